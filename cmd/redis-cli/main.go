@@ -0,0 +1,9 @@
+// Command redis-cli is a Redis client with a REPL, in the spirit of the
+// official redis-cli. Everything but the entry point lives in pkg/cli.
+package main
+
+import "github.com/holys/redis-cli/pkg/cli"
+
+func main() {
+	cli.Main()
+}
@@ -3,6 +3,9 @@ module github.com/holys/redis-cli
 go 1.13
 
 require (
+	github.com/alicebob/miniredis/v2 v2.18.0
 	github.com/go-redis/redis v6.15.9+incompatible
+	github.com/itchyny/gojq v0.12.4
 	github.com/peterh/liner v1.2.0
+	go.starlark.net v0.0.0-20200306205701-8dd3e2ee1dd5
 )
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// can tells whether an ACL user may run a command, without running it, and
+// which of the user's rules blocks it.
+// Usage: CAN user command [arg ...]
+func can(args []string) {
+	if len(args) < 2 {
+		fmt.Println("(error) invalid args. Should be CAN user command [arg ...]")
+		return
+	}
+	user := lexer.TrimQuotes(args[0])
+	cmds := make([]string, len(args)-1)
+	for i, a := range args[1:] {
+		cmds[i] = lexer.TrimQuotes(a)
+	}
+	cliConnect()
+	if serverVersion != "" && compareVersions(serverVersion, "7.0") < 0 {
+		fmt.Printf("(error) CAN needs ACL DRYRUN, Redis 7.0 or later, the server runs %s\n", serverVersion)
+		return
+	}
+
+	ok, reason, err := aclDryRun(user, cmds)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	line := strings.ToUpper(cmds[0])
+	if len(cmds) > 1 {
+		line += " " + strings.Join(cmds[1:], " ")
+	}
+
+	rules := replyFields(client.Do("ACL", "GETUSER", user).Val())
+	if ok {
+		fmt.Printf("%s can run %s\n", user, line)
+		if hasRule(rules["flags"], "off") {
+			fmt.Println("  but the user is off, it can't authenticate")
+		}
+		return
+	}
+	fmt.Printf("%s can't run %s\n", user, line)
+	for _, l := range explainDenial(reason, rules) {
+		fmt.Printf("  %s\n", l)
+	}
+}
+
+// denialPattern matches the reasons of ACL DRYRUN:
+// User alice has no permissions to access the 'foo' key
+var denialPattern = regexp.MustCompile(`no permissions to (?:run|access) the '(.*)' (command|key|channel)`)
+
+// explainDenial turns an ACL DRYRUN reason into what was refused and the
+// rules of the user that refuse it.
+func explainDenial(reason string, rules map[string]interface{}) []string {
+	m := denialPattern.FindStringSubmatch(reason)
+	if m == nil {
+		return []string{reason}
+	}
+	what, kind := m[1], m[2]
+	switch kind {
+	case "command":
+		lines := []string{fmt.Sprintf("blocked: the command %s", strings.ToUpper(what))}
+		commands := ruleString(rules["commands"])
+		if commands == "" {
+			return lines
+		}
+		if rule := blockingRule(commands, what); rule != "" {
+			lines = append(lines, fmt.Sprintf("by rule: %s", rule))
+		} else {
+			lines = append(lines, "by rule: none grants it")
+		}
+		return append(lines, fmt.Sprintf("command rules: %s", commands))
+	case "key":
+		lines := []string{fmt.Sprintf("blocked: the key %s", what)}
+		if keys := ruleString(rules["keys"]); keys != "" {
+			lines = append(lines, fmt.Sprintf("by rule: none of the key patterns %s allows it", keys))
+		} else if rules != nil {
+			lines = append(lines, "by rule: the user has no key patterns (resetkeys)")
+		}
+		return lines
+	default:
+		lines := []string{fmt.Sprintf("blocked: the channel %s", what)}
+		if channels := ruleString(rules["channels"]); channels != "" {
+			lines = append(lines, fmt.Sprintf("by rule: none of the channel patterns %s allows it", channels))
+		} else if rules != nil {
+			lines = append(lines, "by rule: the user has no channel patterns (resetchannels)")
+		}
+		return lines
+	}
+}
+
+// blockingRule returns the last rule of commands that removes command,
+// either by name or by one of its categories.
+func blockingRule(commands, command string) string {
+	command = strings.ToLower(command)
+	fields := strings.Fields(commands)
+	for i := len(fields) - 1; i >= 0; i-- {
+		rule := strings.ToLower(fields[i])
+		switch {
+		case rule == "-"+command || strings.HasPrefix(rule, "-"+command+"|"):
+			return fields[i]
+		case rule == "-@all":
+			return fields[i]
+		case strings.HasPrefix(rule, "-@"):
+			members, err := client.Do("ACL", "CAT", rule[2:]).Result()
+			if err != nil {
+				continue
+			}
+			for _, c := range replyStrings(members) {
+				if c == command {
+					return fields[i]
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// ruleString formats a rule field of ACL GETUSER, a string since Redis 7.0
+// and an array before.
+func ruleString(v interface{}) string {
+	if arr, ok := v.([]interface{}); ok {
+		return strings.Join(replyStrings(arr), " ")
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+// hasRule reports whether an ACL GETUSER flags reply holds flag.
+func hasRule(flags interface{}, flag string) bool {
+	for _, f := range replyStrings(flags) {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// aclDryRun asks the server, with ACL DRYRUN, whether user may run cmds,
+// returning the reason when it may not.
+func aclDryRun(user string, cmds []string) (bool, string, error) {
+	args := []interface{}{"ACL", "DRYRUN", user}
+	for _, c := range cmds {
+		args = append(args, c)
+	}
+	r, err := client.Do(args...).Result()
+	if err != nil {
+		return false, "", err
+	}
+	if s := fmt.Sprint(r); s != "OK" {
+		return false, s, nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// authenticate handles AUTH on the client side: AUTH sent through the
+// pool would only authenticate the connection it went out on, so new pool
+// connections and reconnects would still use the old credentials.
+// Usage: AUTH [username] password
+func authenticate(args []string) {
+	var err error
+	switch len(args) {
+	case 1:
+		err = useCredentials("", lexer.TrimQuotes(args[0]))
+	case 2:
+		err = useCredentials(lexer.TrimQuotes(args[0]), lexer.TrimQuotes(args[1]))
+	default:
+		fmt.Println("(error) ERR wrong number of arguments for 'auth' command")
+		return
+	}
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Println("OK")
+}
+
+// hello sends HELLO, handling its AUTH option like the AUTH command.
+// Usage: HELLO [protover] [AUTH username password] [SETNAME name]
+func hello(args []string) {
+	cmds := []string{"HELLO"}
+	for i := 0; i < len(args); i++ {
+		if strings.ToLower(args[i]) == "auth" && i+2 < len(args) {
+			if err := useCredentials(lexer.TrimQuotes(args[i+1]), lexer.TrimQuotes(args[i+2])); err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			i += 2
+			continue
+		}
+		cmds = append(cmds, args[i])
+	}
+	cliSendCommand(cmds...)
+}
+
+// useCredentials builds a client with the given credentials and keeps it,
+// and them, once they work.
+func useCredentials(username, password string) error {
+	opt := clientOptions(addr(), password)
+	opt.Username = username
+
+	c := conn.New(opt)
+	if err := c.Ping().Err(); err != nil {
+		c.Close()
+		return err
+	}
+
+	if client != nil {
+		client.Close()
+	}
+	client = c
+	*user, *auth = username, password
+	return nil
+}
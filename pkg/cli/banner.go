@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"fmt"
+)
+
+// showServerBanner prints a short summary of the connected server.
+func showServerBanner() {
+	srv, err := fetchInfo()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	fmt.Printf("Connected to %s\n", addr())
+	fmt.Printf("  Server:  Redis %s (%s mode)\n", srv.Get("redis_version"), srv.Get("redis_mode"))
+	fmt.Printf("  Role:    %s\n", srv.Get("role"))
+	fmt.Printf("  Keys:    %d\n", srv.TotalKeys())
+	fmt.Printf("  Memory:  %s\n", srv.Get("used_memory_human"))
+	fmt.Println()
+}
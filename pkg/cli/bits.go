@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+const (
+	bitsPerRow = 64
+	// bitsMaxBytes caps what BITS prints when no range is given.
+	bitsMaxBytes = 1024
+)
+
+// bitsView prints a bitmap as rows of bits with their offsets, followed
+// by a BITCOUNT summary. start and end are byte offsets, as for BITCOUNT.
+// Usage: BITS key [start end]
+func bitsView(args []string) {
+	if len(args) != 1 && len(args) != 3 {
+		fmt.Println("(error) invalid args. Should be BITS key [start end]")
+		return
+	}
+	cliConnect()
+
+	key := lexer.TrimQuotes(args[0])
+	size, err := client.StrLen(key).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if size == 0 {
+		fmt.Println("(empty bitmap)")
+		return
+	}
+
+	start, end := int64(0), size-1
+	truncated := false
+	if len(args) == 3 {
+		s, err1 := strconv.ParseInt(args[1], 10, 64)
+		e, err2 := strconv.ParseInt(args[2], 10, 64)
+		if err1 != nil || err2 != nil {
+			fmt.Println("(error) invalid args. Should be BITS key [start end]")
+			return
+		}
+		start, end = byteIndex(s, size), byteIndex(e, size)
+	} else if size > bitsMaxBytes {
+		end, truncated = bitsMaxBytes-1, true
+	}
+	if start > end {
+		fmt.Println("(empty range)")
+		return
+	}
+
+	data, err := client.GetRange(key, start, end).Result()
+	if err != nil && err != redis.Nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	var row strings.Builder
+	for i := 0; i < len(data)*8; i++ {
+		if i%bitsPerRow == 0 {
+			if row.Len() > 0 {
+				fmt.Println(row.String())
+				row.Reset()
+			}
+			fmt.Fprintf(&row, "%8d  ", start*8+int64(i))
+		} else if i%8 == 0 {
+			row.WriteByte(' ')
+		}
+		if data[i/8]&(0x80>>uint(i%8)) != 0 {
+			row.WriteByte('1')
+		} else {
+			row.WriteByte('0')
+		}
+	}
+	fmt.Println(row.String())
+
+	count, err := client.BitCount(key, &redis.BitCount{Start: start, End: end}).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	total, err := client.BitCount(key, nil).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("bits %d-%d: %d set of %d\n", start*8, end*8+7, count, (end-start+1)*8)
+	fmt.Printf("whole key: %d set of %d (%.2f%%)\n", total, size*8, float64(total)*100/float64(size*8))
+	if truncated {
+		fmt.Printf("showing the first %d bytes, pass a range to see more\n", bitsMaxBytes)
+	}
+}
+
+// byteIndex resolves a possibly negative byte offset against size,
+// clamping it to the string.
+func byteIndex(i, size int64) int64 {
+	if i < 0 {
+		i += size
+	}
+	if i < 0 {
+		return 0
+	}
+	if i >= size {
+		return size - 1
+	}
+	return i
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// version and commit are set at build time, see the Makefile:
+// -ldflags "-X github.com/holys/redis-cli/pkg/cli.version=v1.2.3
+// -X github.com/holys/redis-cli/pkg/cli.commit=abc1234"
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// buildInfo describes the build of the CLI for bug reports.
+func buildInfo() string {
+	v, goRedis := version, "unknown"
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if v == "dev" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			v = bi.Main.Version
+		}
+		for _, dep := range bi.Deps {
+			if dep.Path == "github.com/go-redis/redis" {
+				goRedis = strings.TrimSuffix(dep.Version, "+incompatible")
+			}
+		}
+	}
+
+	details := []string{}
+	if commit != "" {
+		details = append(details, "commit "+commit)
+	}
+	details = append(details, "go-redis "+goRedis, runtime.Version()+" "+runtime.GOOS+"/"+runtime.GOARCH)
+	return fmt.Sprintf("redis-cli %s (%s)", v, strings.Join(details, ", "))
+}
+
+// printVersion prints the client build and the version of the connected
+// server.
+// Usage: VERSION
+func printVersion() {
+	fmt.Printf("client: %s\n", buildInfo())
+
+	cliConnect()
+	srv, err := fetchInfo("server")
+	if err != nil {
+		fmt.Printf("server: (error) %s\n", err.Error())
+		return
+	}
+	details := []string{}
+	for _, field := range []string{"redis_mode", "redis_git_sha1", "os"} {
+		if v := srv.Get(field); v != "" && strings.Trim(v, "0") != "" {
+			details = append(details, v)
+		}
+	}
+	fmt.Printf("server: redis %s (%s)\n", srv.Get("redis_version"), strings.Join(details, ", "))
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// copyReply places the raw text of a reply on the clipboard.
+// Usage: <command> | copy
+func copyReply(reply interface{}) {
+	text := format.Sprint(reply, format.Raw)
+	how, err := copyText(text)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Printf("copied %d bytes to the clipboard (%s)\n", len(text), how)
+}
+
+// clipboardCommands are the clipboard tools tried in order, with the
+// condition under which they can reach the user's clipboard.
+var clipboardCommands = []struct {
+	name string
+	args []string
+	ok   func() bool
+}{
+	{"pbcopy", nil, func() bool { return runtime.GOOS == "darwin" }},
+	{"clip.exe", nil, func() bool { return runtime.GOOS == "windows" || os.Getenv("WSL_DISTRO_NAME") != "" }},
+	{"wl-copy", nil, func() bool { return os.Getenv("WAYLAND_DISPLAY") != "" }},
+	{"xclip", []string{"-selection", "clipboard"}, func() bool { return os.Getenv("DISPLAY") != "" }},
+	{"xsel", []string{"--clipboard", "--input"}, func() bool { return os.Getenv("DISPLAY") != "" }},
+}
+
+// copyText places text on the clipboard and returns how it did so. Over
+// SSH, in the web terminal or when no clipboard tool is found, it falls
+// back to OSC 52, which asks the terminal itself to set the clipboard.
+func copyText(text string) (string, error) {
+	remote := os.Getenv("SSH_TTY") != "" || *showWelcome
+	if !remote {
+		for _, c := range clipboardCommands {
+			if !c.ok() {
+				continue
+			}
+			path, err := exec.LookPath(c.name)
+			if err != nil {
+				continue
+			}
+			cmd := exec.Command(path, c.args...)
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err != nil {
+				return "", fmt.Errorf("%s: %v", c.name, err)
+			}
+			return c.name, nil
+		}
+	}
+
+	fmt.Print(osc52(text))
+	return "OSC 52", nil
+}
+
+// osc52 returns the escape sequence setting the clipboard to text,
+// wrapped for tmux and screen so it reaches the outer terminal.
+func osc52(text string) string {
+	seq := "\033]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\a"
+	switch {
+	case os.Getenv("TMUX") != "":
+		return "\033Ptmux;\033" + seq + "\033\\"
+	case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+		return "\033P" + seq + "\033\\"
+	}
+	return seq
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+)
+
+// clusterNode is one line of CLUSTER NODES.
+type clusterNode struct {
+	ID        string
+	Addr      string
+	Flags     []string
+	MasterID  string
+	LinkState string
+	Slots     [][2]int
+	// Migrating and Importing map a slot to the peer node ID.
+	Migrating map[int]string
+	Importing map[int]string
+}
+
+// HasFlag reports whether the node has the given flag, e.g. master.
+func (n *clusterNode) HasFlag(flag string) bool {
+	for _, f := range n.Flags {
+		if f == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// Role returns master or replica.
+func (n *clusterNode) Role() string {
+	if n.HasFlag("master") {
+		return "master"
+	}
+	return "replica"
+}
+
+// SlotCount returns the number of slots served by the node.
+func (n *clusterNode) SlotCount() int {
+	count := 0
+	for _, r := range n.Slots {
+		count += r[1] - r[0] + 1
+	}
+	return count
+}
+
+// parseClusterNodes parses the reply of CLUSTER NODES.
+func parseClusterNodes(s string) []*clusterNode {
+	var nodes []*clusterNode
+	for _, l := range strings.Split(s, "\n") {
+		fields := strings.Fields(l)
+		if len(fields) < 8 {
+			continue
+		}
+
+		// ip:port@cport[,hostname]
+		nodeAddr := fields[1]
+		if i := strings.IndexAny(nodeAddr, "@,"); i >= 0 {
+			nodeAddr = nodeAddr[:i]
+		}
+		n := &clusterNode{
+			ID:        fields[0],
+			Addr:      nodeAddr,
+			Flags:     strings.Split(fields[2], ","),
+			MasterID:  strings.Trim(fields[3], "-"),
+			LinkState: fields[7],
+			Migrating: map[int]string{},
+			Importing: map[int]string{},
+		}
+
+		for _, slot := range fields[8:] {
+			if strings.HasPrefix(slot, "[") {
+				// [slot->-node] or [slot-<-node]
+				slot = strings.Trim(slot, "[]")
+				if i := strings.Index(slot, "->-"); i >= 0 {
+					s, _ := strconv.Atoi(slot[:i])
+					n.Migrating[s] = slot[i+3:]
+				} else if i := strings.Index(slot, "-<-"); i >= 0 {
+					s, _ := strconv.Atoi(slot[:i])
+					n.Importing[s] = slot[i+3:]
+				}
+				continue
+			}
+
+			bounds := strings.SplitN(slot, "-", 2)
+			start, err := strconv.Atoi(bounds[0])
+			if err != nil {
+				continue
+			}
+			end := start
+			if len(bounds) == 2 {
+				end, _ = strconv.Atoi(bounds[1])
+			}
+			n.Slots = append(n.Slots, [2]int{start, end})
+		}
+		nodes = append(nodes, n)
+	}
+	return nodes
+}
+
+// fetchClusterNodes runs CLUSTER NODES and parses the reply.
+func fetchClusterNodes() ([]*clusterNode, error) {
+	s, err := client.ClusterNodes().Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseClusterNodes(s), nil
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/info"
+)
+
+type nodeHealth struct {
+	latency time.Duration
+	memory  string
+	err     error
+}
+
+// clusterHealth prints a matrix of every cluster node with its role,
+// slots, PING latency, memory and link state.
+// Usage: CLUSTER-HEALTH [--watch [seconds]]
+func clusterHealth(args []string) {
+	rest, interval, err := parseWatch(args, 2*time.Second)
+	if err != nil || len(rest) != 0 {
+		fmt.Println("(error) invalid args. Should be CLUSTER-HEALTH [--watch [seconds]]")
+		return
+	}
+	cliConnect()
+
+	if interval > 0 {
+		watchLoop(interval, printClusterHealth)
+		return
+	}
+	printClusterHealth()
+}
+
+func printClusterHealth() {
+	nodes, err := fetchClusterNodes()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	// probe every node concurrently
+	var mu sync.Mutex
+	health := map[string]nodeHealth{}
+	client.ForEachNode(func(c *redis.Client) error {
+		var h nodeHealth
+		start := time.Now()
+		if h.err = c.Ping().Err(); h.err == nil {
+			h.latency = time.Since(start)
+			if s, err := c.Info("memory").Result(); err == nil {
+				h.memory = info.Parse(s).Get("used_memory_human")
+			}
+		}
+		mu.Lock()
+		health[c.Options().Addr] = h
+		mu.Unlock()
+		return nil
+	})
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Addr < nodes[j].Addr
+	})
+	var rows [][]string
+	for _, n := range nodes {
+		latency, memory := "-", "-"
+		if h, ok := health[n.Addr]; ok {
+			if h.err != nil {
+				latency = "error: " + h.err.Error()
+			} else {
+				latency = h.latency.Round(10 * time.Microsecond).String()
+				memory = h.memory
+			}
+		}
+
+		role := n.Role()
+		if n.HasFlag("fail") {
+			role += " (fail)"
+		} else if n.HasFlag("fail?") {
+			role += " (pfail)"
+		}
+		rows = append(rows, []string{n.Addr, role, strconv.Itoa(n.SlotCount()), latency, memory, n.LinkState})
+	}
+	printTable([]string{"NODE", "ROLE", "SLOTS", "LATENCY", "MEMORY", "LINK"}, rows)
+}
@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"sort"
+	"strings"
+)
+
+// commandDoc documents a Redis command or subcommand for HELP.
+type commandDoc struct {
+	Name       string
+	Args       string
+	Summary    string
+	Group      string
+	Since      string
+	Complexity string
+	Example    string
+}
+
+// commandDocs is the command reference, sorted by name. Subcommands such
+// as "CLIENT INFO" have entries of their own.
+var commandDocs = []commandDoc{
+	{"ACL", "subcommand [arg ...]", "A container for Access List Control commands", "server", "6.0.0", "Depends on subcommand", ""},
+	{"ACL CAT", "[category]", "List the ACL categories or the commands inside a category", "server", "6.0.0", "O(1)", "ACL CAT dangerous"},
+	{"ACL DELUSER", "username [username ...]", "Remove the specified ACL users and the associated rules", "server", "6.0.0", "O(1) amortized time considering the typical user", "ACL DELUSER antirez"},
+	{"ACL DRYRUN", "username command [arg ...]", "Returns whether the user can execute the given command without executing the command", "server", "7.0.0", "O(1)", "ACL DRYRUN virginia SET foo bar"},
+	{"ACL GETUSER", "username", "Get the rules for a specific ACL user", "server", "6.0.0", "O(N) where N is the number of password, command and pattern rules that the user has", "ACL GETUSER default"},
+	{"ACL LIST", "", "List the current ACL rules in ACL config file format", "server", "6.0.0", "O(N) where N is the number of configured users", ""},
+	{"ACL LOG", "[count|RESET]", "List latest events denied because of ACLs in place", "server", "6.0.0", "O(N) with N being the number of entries shown", "ACL LOG 10"},
+	{"ACL SETUSER", "username [rule [rule ...]]", "Modify or create the rules for a specific ACL user", "server", "6.0.0", "O(N) where N is the number of rules provided", "ACL SETUSER alice on >p1pp0 ~cached:* +get"},
+	{"ACL USERS", "", "List the username of all the configured ACL rules", "server", "6.0.0", "O(N) where N is the number of configured users", ""},
+	{"ACL WHOAMI", "", "Return the name of the user associated to the current connection", "server", "6.0.0", "O(1)", ""},
+	{"APPEND", "key value", "Append a value to a key", "string", "2.0.0", "O(1)", "APPEND greeting \" World\""},
+	{"AUTH", "[username] password", "Authenticate to the server", "connection", "1.0.0", "O(N) where N is the number of passwords defined for the user", "AUTH alice p1pp0"},
+	{"BGREWRITEAOF", "", "Asynchronously rewrite the append-only file", "server", "1.0.0", "O(1)", ""},
+	{"BGSAVE", "[SCHEDULE]", "Asynchronously save the dataset to disk", "server", "1.0.0", "O(1)", ""},
+	{"BITCOUNT", "key [start end [BYTE|BIT]]", "Count set bits in a string", "bitmap", "2.6.0", "O(N)", "BITCOUNT visits 0 -1"},
+	{"BITFIELD", "key [GET encoding offset|[OVERFLOW WRAP|SAT|FAIL] SET encoding offset value|INCRBY encoding offset increment ...]", "Perform arbitrary bitfield integer operations on strings", "bitmap", "3.2.0", "O(1) for each subcommand specified", "BITFIELD counters INCRBY u8 0 1"},
+	{"BITFIELD_RO", "key [GET encoding offset ...]", "Perform arbitrary bitfield integer operations on strings. Read-only variant of BITFIELD", "bitmap", "6.0.0", "O(1) for each subcommand specified", "BITFIELD_RO counters GET u8 0"},
+	{"BITOP", "AND|OR|XOR|NOT destkey key [key ...]", "Perform bitwise operations between strings", "bitmap", "2.6.0", "O(N)", "BITOP AND both visits:mon visits:tue"},
+	{"BITPOS", "key bit [start [end [BYTE|BIT]]]", "Find first bit set or clear in a string", "bitmap", "2.8.7", "O(N)", "BITPOS visits 1"},
+	{"BLMOVE", "source destination LEFT|RIGHT LEFT|RIGHT timeout", "Pop an element from a list, push it to another list and return it; or block until one is available", "list", "6.2.0", "O(1)", "BLMOVE jobs working RIGHT LEFT 5"},
+	{"BLMPOP", "timeout numkeys key [key ...] LEFT|RIGHT [COUNT count]", "Pop elements from a list, or block until one is available", "list", "7.0.0", "O(N+M) where N is the number of provided keys and M is the number of elements returned", "BLMPOP 5 2 jobs:high jobs:low LEFT"},
+	{"BLPOP", "key [key ...] timeout", "Remove and get the first element in a list, or block until one is available", "list", "2.0.0", "O(N) where N is the number of provided keys", "BLPOP jobs 5"},
+	{"BRPOP", "key [key ...] timeout", "Remove and get the last element in a list, or block until one is available", "list", "2.0.0", "O(N) where N is the number of provided keys", "BRPOP jobs 5"},
+	{"BRPOPLPUSH", "source destination timeout", "Pop an element from a list, push it to another list and return it; or block until one is available", "list", "2.2.0", "O(1)", ""},
+	{"BZMPOP", "timeout numkeys key [key ...] MIN|MAX [COUNT count]", "Remove and return members with scores in a sorted set or block until one is available", "sorted-set", "7.0.0", "O(K) + O(M*log(N)) where K is the number of provided keys, N the number of elements in the sorted set and M the number of elements popped", ""},
+	{"BZPOPMAX", "key [key ...] timeout", "Remove and return the member with the highest score from one or more sorted sets, or block until one is available", "sorted-set", "5.0.0", "O(log(N)) with N being the number of elements in the sorted set", "BZPOPMAX tasks 5"},
+	{"BZPOPMIN", "key [key ...] timeout", "Remove and return the member with the lowest score from one or more sorted sets, or block until one is available", "sorted-set", "5.0.0", "O(log(N)) with N being the number of elements in the sorted set", "BZPOPMIN tasks 5"},
+	{"CLIENT", "subcommand [arg ...]", "A container for client connection commands", "connection", "2.4.0", "Depends on subcommand", ""},
+	{"CLIENT GETNAME", "", "Get the current connection name", "connection", "2.6.9", "O(1)", ""},
+	{"CLIENT ID", "", "Returns the client ID for the current connection", "connection", "5.0.0", "O(1)", ""},
+	{"CLIENT INFO", "", "Returns information about the current client connection", "connection", "6.2.0", "O(1)", ""},
+	{"CLIENT KILL", "[ID client-id] [TYPE NORMAL|MASTER|REPLICA|PUBSUB] [USER username] [ADDR ip:port] [LADDR ip:port] [SKIPME yes|no]", "Kill the connection of a client", "connection", "2.4.0", "O(N) where N is the number of client connections", "CLIENT KILL ADDR 127.0.0.1:52340"},
+	{"CLIENT LIST", "[TYPE NORMAL|MASTER|REPLICA|PUBSUB] [ID client-id [client-id ...]]", "Get the list of client connections", "connection", "2.4.0", "O(N) where N is the number of client connections", "CLIENT LIST TYPE normal"},
+	{"CLIENT NO-EVICT", "ON|OFF", "Set client eviction mode for the current connection", "connection", "7.0.0", "O(1)", ""},
+	{"CLIENT NO-TOUCH", "ON|OFF", "Controls whether commands sent by the client will alter the LRU/LFU of the keys they access", "connection", "7.2.0", "O(1)", ""},
+	{"CLIENT PAUSE", "timeout [WRITE|ALL]", "Stop processing commands from clients for some time", "connection", "2.9.50", "O(1)", "CLIENT PAUSE 5000 WRITE"},
+	{"CLIENT SETNAME", "connection-name", "Set the current connection name", "connection", "2.6.9", "O(1)", "CLIENT SETNAME worker-1"},
+	{"CLIENT TRACKING", "ON|OFF [REDIRECT client-id] [PREFIX prefix ...] [BCAST] [OPTIN] [OPTOUT] [NOLOOP]", "Enable or disable server assisted client side caching support", "connection", "6.0.0", "O(1)", ""},
+	{"CLIENT UNBLOCK", "client-id [TIMEOUT|ERROR]", "Unblock a client blocked in a blocking command from a different connection", "connection", "5.0.0", "O(log N) where N is the number of client connections", ""},
+	{"CLIENT UNPAUSE", "", "Resume processing of clients that were paused", "connection", "6.2.0", "O(N) where N is the number of paused clients", ""},
+	{"CLUSTER", "subcommand [arg ...]", "A container for cluster commands", "cluster", "3.0.0", "Depends on subcommand", ""},
+	{"CLUSTER COUNTKEYSINSLOT", "slot", "Return the number of local keys in the specified hash slot", "cluster", "3.0.0", "O(1)", "CLUSTER COUNTKEYSINSLOT 7000"},
+	{"CLUSTER FAILOVER", "[FORCE|TAKEOVER]", "Forces a replica to perform a manual failover of its master", "cluster", "3.0.0", "O(1)", ""},
+	{"CLUSTER GETKEYSINSLOT", "slot count", "Return local key names in the specified hash slot", "cluster", "3.0.0", "O(N) where N is the number of requested keys", "CLUSTER GETKEYSINSLOT 7000 10"},
+	{"CLUSTER INFO", "", "Provides info about Redis Cluster node state", "cluster", "3.0.0", "O(1)", ""},
+	{"CLUSTER KEYSLOT", "key", "Returns the hash slot of the specified key", "cluster", "3.0.0", "O(N) where N is the number of bytes in the key", "CLUSTER KEYSLOT user:{42}:profile"},
+	{"CLUSTER MYID", "", "Return the node id", "cluster", "3.0.0", "O(1)", ""},
+	{"CLUSTER NODES", "", "Get Cluster config for the node", "cluster", "3.0.0", "O(N) where N is the total number of Cluster nodes", ""},
+	{"CLUSTER REPLICAS", "node-id", "List replica nodes of the specified master node", "cluster", "5.0.0", "O(1)", ""},
+	{"CLUSTER SHARDS", "", "Get array of cluster slots to node mappings", "cluster", "7.0.0", "O(N) where N is the total number of cluster nodes", ""},
+	{"CLUSTER SLOTS", "", "Get array of Cluster slot to node mappings", "cluster", "3.0.0", "O(N) where N is the total number of Cluster nodes", ""},
+	{"COMMAND", "", "Get array of Redis command details", "server", "2.8.13", "O(N) where N is the total number of Redis commands", ""},
+	{"COMMAND COUNT", "", "Get total number of Redis commands", "server", "2.8.13", "O(1)", ""},
+	{"COMMAND DOCS", "[command-name [command-name ...]]", "Get array of specific Redis command documentation", "server", "7.0.0", "O(N) where N is the number of commands to look up", "COMMAND DOCS SET"},
+	{"COMMAND GETKEYS", "command [arg ...]", "Extract keys given a full Redis command", "server", "2.8.13", "O(N) where N is the number of arguments to the command", "COMMAND GETKEYS MSET a 1 b 2"},
+	{"COMMAND INFO", "[command-name [command-name ...]]", "Get array of specific Redis command details, or all when no argument is given", "server", "2.8.13", "O(N) where N is the number of commands to look up", "COMMAND INFO GET"},
+	{"CONFIG GET", "parameter [parameter ...]", "Get the values of configuration parameters", "server", "2.0.0", "O(N) when N is the number of configuration parameters provided", "CONFIG GET maxmemory*"},
+	{"CONFIG RESETSTAT", "", "Reset the stats returned by INFO", "server", "2.0.0", "O(1)", ""},
+	{"CONFIG REWRITE", "", "Rewrite the configuration file with the in memory configuration", "server", "2.8.0", "O(1)", ""},
+	{"CONFIG SET", "parameter value [parameter value ...]", "Set configuration parameters to the given values", "server", "2.0.0", "O(N) when N is the number of configuration parameters provided", "CONFIG SET maxmemory 2gb"},
+	{"COPY", "source destination [DB destination-db] [REPLACE]", "Copy a key", "generic", "6.2.0", "O(N) worst case for collections, where N is the number of nested items", "COPY config config:backup"},
+	{"DBSIZE", "", "Return the number of keys in the selected database", "server", "1.0.0", "O(1)", ""},
+	{"DEBUG", "subcommand [arg ...]", "A container for debugging commands", "server", "1.0.0", "Depends on subcommand", ""},
+	{"DECR", "key", "Decrement the integer value of a key by one", "string", "1.0.0", "O(1)", "DECR stock:42"},
+	{"DECRBY", "key decrement", "Decrement the integer value of a key by the given number", "string", "1.0.0", "O(1)", "DECRBY stock:42 5"},
+	{"DEL", "key [key ...]", "Delete a key", "generic", "1.0.0", "O(N) where N is the number of keys that will be removed", "DEL session:1 session:2"},
+	{"DISCARD", "", "Discard all commands issued after MULTI", "transactions", "2.0.0", "O(N), when N is the number of queued commands", ""},
+	{"DUMP", "key", "Return a serialized version of the value stored at the specified key", "generic", "2.6.0", "O(1) to access the key and additional O(N*M) to serialize it", ""},
+	{"ECHO", "message", "Echo the given string", "connection", "1.0.0", "O(1)", "ECHO hello"},
+	{"EVAL", "script numkeys [key [key ...]] [arg [arg ...]]", "Execute a Lua script server side", "scripting", "2.6.0", "Depends on the script that is executed", "EVAL \"return redis.call('GET', KEYS[1])\" 1 greeting"},
+	{"EVALSHA", "sha1 numkeys [key [key ...]] [arg [arg ...]]", "Execute a Lua script server side", "scripting", "2.6.0", "Depends on the script that is executed", ""},
+	{"EVALSHA_RO", "sha1 numkeys [key [key ...]] [arg [arg ...]]", "Execute a read-only Lua script server side", "scripting", "7.0.0", "Depends on the script that is executed", ""},
+	{"EVAL_RO", "script numkeys [key [key ...]] [arg [arg ...]]", "Execute a read-only Lua script server side", "scripting", "7.0.0", "Depends on the script that is executed", ""},
+	{"EXEC", "", "Execute all commands issued after MULTI", "transactions", "1.2.0", "Depends on commands in the transaction", ""},
+	{"EXISTS", "key [key ...]", "Determine if a key exists", "generic", "1.0.0", "O(N) where N is the number of keys to check", "EXISTS session:1"},
+	{"EXPIRE", "key seconds [NX|XX|GT|LT]", "Set a key's time to live in seconds", "generic", "1.0.0", "O(1)", "EXPIRE session:1 3600"},
+	{"EXPIREAT", "key unix-time-seconds [NX|XX|GT|LT]", "Set the expiration for a key as a UNIX timestamp", "generic", "1.2.0", "O(1)", "EXPIREAT session:1 1893456000"},
+	{"EXPIRETIME", "key", "Get the expiration Unix timestamp for a key", "generic", "7.0.0", "O(1)", ""},
+	{"FAILOVER", "[TO host port [FORCE]] [ABORT] [TIMEOUT milliseconds]", "Start a coordinated failover between this server and one of its replicas", "server", "6.2.0", "O(1)", ""},
+	{"FCALL", "function numkeys [key [key ...]] [arg [arg ...]]", "Invoke a function", "scripting", "7.0.0", "Depends on the function that is executed", ""},
+	{"FCALL_RO", "function numkeys [key [key ...]] [arg [arg ...]]", "Invoke a read-only function", "scripting", "7.0.0", "Depends on the function that is executed", ""},
+	{"FLUSHALL", "[ASYNC|SYNC]", "Remove all keys from all databases", "server", "1.0.0", "O(N) where N is the total number of keys in all databases", ""},
+	{"FLUSHDB", "[ASYNC|SYNC]", "Remove all keys from the current database", "server", "1.0.0", "O(N) where N is the number of keys in the selected database", ""},
+	{"FUNCTION", "subcommand [arg ...]", "A container for function commands", "scripting", "7.0.0", "Depends on subcommand", ""},
+	{"GEOADD", "key [NX|XX] [CH] longitude latitude member [longitude latitude member ...]", "Add one or more geospatial items in the geospatial index represented using a sorted set", "geo", "3.2.0", "O(log(N)) for each item added", "GEOADD places 13.361389 38.115556 Palermo"},
+	{"GEODIST", "key member1 member2 [M|KM|FT|MI]", "Returns the distance between two members of a geospatial index", "geo", "3.2.0", "O(log(N))", "GEODIST places Palermo Catania km"},
+	{"GEOHASH", "key [member [member ...]]", "Returns members of a geospatial index as standard geohash strings", "geo", "3.2.0", "O(log(N)) for each member requested", ""},
+	{"GEOPOS", "key [member [member ...]]", "Returns longitude and latitude of members of a geospatial index", "geo", "3.2.0", "O(N) where N is the number of members requested", "GEOPOS places Palermo"},
+	{"GEORADIUS", "key longitude latitude radius M|KM|FT|MI [WITHCOORD] [WITHDIST] [WITHHASH] [COUNT count [ANY]] [ASC|DESC]", "Query a sorted set representing a geospatial index to fetch members matching a given maximum distance from a point", "geo", "3.2.0", "O(N+log(M)) where N is the number of elements inside the bounding box and M is the number of items inside the index", ""},
+	{"GEORADIUSBYMEMBER", "key member radius M|KM|FT|MI [WITHCOORD] [WITHDIST] [WITHHASH] [COUNT count [ANY]] [ASC|DESC]", "Query a sorted set representing a geospatial index to fetch members matching a given maximum distance from a member", "geo", "3.2.0", "O(N+log(M)) where N is the number of elements inside the bounding box and M is the number of items inside the index", ""},
+	{"GEOSEARCH", "key FROMMEMBER member|FROMLONLAT longitude latitude BYRADIUS radius M|KM|FT|MI|BYBOX width height M|KM|FT|MI [ASC|DESC] [COUNT count [ANY]] [WITHCOORD] [WITHDIST] [WITHHASH]", "Query a sorted set representing a geospatial index to fetch members inside an area of a box or a circle", "geo", "6.2.0", "O(N+log(M)) where N is the number of elements in the grid-aligned bounding box area and M is the number of items inside the shape", "GEOSEARCH places FROMLONLAT 15 37 BYRADIUS 200 km ASC"},
+	{"GEOSEARCHSTORE", "destination source FROMMEMBER member|FROMLONLAT longitude latitude BYRADIUS radius M|KM|FT|MI|BYBOX width height M|KM|FT|MI [ASC|DESC] [COUNT count [ANY]] [STOREDIST]", "Query a sorted set representing a geospatial index to fetch members inside an area of a box or a circle, and store the result in another key", "geo", "6.2.0", "O(N+log(M)) where N is the number of elements in the grid-aligned bounding box area and M is the number of items inside the shape", ""},
+	{"GET", "key", "Get the value of a key", "string", "1.0.0", "O(1)", "GET greeting"},
+	{"GETBIT", "key offset", "Returns the bit value at offset in the string value stored at key", "bitmap", "2.2.0", "O(1)", "GETBIT visits 7"},
+	{"GETDEL", "key", "Get the value of a key and delete the key", "string", "6.2.0", "O(1)", "GETDEL token:42"},
+	{"GETEX", "key [EX seconds|PX milliseconds|EXAT unix-time-seconds|PXAT unix-time-milliseconds|PERSIST]", "Get the value of a key and optionally set its expiration", "string", "6.2.0", "O(1)", "GETEX session:1 EX 3600"},
+	{"GETRANGE", "key start end", "Get a substring of the string stored at a key", "string", "2.4.0", "O(N) where N is the length of the returned string", "GETRANGE greeting 0 4"},
+	{"GETSET", "key value", "Set the string value of a key and return its old value", "string", "1.0.0", "O(1)", ""},
+	{"HDEL", "key field [field ...]", "Delete one or more hash fields", "hash", "2.0.0", "O(N) where N is the number of fields to be removed", "HDEL user:42 email"},
+	{"HELLO", "[protover [AUTH username password] [SETNAME clientname]]", "Handshake with Redis", "connection", "6.0.0", "O(1)", "HELLO 2 AUTH alice p1pp0"},
+	{"HEXISTS", "key field", "Determine if a hash field exists", "hash", "2.0.0", "O(1)", "HEXISTS user:42 email"},
+	{"HEXPIRE", "key seconds [NX|XX|GT|LT] FIELDS numfields field [field ...]", "Set expiry for hash fields using relative time to expire (seconds)", "hash", "7.4.0", "O(N) where N is the number of specified fields", "HEXPIRE user:42 60 FIELDS 1 otp"},
+	{"HEXPIRETIME", "key FIELDS numfields field [field ...]", "Returns the expiration time of hash fields as a Unix timestamp, in seconds", "hash", "7.4.0", "O(N) where N is the number of specified fields", ""},
+	{"HGET", "key field", "Get the value of a hash field", "hash", "2.0.0", "O(1)", "HGET user:42 name"},
+	{"HGETALL", "key", "Get all the fields and values in a hash", "hash", "2.0.0", "O(N) where N is the size of the hash", "HGETALL user:42"},
+	{"HINCRBY", "key field increment", "Increment the integer value of a hash field by the given number", "hash", "2.0.0", "O(1)", "HINCRBY user:42 logins 1"},
+	{"HINCRBYFLOAT", "key field increment", "Increment the float value of a hash field by the given amount", "hash", "2.6.0", "O(1)", "HINCRBYFLOAT user:42 balance 10.5"},
+	{"HKEYS", "key", "Get all the fields in a hash", "hash", "2.0.0", "O(N) where N is the size of the hash", "HKEYS user:42"},
+	{"HLEN", "key", "Get the number of fields in a hash", "hash", "2.0.0", "O(1)", "HLEN user:42"},
+	{"HMGET", "key field [field ...]", "Get the values of all the given hash fields", "hash", "2.0.0", "O(N) where N is the number of fields being requested", "HMGET user:42 name email"},
+	{"HMSET", "key field value [field value ...]", "Set multiple hash fields to multiple values", "hash", "2.0.0", "O(N) where N is the number of fields being set", ""},
+	{"HPERSIST", "key FIELDS numfields field [field ...]", "Removes the expiration time for each specified field", "hash", "7.4.0", "O(N) where N is the number of specified fields", ""},
+	{"HPEXPIRE", "key milliseconds [NX|XX|GT|LT] FIELDS numfields field [field ...]", "Set expiry for hash fields using relative time to expire (milliseconds)", "hash", "7.4.0", "O(N) where N is the number of specified fields", ""},
+	{"HPTTL", "key FIELDS numfields field [field ...]", "Returns the TTL in milliseconds of hash fields", "hash", "7.4.0", "O(N) where N is the number of specified fields", ""},
+	{"HRANDFIELD", "key [count [WITHVALUES]]", "Get one or multiple random fields from a hash", "hash", "6.2.0", "O(N) where N is the number of fields returned", "HRANDFIELD user:42 2 WITHVALUES"},
+	{"HSCAN", "key cursor [MATCH pattern] [COUNT count]", "Incrementally iterate hash fields and associated values", "hash", "2.8.0", "O(1) for every call. O(N) for a complete iteration", "HSCAN user:42 0 MATCH addr*"},
+	{"HSET", "key field value [field value ...]", "Set the string value of a hash field", "hash", "2.0.0", "O(1) for each field/value pair added", "HSET user:42 name Ada email ada@example.com"},
+	{"HSETNX", "key field value", "Set the value of a hash field, only if the field does not exist", "hash", "2.0.0", "O(1)", "HSETNX user:42 created 1700000000"},
+	{"HSTRLEN", "key field", "Get the length of the value of a hash field", "hash", "3.2.0", "O(1)", "HSTRLEN user:42 name"},
+	{"HTTL", "key FIELDS numfields field [field ...]", "Returns the TTL in seconds of hash fields", "hash", "7.4.0", "O(N) where N is the number of specified fields", "HTTL user:42 FIELDS 1 otp"},
+	{"HVALS", "key", "Get all the values in a hash", "hash", "2.0.0", "O(N) where N is the size of the hash", "HVALS user:42"},
+	{"INCR", "key", "Increment the integer value of a key by one", "string", "1.0.0", "O(1)", "INCR page:views"},
+	{"INCRBY", "key increment", "Increment the integer value of a key by the given amount", "string", "1.0.0", "O(1)", "INCRBY page:views 10"},
+	{"INCRBYFLOAT", "key increment", "Increment the float value of a key by the given amount", "string", "2.6.0", "O(1)", "INCRBYFLOAT temperature 0.5"},
+	{"INFO", "[section [section ...]]", "Get information and statistics about the server", "server", "1.0.0", "O(1)", "INFO memory"},
+	{"KEYS", "pattern", "Find all keys matching the given pattern", "generic", "1.0.0", "O(N) with N being the number of keys in the database", "KEYS user:*"},
+	{"LASTSAVE", "", "Get the UNIX time stamp of the last successful save to disk", "server", "1.0.0", "O(1)", ""},
+	{"LATENCY", "subcommand [arg ...]", "A container for latency diagnostics commands", "server", "2.8.13", "Depends on subcommand", ""},
+	{"LATENCY HISTOGRAM", "[command [command ...]]", "Return the cumulative distribution of latencies of a subset of commands or all", "server", "7.0.0", "O(N) where N is the number of commands with latency information being retrieved", ""},
+	{"LATENCY HISTORY", "event", "Return timestamp-latency samples for the event", "server", "2.8.13", "O(1)", "LATENCY HISTORY command"},
+	{"LATENCY LATEST", "", "Return the latest latency samples for all events", "server", "2.8.13", "O(1)", ""},
+	{"LATENCY RESET", "[event [event ...]]", "Reset latency data for one or more events", "server", "2.8.13", "O(1)", ""},
+	{"LCS", "key1 key2 [LEN] [IDX] [MINMATCHLEN len] [WITHMATCHLEN]", "Find longest common substring", "string", "7.0.0", "O(N*M) where N and M are the lengths of s1 and s2", "LCS key1 key2 LEN"},
+	{"LINDEX", "key index", "Get an element from a list by its index", "list", "1.0.0", "O(N) where N is the number of elements to traverse to get to the element at index", "LINDEX jobs 0"},
+	{"LINSERT", "key BEFORE|AFTER pivot element", "Insert an element before or after another element in a list", "list", "2.2.0", "O(N) where N is the number of elements to traverse before seeing the value pivot", "LINSERT jobs BEFORE j2 j1"},
+	{"LLEN", "key", "Get the length of a list", "list", "1.0.0", "O(1)", "LLEN jobs"},
+	{"LMOVE", "source destination LEFT|RIGHT LEFT|RIGHT", "Pop an element from a list, push it to another list and return it", "list", "6.2.0", "O(1)", "LMOVE jobs working RIGHT LEFT"},
+	{"LMPOP", "numkeys key [key ...] LEFT|RIGHT [COUNT count]", "Pop elements from a list", "list", "7.0.0", "O(N+M) where N is the number of provided keys and M is the number of elements returned", "LMPOP 2 jobs:high jobs:low LEFT COUNT 10"},
+	{"LOLWUT", "[VERSION version]", "Display some computer art and the Redis version", "server", "5.0.0", "O(1)", ""},
+	{"LPOP", "key [count]", "Remove and get the first elements in a list", "list", "1.0.0", "O(N) where N is the number of elements returned", "LPOP jobs"},
+	{"LPOS", "key element [RANK rank] [COUNT num-matches] [MAXLEN len]", "Return the index of matching elements on a list", "list", "6.0.6", "O(N) where N is the number of elements in the list", "LPOS jobs j42"},
+	{"LPUSH", "key element [element ...]", "Prepend one or multiple elements to a list", "list", "1.0.0", "O(1) for each element added", "LPUSH jobs j1 j2"},
+	{"LPUSHX", "key element [element ...]", "Prepend an element to a list, only if the list exists", "list", "2.2.0", "O(1) for each element added", ""},
+	{"LRANGE", "key start stop", "Get a range of elements from a list", "list", "1.0.0", "O(S+N) where S is the distance of start offset from HEAD for small lists and N is the number of elements in the specified range", "LRANGE jobs 0 -1"},
+	{"LREM", "key count element", "Remove elements from a list", "list", "1.0.0", "O(N+M) where N is the length of the list and M is the number of elements removed", "LREM jobs 0 j42"},
+	{"LSET", "key index element", "Set the value of an element in a list by its index", "list", "1.0.0", "O(N) where N is the length of the list", "LSET jobs 0 j1"},
+	{"LTRIM", "key start stop", "Trim a list to the specified range", "list", "1.0.0", "O(N) where N is the number of elements to be removed by the operation", "LTRIM events 0 999"},
+	{"MEMORY", "subcommand [arg ...]", "A container for memory diagnostics commands", "server", "4.0.0", "Depends on subcommand", ""},
+	{"MEMORY DOCTOR", "", "Outputs memory problems report", "server", "4.0.0", "O(1)", ""},
+	{"MEMORY STATS", "", "Show memory usage details", "server", "4.0.0", "O(1)", ""},
+	{"MEMORY USAGE", "key [SAMPLES count]", "Estimate the memory usage of a key", "server", "4.0.0", "O(N) where N is the number of samples", "MEMORY USAGE user:42"},
+	{"MGET", "key [key ...]", "Get the values of all the given keys", "string", "1.0.0", "O(N) where N is the number of keys to retrieve", "MGET key1 key2"},
+	{"MIGRATE", "host port key|\"\" destination-db timeout [COPY] [REPLACE] [AUTH password|AUTH2 username password] [KEYS key [key ...]]", "Atomically transfer a key from a Redis instance to another one", "generic", "2.6.0", "O(N) on the source and O(M) on the destination", ""},
+	{"MODULE", "subcommand [arg ...]", "A container for module commands", "server", "4.0.0", "Depends on subcommand", ""},
+	{"MODULE LIST", "", "List all modules loaded by the server", "server", "4.0.0", "O(N) where N is the number of loaded modules", ""},
+	{"MONITOR", "", "Listen for all requests received by the server in real time", "server", "1.0.0", "", ""},
+	{"MOVE", "key db", "Move a key to another database", "generic", "1.0.0", "O(1)", "MOVE session:1 2"},
+	{"MSET", "key value [key value ...]", "Set multiple keys to multiple values", "string", "1.0.1", "O(N) where N is the number of keys to set", "MSET key1 a key2 b"},
+	{"MSETNX", "key value [key value ...]", "Set multiple keys to multiple values, only if none of the keys exist", "string", "1.0.1", "O(N) where N is the number of keys to set", ""},
+	{"MULTI", "", "Mark the start of a transaction block", "transactions", "1.2.0", "O(1)", ""},
+	{"OBJECT", "subcommand [arg ...]", "A container for object introspection commands", "generic", "2.2.3", "Depends on subcommand", ""},
+	{"OBJECT ENCODING", "key", "Inspect the internal encoding of a Redis object", "generic", "2.2.3", "O(1)", "OBJECT ENCODING user:42"},
+	{"OBJECT FREQ", "key", "Get the logarithmic access frequency counter of a Redis object", "generic", "4.0.0", "O(1)", ""},
+	{"OBJECT HELP", "", "Show helpful text about the different subcommands", "generic", "6.2.0", "O(1)", ""},
+	{"OBJECT IDLETIME", "key", "Get the time since a Redis object was last accessed", "generic", "2.2.3", "O(1)", ""},
+	{"OBJECT REFCOUNT", "key", "Get the number of references to the value of the key", "generic", "2.2.3", "O(1)", ""},
+	{"PERSIST", "key", "Remove the expiration from a key", "generic", "2.2.0", "O(1)", "PERSIST session:1"},
+	{"PEXPIRE", "key milliseconds [NX|XX|GT|LT]", "Set a key's time to live in milliseconds", "generic", "2.6.0", "O(1)", "PEXPIRE lock 1500"},
+	{"PEXPIREAT", "key unix-time-milliseconds [NX|XX|GT|LT]", "Set the expiration for a key as a UNIX timestamp specified in milliseconds", "generic", "2.6.0", "O(1)", ""},
+	{"PEXPIRETIME", "key", "Get the expiration Unix timestamp for a key in milliseconds", "generic", "7.0.0", "O(1)", ""},
+	{"PFADD", "key [element [element ...]]", "Adds the specified elements to the specified HyperLogLog", "hyperloglog", "2.8.9", "O(1) to add every element", "PFADD visitors alice bob"},
+	{"PFCOUNT", "key [key ...]", "Return the approximated cardinality of the set(s) observed by the HyperLogLog at key(s)", "hyperloglog", "2.8.9", "O(1) with a very small average constant time when called with a single key", "PFCOUNT visitors"},
+	{"PFMERGE", "destkey [sourcekey [sourcekey ...]]", "Merge N different HyperLogLogs into a single one", "hyperloglog", "2.8.9", "O(N) to merge N HyperLogLogs", "PFMERGE visitors:week visitors:mon visitors:tue"},
+	{"PING", "[message]", "Ping the server", "connection", "1.0.0", "O(1)", ""},
+	{"PSETEX", "key milliseconds value", "Set the value and expiration in milliseconds of a key", "string", "2.6.0", "O(1)", ""},
+	{"PSUBSCRIBE", "pattern [pattern ...]", "Listen for messages published to channels matching the given patterns", "pubsub", "2.0.0", "O(N) where N is the number of patterns the client is already subscribed to", "PSUBSCRIBE news.*"},
+	{"PTTL", "key", "Get the time to live for a key in milliseconds", "generic", "2.6.0", "O(1)", "PTTL lock"},
+	{"PUBLISH", "channel message", "Post a message to a channel", "pubsub", "2.0.0", "O(N+M) where N is the number of clients subscribed to the receiving channel and M is the total number of subscribed patterns", "PUBLISH news hello"},
+	{"PUBSUB", "subcommand [arg ...]", "A container for Pub/Sub commands", "pubsub", "2.8.0", "Depends on subcommand", ""},
+	{"PUBSUB CHANNELS", "[pattern]", "List active channels", "pubsub", "2.8.0", "O(N) where N is the number of active channels", "PUBSUB CHANNELS news.*"},
+	{"PUBSUB NUMSUB", "[channel [channel ...]]", "Get the count of subscribers for channels", "pubsub", "2.8.0", "O(N) for the NUMSUB subcommand, where N is the number of requested channels", ""},
+	{"PUNSUBSCRIBE", "[pattern [pattern ...]]", "Stop listening for messages posted to channels matching the given patterns", "pubsub", "2.0.0", "O(N+M) where N is the number of patterns the client is already subscribed and M is the number of total patterns subscribed in the system", ""},
+	{"QUIT", "", "Close the connection", "connection", "1.0.0", "O(1)", ""},
+	{"RANDOMKEY", "", "Return a random key from the keyspace", "generic", "1.0.0", "O(1)", ""},
+	{"READONLY", "", "Enables read queries for a connection to a cluster replica node", "cluster", "3.0.0", "O(1)", ""},
+	{"READWRITE", "", "Disables read queries for a connection to a cluster replica node", "cluster", "3.0.0", "O(1)", ""},
+	{"RENAME", "key newkey", "Rename a key", "generic", "1.0.0", "O(1)", "RENAME config config:old"},
+	{"RENAMENX", "key newkey", "Rename a key, only if the new key does not exist", "generic", "1.0.0", "O(1)", ""},
+	{"REPLICAOF", "host port|NO ONE", "Make the server a replica of another instance, or promote it as master", "server", "5.0.0", "O(1)", "REPLICAOF 10.0.0.1 6379"},
+	{"RESET", "", "Reset the connection", "connection", "6.2.0", "O(1)", ""},
+	{"RESTORE", "key ttl serialized-value [REPLACE] [ABSTTL] [IDLETIME seconds] [FREQ frequency]", "Create a key using the provided serialized value, previously obtained using DUMP", "generic", "2.6.0", "O(1) to create the new key and additional O(N*M) to reconstruct the serialized value", ""},
+	{"ROLE", "", "Return the role of the instance in the context of replication", "server", "2.8.12", "O(1)", ""},
+	{"RPOP", "key [count]", "Remove and get the last elements in a list", "list", "1.0.0", "O(N) where N is the number of elements returned", "RPOP jobs"},
+	{"RPOPLPUSH", "source destination", "Remove the last element in a list, prepend it to another list and return it", "list", "1.2.0", "O(1)", ""},
+	{"RPUSH", "key element [element ...]", "Append one or multiple elements to a list", "list", "1.0.0", "O(1) for each element added", "RPUSH jobs j1 j2"},
+	{"RPUSHX", "key element [element ...]", "Append an element to a list, only if the list exists", "list", "2.2.0", "O(1) for each element added", ""},
+	{"SADD", "key member [member ...]", "Add one or more members to a set", "set", "1.0.0", "O(1) for each element added", "SADD tags redis go"},
+	{"SAVE", "", "Synchronously save the dataset to disk", "server", "1.0.0", "O(N) where N is the total number of keys in all databases", ""},
+	{"SCAN", "cursor [MATCH pattern] [COUNT count] [TYPE type]", "Incrementally iterate the keys space", "generic", "2.8.0", "O(1) for every call. O(N) for a complete iteration", "SCAN 0 MATCH user:* COUNT 100"},
+	{"SCARD", "key", "Get the number of members in a set", "set", "1.0.0", "O(1)", "SCARD tags"},
+	{"SCRIPT", "subcommand [arg ...]", "A container for Lua scripts management commands", "scripting", "2.6.0", "Depends on subcommand", ""},
+	{"SCRIPT EXISTS", "sha1 [sha1 ...]", "Check existence of scripts in the script cache", "scripting", "2.6.0", "O(N) with N being the number of scripts to check", ""},
+	{"SCRIPT FLUSH", "[ASYNC|SYNC]", "Remove all the scripts from the script cache", "scripting", "2.6.0", "O(N) with N being the number of scripts in cache", ""},
+	{"SCRIPT KILL", "", "Kill the script currently in execution", "scripting", "2.6.0", "O(1)", ""},
+	{"SCRIPT LOAD", "script", "Load the specified Lua script into the script cache", "scripting", "2.6.0", "O(N) with N being the length in bytes of the script body", "SCRIPT LOAD \"return 1\""},
+	{"SDIFF", "key [key ...]", "Subtract multiple sets", "set", "1.0.0", "O(N) where N is the total number of elements in all given sets", "SDIFF tags:a tags:b"},
+	{"SDIFFSTORE", "destination key [key ...]", "Subtract multiple sets and store the resulting set in a key", "set", "1.0.0", "O(N) where N is the total number of elements in all given sets", ""},
+	{"SELECT", "index", "Change the selected database for the current connection", "connection", "1.0.0", "O(1)", "SELECT 1"},
+	{"SET", "key value [NX|XX] [GET] [EX seconds|PX milliseconds|EXAT unix-time-seconds|PXAT unix-time-milliseconds|KEEPTTL]", "Set the string value of a key", "string", "1.0.0", "O(1)", "SET greeting hello EX 60"},
+	{"SETBIT", "key offset value", "Sets or clears the bit at offset in the string value stored at key", "bitmap", "2.2.0", "O(1)", "SETBIT visits 7 1"},
+	{"SETEX", "key seconds value", "Set the value and expiration of a key", "string", "2.0.0", "O(1)", ""},
+	{"SETNX", "key value", "Set the value of a key, only if the key does not exist", "string", "1.0.0", "O(1)", ""},
+	{"SETRANGE", "key offset value", "Overwrite part of a string at key starting at the specified offset", "string", "2.2.0", "O(1), not counting the time taken to copy the new string in place", "SETRANGE greeting 6 Redis"},
+	{"SHUTDOWN", "[NOSAVE|SAVE] [NOW] [FORCE] [ABORT]", "Synchronously save the dataset to disk and then shut down the server", "server", "1.0.0", "O(N) when saving, where N is the total number of keys in all databases", ""},
+	{"SINTER", "key [key ...]", "Intersect multiple sets", "set", "1.0.0", "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets", "SINTER tags:a tags:b"},
+	{"SINTERCARD", "numkeys key [key ...] [LIMIT limit]", "Intersect multiple sets and return the cardinality of the result", "set", "7.0.0", "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets", "SINTERCARD 2 tags:a tags:b"},
+	{"SINTERSTORE", "destination key [key ...]", "Intersect multiple sets and store the resulting set in a key", "set", "1.0.0", "O(N*M) worst case where N is the cardinality of the smallest set and M is the number of sets", ""},
+	{"SISMEMBER", "key member", "Determine if a given value is a member of a set", "set", "1.0.0", "O(1)", "SISMEMBER tags redis"},
+	{"SLAVEOF", "host port|NO ONE", "Make the server a replica of another instance, or promote it as master", "server", "1.0.0", "O(1)", ""},
+	{"SLOWLOG", "subcommand [arg ...]", "A container for slow log commands", "server", "2.2.12", "Depends on subcommand", ""},
+	{"SLOWLOG GET", "[count]", "Get the slow log's entries", "server", "2.2.12", "O(N) where N is the number of entries returned", "SLOWLOG GET 10"},
+	{"SLOWLOG LEN", "", "Get the slow log's length", "server", "2.2.12", "O(1)", ""},
+	{"SLOWLOG RESET", "", "Clear all entries from the slow log", "server", "2.2.12", "O(N) where N is the number of entries in the slowlog", ""},
+	{"SMEMBERS", "key", "Get all the members in a set", "set", "1.0.0", "O(N) where N is the set cardinality", "SMEMBERS tags"},
+	{"SMISMEMBER", "key member [member ...]", "Returns the membership associated with the given elements for a set", "set", "6.2.0", "O(N) where N is the number of elements being checked for membership", "SMISMEMBER tags redis go"},
+	{"SMOVE", "source destination member", "Move a member from one set to another", "set", "1.0.0", "O(1)", ""},
+	{"SORT", "key [BY pattern] [LIMIT offset count] [GET pattern [GET pattern ...]] [ASC|DESC] [ALPHA] [STORE destination]", "Sort the elements in a list, set or sorted set", "generic", "1.0.0", "O(N+M*log(M)) where N is the number of elements in the list or set to sort, and M the number of returned elements", "SORT ids ALPHA LIMIT 0 10"},
+	{"SPOP", "key [count]", "Remove and return one or multiple random members from a set", "set", "1.0.0", "Without the count argument O(1), otherwise O(N) where N is the value of the passed count", ""},
+	{"SPUBLISH", "shardchannel message", "Post a message to a shard channel", "pubsub", "7.0.0", "O(N) where N is the number of clients subscribed to the receiving shard channel", ""},
+	{"SRANDMEMBER", "key [count]", "Get one or multiple random members from a set", "set", "1.0.0", "Without the count argument O(1), otherwise O(N) where N is the absolute value of the passed count", "SRANDMEMBER tags 3"},
+	{"SREM", "key member [member ...]", "Remove one or more members from a set", "set", "1.0.0", "O(N) where N is the number of members to be removed", "SREM tags go"},
+	{"SSCAN", "key cursor [MATCH pattern] [COUNT count]", "Incrementally iterate Set elements", "set", "2.8.0", "O(1) for every call. O(N) for a complete iteration", "SSCAN tags 0 MATCH r*"},
+	{"SSUBSCRIBE", "shardchannel [shardchannel ...]", "Listen for messages published to the given shard channels", "pubsub", "7.0.0", "O(N) where N is the number of shard channels to subscribe to", ""},
+	{"STRLEN", "key", "Get the length of the value stored in a key", "string", "2.2.0", "O(1)", "STRLEN greeting"},
+	{"SUBSCRIBE", "channel [channel ...]", "Listen for messages published to the given channels", "pubsub", "2.0.0", "O(N) where N is the number of channels to subscribe to", "SUBSCRIBE news"},
+	{"SUBSTR", "key start end", "Get a substring of the string stored at a key", "string", "1.0.0", "O(N) where N is the length of the returned string", ""},
+	{"SUNION", "key [key ...]", "Add multiple sets", "set", "1.0.0", "O(N) where N is the total number of elements in all given sets", "SUNION tags:a tags:b"},
+	{"SUNIONSTORE", "destination key [key ...]", "Add multiple sets and store the resulting set in a key", "set", "1.0.0", "O(N) where N is the total number of elements in all given sets", ""},
+	{"SUNSUBSCRIBE", "[shardchannel [shardchannel ...]]", "Stop listening for messages posted to the given shard channels", "pubsub", "7.0.0", "O(N) where N is the number of clients already subscribed to a shard channel", ""},
+	{"SWAPDB", "index1 index2", "Swaps two Redis databases", "server", "4.0.0", "O(N) where N is the count of clients watching or blocking on keys from both databases", "SWAPDB 0 1"},
+	{"SYNC", "", "Internal command used for replication", "server", "1.0.0", "", ""},
+	{"TIME", "", "Return the current server time", "server", "2.6.0", "O(1)", ""},
+	{"TOUCH", "key [key ...]", "Alters the last access time of a key(s). Returns the number of existing keys specified", "generic", "3.2.1", "O(N) where N is the number of keys that will be touched", ""},
+	{"TTL", "key", "Get the time to live for a key in seconds", "generic", "1.0.0", "O(1)", "TTL session:1"},
+	{"TYPE", "key", "Determine the type stored at key", "generic", "1.0.0", "O(1)", "TYPE user:42"},
+	{"UNLINK", "key [key ...]", "Delete a key asynchronously in another thread. Otherwise it is just as DEL, but non blocking", "generic", "4.0.0", "O(1) for each key removed regardless of its size", "UNLINK bigset"},
+	{"UNSUBSCRIBE", "[channel [channel ...]]", "Stop listening for messages posted to the given channels", "pubsub", "2.0.0", "O(N) where N is the number of clients already subscribed to a channel", ""},
+	{"UNWATCH", "", "Forget about all watched keys", "transactions", "2.2.0", "O(1)", ""},
+	{"WAIT", "numreplicas timeout", "Wait for the synchronous replication of all the write commands sent in the context of the current connection", "generic", "3.0.0", "O(1)", "WAIT 1 1000"},
+	{"WAITAOF", "numlocal numreplicas timeout", "Wait for all write commands sent by the client to be fsynced to the AOF", "generic", "7.2.0", "O(1)", ""},
+	{"WATCH", "key [key ...]", "Watch the given keys to determine execution of the MULTI/EXEC block", "transactions", "2.2.0", "O(1) for every key", ""},
+	{"XACK", "key group id [id ...]", "Marks a pending message as correctly processed, removing it from the pending entries list of the consumer group", "stream", "5.0.0", "O(1) for each message ID processed", "XACK events workers 1526569495631-0"},
+	{"XADD", "key [NOMKSTREAM] [MAXLEN|MINID [=|~] threshold [LIMIT count]] *|id field value [field value ...]", "Appends a new entry to a stream", "stream", "5.0.0", "O(1) when adding a new entry, O(N) when trimming where N being the number of entries evicted", "XADD events * type login user 42"},
+	{"XAUTOCLAIM", "key group consumer min-idle-time start [COUNT count] [JUSTID]", "Changes (or acquires) ownership of messages in a consumer group, as if the messages were delivered to the specified consumer", "stream", "6.2.0", "O(1) if COUNT is small", ""},
+	{"XCLAIM", "key group consumer min-idle-time id [id ...] [IDLE ms] [TIME unix-time-milliseconds] [RETRYCOUNT count] [FORCE] [JUSTID]", "Changes (or acquires) ownership of a message in a consumer group, as if the message was delivered to the specified consumer", "stream", "5.0.0", "O(log N) with N being the number of messages in the PEL of the consumer group", ""},
+	{"XDEL", "key id [id ...]", "Removes the specified entries from the stream. Returns the number of items actually deleted, that may be different from the number of IDs passed in case certain IDs do not exist", "stream", "5.0.0", "O(1) for each single item to delete in the stream", ""},
+	{"XGROUP", "subcommand [arg ...]", "A container for consumer groups commands", "stream", "5.0.0", "Depends on subcommand", ""},
+	{"XGROUP CREATE", "key group id|$ [MKSTREAM] [ENTRIESREAD entries-read]", "Create a consumer group", "stream", "5.0.0", "O(1)", "XGROUP CREATE events workers $ MKSTREAM"},
+	{"XINFO", "subcommand [arg ...]", "A container for stream introspection commands", "stream", "5.0.0", "Depends on subcommand", ""},
+	{"XINFO GROUPS", "key", "List the consumer groups of a stream", "stream", "5.0.0", "O(1)", "XINFO GROUPS events"},
+	{"XINFO STREAM", "key [FULL [COUNT count]]", "Get information about a stream", "stream", "5.0.0", "O(1)", "XINFO STREAM events"},
+	{"XLEN", "key", "Return the number of entries in a stream", "stream", "5.0.0", "O(1)", "XLEN events"},
+	{"XPENDING", "key group [[IDLE min-idle-time] start end count [consumer]]", "Return information and entries from a stream consumer group pending entries list, that are messages fetched but never acknowledged", "stream", "5.0.0", "O(N) with N being the number of elements returned", "XPENDING events workers"},
+	{"XRANGE", "key start end [COUNT count]", "Return a range of elements in a stream, with IDs matching the specified IDs interval", "stream", "5.0.0", "O(N) with N being the number of elements being returned", "XRANGE events - + COUNT 10"},
+	{"XREAD", "[COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...]", "Return never seen elements in multiple streams, with IDs greater than the ones reported by the caller for each stream. Can block", "stream", "5.0.0", "", "XREAD COUNT 10 STREAMS events 0"},
+	{"XREADGROUP", "GROUP group consumer [COUNT count] [BLOCK milliseconds] [NOACK] STREAMS key [key ...] id [id ...]", "Return new entries from a stream using a consumer group, or access the history of the pending entries for a given consumer. Can block", "stream", "5.0.0", "For each stream mentioned: O(M) with M the number of elements returned", "XREADGROUP GROUP workers w1 COUNT 10 STREAMS events >"},
+	{"XREVRANGE", "key end start [COUNT count]", "Return a range of elements in a stream, with IDs matching the specified IDs interval, in reverse order (from greater to smaller IDs) compared to XRANGE", "stream", "5.0.0", "O(N) with N being the number of elements returned", "XREVRANGE events + - COUNT 1"},
+	{"XSETID", "key last-id [ENTRIESADDED entries-added] [MAXDELETEDID max-deleted-id]", "An internal command for replicating stream values", "stream", "5.0.0", "O(1)", ""},
+	{"XTRIM", "key MAXLEN|MINID [=|~] threshold [LIMIT count]", "Trims the stream to (approximately if '~' is passed) a certain size", "stream", "5.0.0", "O(N), with N being the number of evicted entries", "XTRIM events MAXLEN ~ 1000"},
+	{"ZADD", "key [NX|XX] [GT|LT] [CH] [INCR] score member [score member ...]", "Add one or more members to a sorted set, or update its score if it already exists", "sorted-set", "1.2.0", "O(log(N)) for each item added, where N is the number of elements in the sorted set", "ZADD scores 10 alice 20 bob"},
+	{"ZCARD", "key", "Get the number of members in a sorted set", "sorted-set", "1.2.0", "O(1)", "ZCARD scores"},
+	{"ZCOUNT", "key min max", "Count the members in a sorted set with scores within the given values", "sorted-set", "2.0.0", "O(log(N)) with N being the number of elements in the sorted set", "ZCOUNT scores 10 (20"},
+	{"ZDIFF", "numkeys key [key ...] [WITHSCORES]", "Subtract multiple sorted sets", "sorted-set", "6.2.0", "O(L + (N-K)log(N)) worst case where L is the total number of elements in all the sets, N is the size of the first set, and K is the size of the result set", ""},
+	{"ZDIFFSTORE", "destination numkeys key [key ...]", "Subtract multiple sorted sets and store the resulting sorted set in a new key", "sorted-set", "6.2.0", "O(L + (N-K)log(N)) worst case where L is the total number of elements in all the sets, N is the size of the first set, and K is the size of the result set", ""},
+	{"ZINCRBY", "key increment member", "Increment the score of a member in a sorted set", "sorted-set", "1.2.0", "O(log(N)) where N is the number of elements in the sorted set", "ZINCRBY scores 5 alice"},
+	{"ZINTER", "numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM|MIN|MAX] [WITHSCORES]", "Intersect multiple sorted sets", "sorted-set", "6.2.0", "O(N*K)+O(M*log(M)) worst case with N being the smallest input sorted set, K being the number of input sorted sets and M being the number of elements in the resulting sorted set", ""},
+	{"ZINTERCARD", "numkeys key [key ...] [LIMIT limit]", "Intersect multiple sorted sets and return the cardinality of the result", "sorted-set", "7.0.0", "O(N*K) worst case with N being the smallest input sorted set, K being the number of input sorted sets", ""},
+	{"ZINTERSTORE", "destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM|MIN|MAX]", "Intersect multiple sorted sets and store the resulting sorted set in a new key", "sorted-set", "2.0.0", "O(N*K)+O(M*log(M)) worst case with N being the smallest input sorted set, K being the number of input sorted sets and M being the number of elements in the resulting sorted set", ""},
+	{"ZLEXCOUNT", "key min max", "Count the number of members in a sorted set between a given lexicographical range", "sorted-set", "2.8.9", "O(log(N)) with N being the number of elements in the sorted set", "ZLEXCOUNT names [a (c"},
+	{"ZMPOP", "numkeys key [key ...] MIN|MAX [COUNT count]", "Remove and return members with scores in a sorted set", "sorted-set", "7.0.0", "O(K) + O(M*log(N)) where K is the number of provided keys, N the number of elements in the sorted set and M the number of elements popped", ""},
+	{"ZMSCORE", "key member [member ...]", "Get the score associated with the given members in a sorted set", "sorted-set", "6.2.0", "O(N) where N is the number of members being requested", "ZMSCORE scores alice bob"},
+	{"ZPOPMAX", "key [count]", "Remove and return members with the highest scores in a sorted set", "sorted-set", "5.0.0", "O(log(N)*M) with N being the number of elements in the sorted set, and M being the number of elements popped", ""},
+	{"ZPOPMIN", "key [count]", "Remove and return members with the lowest scores in a sorted set", "sorted-set", "5.0.0", "O(log(N)*M) with N being the number of elements in the sorted set, and M being the number of elements popped", ""},
+	{"ZRANDMEMBER", "key [count [WITHSCORES]]", "Get one or multiple random elements from a sorted set", "sorted-set", "6.2.0", "O(N) where N is the number of elements returned", ""},
+	{"ZRANGE", "key start stop [BYSCORE|BYLEX] [REV] [LIMIT offset count] [WITHSCORES]", "Return a range of members in a sorted set", "sorted-set", "1.2.0", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements returned", "ZRANGE scores 0 9 REV WITHSCORES"},
+	{"ZRANGEBYLEX", "key min max [LIMIT offset count]", "Return a range of members in a sorted set, by lexicographical range", "sorted-set", "2.8.9", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned", ""},
+	{"ZRANGEBYSCORE", "key min max [WITHSCORES] [LIMIT offset count]", "Return a range of members in a sorted set, by score", "sorted-set", "1.0.5", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned", ""},
+	{"ZRANGESTORE", "dst src min max [BYSCORE|BYLEX] [REV] [LIMIT offset count]", "Store a range of members from sorted set into another key", "sorted-set", "6.2.0", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements stored into the destination key", ""},
+	{"ZRANK", "key member [WITHSCORE]", "Determine the index of a member in a sorted set", "sorted-set", "2.0.0", "O(log(N))", "ZRANK scores alice"},
+	{"ZREM", "key member [member ...]", "Remove one or more members from a sorted set", "sorted-set", "1.2.0", "O(M*log(N)) with N being the number of elements in the sorted set and M the number of elements to be removed", "ZREM scores bob"},
+	{"ZREMRANGEBYLEX", "key min max", "Remove all members in a sorted set between the given lexicographical range", "sorted-set", "2.8.9", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation", ""},
+	{"ZREMRANGEBYRANK", "key start stop", "Remove all members in a sorted set within the given indexes", "sorted-set", "2.0.0", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation", "ZREMRANGEBYRANK scores 0 -101"},
+	{"ZREMRANGEBYSCORE", "key min max", "Remove all members in a sorted set within the given scores", "sorted-set", "1.2.0", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements removed by the operation", ""},
+	{"ZREVRANGE", "key start stop [WITHSCORES]", "Return a range of members in a sorted set, by index, with scores ordered from high to low", "sorted-set", "1.2.0", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements returned", ""},
+	{"ZREVRANGEBYLEX", "key max min [LIMIT offset count]", "Return a range of members in a sorted set, by lexicographical range, ordered from higher to lower strings", "sorted-set", "2.8.9", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned", ""},
+	{"ZREVRANGEBYSCORE", "key max min [WITHSCORES] [LIMIT offset count]", "Return a range of members in a sorted set, by score, with scores ordered from high to low", "sorted-set", "2.2.0", "O(log(N)+M) with N being the number of elements in the sorted set and M the number of elements being returned", ""},
+	{"ZREVRANK", "key member [WITHSCORE]", "Determine the index of a member in a sorted set, with scores ordered from high to low", "sorted-set", "2.0.0", "O(log(N))", "ZREVRANK scores alice"},
+	{"ZSCAN", "key cursor [MATCH pattern] [COUNT count]", "Incrementally iterate sorted sets elements and associated scores", "sorted-set", "2.8.0", "O(1) for every call. O(N) for a complete iteration", ""},
+	{"ZSCORE", "key member", "Get the score associated with the given member in a sorted set", "sorted-set", "1.2.0", "O(1)", "ZSCORE scores alice"},
+	{"ZUNION", "numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM|MIN|MAX] [WITHSCORES]", "Add multiple sorted sets", "sorted-set", "6.2.0", "O(N)+O(M*log(M)) with N being the sum of the sizes of the input sorted sets, and M being the number of elements in the resulting sorted set", ""},
+	{"ZUNIONSTORE", "destination numkeys key [key ...] [WEIGHTS weight [weight ...]] [AGGREGATE SUM|MIN|MAX]", "Add multiple sorted sets and store the resulting sorted set in a new key", "sorted-set", "2.0.0", "O(N)+O(M*log(M)) with N being the sum of the sizes of the input sorted sets, and M being the number of elements in the resulting sorted set", ""},
+}
+
+// commandGroups lists the groups of commandDocs in the order HELP shows
+// them.
+var commandGroups = []string{
+	"generic", "string", "list", "set", "sorted-set", "hash", "pubsub", "transactions",
+	"connection", "server", "scripting", "hyperloglog", "cluster", "geo", "stream", "bitmap",
+}
+
+// lookupCommandDoc returns the documentation of a command, or of its
+// subcommand when cmds has one that is documented.
+func lookupCommandDoc(cmds []string) (commandDoc, bool) {
+	if len(cmds) > 1 {
+		if d, ok := findCommandDoc(cmds[0] + " " + cmds[1]); ok {
+			return d, true
+		}
+	}
+	if len(cmds) > 0 {
+		return findCommandDoc(cmds[0])
+	}
+	return commandDoc{}, false
+}
+
+func findCommandDoc(name string) (commandDoc, bool) {
+	name = strings.ToUpper(name)
+	i := sort.Search(len(commandDocs), func(i int) bool {
+		return commandDocs[i].Name >= name
+	})
+	if i < len(commandDocs) && commandDocs[i].Name == name {
+		return commandDocs[i], true
+	}
+	return commandDoc{}, false
+}
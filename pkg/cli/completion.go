@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// completionCommands returns the lower-cased Redis command names known to
+// the command reference.
+func completionCommands() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, d := range commandDocs {
+		name := strings.ToLower(strings.Fields(d.Name)[0])
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printCompletion writes a completion script for the given shell.
+// Usage: redis-cli completion bash|zsh|fish
+func printCompletion(args []string) {
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be completion bash|zsh|fish")
+		os.Exit(1)
+	}
+
+	prog := filepath.Base(os.Args[0])
+	fn := "_" + strings.NewReplacer("-", "_", ".", "_").Replace(prog)
+
+	var flags []string
+	flag.VisitAll(func(f *flag.Flag) {
+		flags = append(flags, "-"+f.Name)
+	})
+	commands := completionCommands()
+
+	switch strings.ToLower(args[0]) {
+	case "bash":
+		fmt.Printf(`%s() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+    else
+        COMPREPLY=( $(compgen -W "%s" -- "$cur") )
+    fi
+}
+complete -F %s %s
+`, fn, strings.Join(flags, " "), strings.Join(commands, " "), fn, prog)
+	case "zsh":
+		fmt.Printf(`#compdef %s
+%s() {
+    if [[ $PREFIX == -* ]]; then
+        compadd -- %s
+    else
+        compadd -- %s
+    fi
+}
+compdef %s %s
+`, prog, fn, strings.Join(flags, " "), strings.Join(commands, " "), fn, prog)
+	case "fish":
+		flag.VisitAll(func(f *flag.Flag) {
+			fmt.Printf("complete -c %s -o %s -d %q\n", prog, f.Name, f.Usage)
+		})
+		fmt.Printf("complete -c %s -f -a %q\n", prog, strings.Join(commands, " "))
+	default:
+		fmt.Printf("(error) unsupported shell %q, should be one of bash, zsh, fish\n", args[0])
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// keySpec locates the keys of a command: from first to last (negative
+// counts from the end) every step arguments, as COMMAND reports them.
+type keySpec struct {
+	first, last, step int
+}
+
+// multiKeySpecs are used for the common multi-key commands when the
+// server doesn't answer COMMAND, as some proxies don't.
+var multiKeySpecs = map[string]keySpec{
+	"mget":        {1, -1, 1},
+	"mset":        {1, -1, 2},
+	"msetnx":      {1, -1, 2},
+	"del":         {1, -1, 1},
+	"unlink":      {1, -1, 1},
+	"exists":      {1, -1, 1},
+	"touch":       {1, -1, 1},
+	"watch":       {1, -1, 1},
+	"rename":      {1, 2, 1},
+	"renamenx":    {1, 2, 1},
+	"copy":        {1, 2, 1},
+	"smove":       {1, 2, 1},
+	"lmove":       {1, 2, 1},
+	"rpoplpush":   {1, 2, 1},
+	"sdiff":       {1, -1, 1},
+	"sinter":      {1, -1, 1},
+	"sunion":      {1, -1, 1},
+	"sdiffstore":  {1, -1, 1},
+	"sinterstore": {1, -1, 1},
+	"sunionstore": {1, -1, 1},
+	"pfcount":     {1, -1, 1},
+	"pfmerge":     {1, -1, 1},
+	"blpop":       {1, -2, 1},
+	"brpop":       {1, -2, 1},
+	"bzpopmin":    {1, -2, 1},
+	"bzpopmax":    {1, -2, 1},
+}
+
+// numKeysCommands take their keys after a numkeys argument, at the given
+// index, with the keys before it, such as a destination, at keysBefore.
+var numKeysCommands = map[string]struct {
+	numkeys    int
+	keysBefore []int
+}{
+	"eval":        {2, nil},
+	"evalsha":     {2, nil},
+	"eval_ro":     {2, nil},
+	"evalsha_ro":  {2, nil},
+	"fcall":       {2, nil},
+	"fcall_ro":    {2, nil},
+	"zunion":      {1, nil},
+	"zinter":      {1, nil},
+	"zdiff":       {1, nil},
+	"zintercard":  {1, nil},
+	"sintercard":  {1, nil},
+	"lmpop":       {1, nil},
+	"zmpop":       {1, nil},
+	"blmpop":      {2, nil},
+	"bzmpop":      {2, nil},
+	"zunionstore": {2, []int{1}},
+	"zinterstore": {2, []int{1}},
+	"zdiffstore":  {2, []int{1}},
+}
+
+// commandKeys returns the keys of a command line, or nil when their
+// position is unknown.
+func commandKeys(args []string) []string {
+	name := strings.ToLower(args[0])
+	if nk, ok := numKeysCommands[name]; ok {
+		if nk.numkeys >= len(args) {
+			return nil
+		}
+		n, err := strconv.Atoi(args[nk.numkeys])
+		if err != nil || n < 0 || nk.numkeys+n >= len(args) {
+			return nil
+		}
+		var keys []string
+		for _, i := range nk.keysBefore {
+			keys = append(keys, args[i])
+		}
+		return append(keys, args[nk.numkeys+1:nk.numkeys+1+n]...)
+	}
+
+	spec, ok := multiKeySpecs[name]
+	if info := commandInfo(name); info != nil {
+		spec, ok = keySpec{int(info.FirstKeyPos), int(info.LastKeyPos), int(info.StepCount)}, true
+	}
+	if !ok || spec.first <= 0 || spec.step <= 0 {
+		return nil
+	}
+	last := spec.last
+	if last < 0 {
+		last += len(args)
+	}
+	var keys []string
+	for i := spec.first; i <= last && i < len(args); i += spec.step {
+		keys = append(keys, args[i])
+	}
+	return keys
+}
+
+// checkSlots refuses, in cluster mode, a command whose keys don't all map
+// to one slot, naming the keys of every slot rather than leaving the
+// server to answer CROSSSLOT.
+func checkSlots(args []string) error {
+	if !clusterEnabled || len(args) < 3 {
+		return nil
+	}
+	keys := commandKeys(args)
+	if len(keys) < 2 {
+		return nil
+	}
+
+	bySlot := map[int][]string{}
+	var slots []int
+	for _, k := range keys {
+		s := keySlot(k)
+		if _, ok := bySlot[s]; !ok {
+			slots = append(slots, s)
+		}
+		bySlot[s] = append(bySlot[s], k)
+	}
+	if len(slots) == 1 {
+		return nil
+	}
+
+	sort.Ints(slots)
+	groups := make([]string, len(slots))
+	for i, s := range slots {
+		groups[i] = fmt.Sprintf("slot %d: %s", s, strings.Join(bySlot[s], " "))
+	}
+	return fmt.Errorf("CROSSSLOT the keys of %s map to %d slots (%s), give them a common {tag} to use them together",
+		strings.ToUpper(args[0]), len(slots), strings.Join(groups, "; "))
+}
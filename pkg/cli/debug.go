@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// debugCrashing are the DEBUG subcommands that take the server down or
+// replace its dataset.
+var debugCrashing = map[string]bool{
+	"segfault":          true,
+	"panic":             true,
+	"oom":               true,
+	"assert":            true,
+	"restart":           true,
+	"crash-and-recover": true,
+	"reload":            true,
+	"loadaof":           true,
+	"flushall":          true,
+}
+
+// debugCommand validates the common DEBUG subcommands and warns before the
+// ones that block or crash the server. DEBUG --raw sub ... skips the checks.
+// Usage: DEBUG OBJECT key | SLEEP seconds | SET-ACTIVE-EXPIRE 0|1 | QUICKACK 0|1 | STRINGMATCH-LEN
+func debugCommand(cmds []string) {
+	if len(cmds) > 1 && strings.ToLower(cmds[1]) == "--raw" {
+		cliSendCommand(append(cmds[:1:1], cmds[2:]...)...)
+		return
+	}
+	if len(cmds) < 2 {
+		fmt.Println("(error) invalid args. Should be DEBUG OBJECT key | SLEEP seconds | SET-ACTIVE-EXPIRE 0|1 | QUICKACK 0|1 | STRINGMATCH-LEN, or DEBUG --raw ...")
+		return
+	}
+
+	sub := strings.ToLower(cmds[1])
+	args := cmds[2:]
+	switch {
+	case sub == "object":
+		if len(args) != 1 {
+			fmt.Println("(error) invalid args. Should be DEBUG OBJECT key")
+			return
+		}
+		debugObject(lexer.TrimQuotes(args[0]))
+		return
+	case sub == "sleep":
+		if len(args) != 1 {
+			fmt.Println("(error) invalid args. Should be DEBUG SLEEP seconds")
+			return
+		}
+		secs, err := strconv.ParseFloat(args[0], 64)
+		if err != nil || secs < 0 {
+			fmt.Printf("(error) invalid number of seconds %q\n", args[0])
+			return
+		}
+		if secs > 0 && !confirm(fmt.Sprintf("DEBUG SLEEP blocks %s, and every client of it, for %gs. Continue?", addr(), secs)) {
+			return
+		}
+	case sub == "set-active-expire" || sub == "quickack":
+		if len(args) != 1 || (args[0] != "0" && args[0] != "1") {
+			fmt.Printf("(error) invalid args. Should be DEBUG %s 0|1\n", strings.ToUpper(sub))
+			return
+		}
+		if sub == "set-active-expire" && args[0] == "0" &&
+			!confirm("With active expire off, keys only expire when accessed and memory can grow. Continue?") {
+			return
+		}
+	case sub == "stringmatch-len":
+		if len(args) != 0 {
+			fmt.Println("(error) invalid args. Should be DEBUG STRINGMATCH-LEN")
+			return
+		}
+		fmt.Println("warning: this runs a CPU intensive pattern matching test on the server")
+	case debugCrashing[sub]:
+		if !confirm(fmt.Sprintf("DEBUG %s disrupts %s: it crashes, restarts or reloads the server. Continue?", strings.ToUpper(sub), addr())) {
+			return
+		}
+	}
+
+	cliSendCommand(cmds...)
+}
+
+// debugObject prints the fields of DEBUG OBJECT as a table.
+func debugObject(key string) {
+	cliConnect()
+	if err := checkAllowed([]string{"DEBUG", "OBJECT", key}); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	s, err := client.Do("DEBUG", "OBJECT", key).String()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		if strings.Contains(err.Error(), "not allowed") {
+			fmt.Println("hint: DEBUG is disabled by default since Redis 7, see enable-debug-command")
+		}
+		return
+	}
+
+	// Value at:0x7f... refcount:1 encoding:embstr serializedlength:6 lru:... lru_seconds_idle:3
+	var rows [][]string
+	for _, field := range strings.Fields(strings.TrimPrefix(s, "Value ")) {
+		if i := strings.Index(field, ":"); i > 0 {
+			rows = append(rows, []string{field[:i], field[i+1:]})
+		}
+	}
+	if len(rows) == 0 {
+		fmt.Println(s)
+		return
+	}
+	printTable([]string{"FIELD", "VALUE"}, rows)
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+const diffContext = 3
+
+// diffCommands runs two commands and prints a unified diff of their
+// formatted replies. A command starting with @host:port runs on that node.
+// Usage: DIFFCMD "cmd A" "cmd B"
+func diffCommands(args []string) {
+	if len(args) != 2 {
+		fmt.Println(`(error) invalid args. Should be DIFFCMD "cmd A" "cmd B"`)
+		return
+	}
+	cliConnect()
+
+	a, b := lexer.TrimQuotes(args[0]), lexer.TrimQuotes(args[1])
+	outA, err := captureCommand(a)
+	if err != nil {
+		fmt.Printf("(error) %s: %s\n", a, err.Error())
+		return
+	}
+	outB, err := captureCommand(b)
+	if err != nil {
+		fmt.Printf("(error) %s: %s\n", b, err.Error())
+		return
+	}
+
+	d := unifiedDiff(strings.Split(outA, "\n"), strings.Split(outB, "\n"), a, b)
+	if d == "" {
+		fmt.Println("(no differences)")
+		return
+	}
+	fmt.Print(d)
+}
+
+// captureCommand runs a command line and returns its formatted reply.
+func captureCommand(l string) (string, error) {
+	cmds := lexer.Split(l)
+	target := ""
+	if len(cmds) > 0 && strings.HasPrefix(cmds[0], "@") {
+		target, cmds = cmds[0][1:], cmds[1:]
+	}
+	if len(cmds) == 0 {
+		return "", fmt.Errorf("empty command")
+	}
+
+	args := make([]interface{}, len(cmds))
+	for i, c := range cmds {
+		args[i] = lexer.TrimQuotes(c)
+	}
+
+	var r interface{}
+	var err error
+	if target != "" {
+		c := conn.NewSingle(connOptions(target, *auth))
+		defer c.Close()
+		r, err = c.Do(args...).Result()
+	} else {
+		r, err = client.Do(args...).Result()
+	}
+	if err == redis.Nil {
+		r, err = nil, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return format.Sprint(r, mode), nil
+}
+
+// unifiedDiff returns the unified diff of two line slices, empty when
+// they are equal.
+func unifiedDiff(a, b []string, nameA, nameB string) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type edit struct {
+		op   byte
+		line string
+		ai   int
+		bi   int
+	}
+	var edits []edit
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			edits = append(edits, edit{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, edit{'-', a[i], i, j})
+			i++
+		default:
+			edits = append(edits, edit{'+', b[j], i, j})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	for k := 0; k < len(edits); {
+		if edits[k].op == ' ' {
+			k++
+			continue
+		}
+
+		// grow the hunk while changes are close enough to share context
+		start := k - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := k
+		for end < len(edits) {
+			if edits[end].op != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(edits) && edits[next].op == ' ' {
+				next++
+			}
+			if next < len(edits) && next-end <= 2*diffContext {
+				end = next
+				continue
+			}
+			end += diffContext
+			if end > len(edits) {
+				end = len(edits)
+			}
+			break
+		}
+
+		if out.Len() == 0 {
+			fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+		}
+		countA, countB := 0, 0
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				countA++
+			}
+			if e.op != '-' {
+				countB++
+			}
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", edits[start].ai+1, countA, edits[start].bi+1, countB)
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(&out, "%c%s\n", e.op, e.line)
+		}
+		k = end
+	}
+	return out.String()
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// dumpKey writes the DUMP payload of a key to a file.
+// Usage: DUMPKEY key file
+func dumpKey(args []string) {
+	if len(args) != 2 {
+		fmt.Println("(error) invalid args. Should be DUMPKEY key file")
+		return
+	}
+	cliConnect()
+
+	key, file := lexer.TrimQuotes(args[0]), lexer.TrimQuotes(args[1])
+	payload, err := client.Do("DUMP", key).String()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	if err := writeFileAtomic(file, []byte(payload), 0600); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Printf("dumped %s to %s (%d bytes)\n", key, file, len(payload))
+}
+
+// restoreKey restores a key from a payload previously written by DUMPKEY.
+// Usage: RESTOREKEY key file [ttl] [REPLACE]
+func restoreKey(args []string) {
+	if len(args) < 2 || len(args) > 4 {
+		fmt.Println("(error) invalid args. Should be RESTOREKEY key file [ttl] [REPLACE]")
+		return
+	}
+	cliConnect()
+
+	key, file := lexer.TrimQuotes(args[0]), lexer.TrimQuotes(args[1])
+	ttl := int64(0)
+	replace := false
+	for _, arg := range args[2:] {
+		if strings.ToLower(arg) == "replace" {
+			replace = true
+			continue
+		}
+		n, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil || n < 0 {
+			fmt.Printf("(error) invalid ttl %q\n", arg)
+			return
+		}
+		ttl = n
+	}
+
+	payload, err := ioutil.ReadFile(file)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	cmd := []interface{}{"RESTORE", key, ttl, string(payload)}
+	if replace {
+		cmd = append(cmd, "REPLACE")
+	}
+	r, err := client.Do(cmd...).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	printReply(0, r, mode)
+	fmt.Printf("\n")
+}
+
+// writeFileAtomic writes data to a temporary file next to name and renames
+// it into place, so a failed write never leaves a truncated file behind.
+func writeFileAtomic(name string, data []byte, perm os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(name), "."+filepath.Base(name)+".tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Chmod(tmp, perm); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, name)
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// editKey opens a string, hash or RedisJSON value in $VISUAL or $EDITOR,
+// shows the diff and writes the edited value back after confirmation.
+// Hashes are edited as a JSON object of fields.
+// Usage: EDIT key
+func editKey(args []string) {
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be EDIT key")
+		return
+	}
+	key := args[0]
+	cliConnect()
+
+	typ, err := client.Type(key).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	before, err := editableValue(key, typ)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	after, err := runEditor(before)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	// editors add a final newline the value didn't have
+	if !strings.HasSuffix(before, "\n") {
+		after = strings.TrimSuffix(after, "\n")
+	}
+	if after == before {
+		fmt.Println("(no changes)")
+		return
+	}
+
+	fmt.Print(unifiedDiff(strings.Split(before, "\n"), strings.Split(after, "\n"), key, key+" (edited)"))
+	if !confirm(fmt.Sprintf("Write %s back?", key)) {
+		fmt.Println("(aborted)")
+		return
+	}
+
+	// refuse to overwrite changes made while the editor was open
+	current, err := editableValue(key, typ)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if current != before {
+		fmt.Printf("(error) %s changed while it was being edited, not writing it back\n", key)
+		return
+	}
+
+	if err := writeEdited(key, typ, before, after); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Println("OK")
+}
+
+// editableValue returns the value of key as the text to edit.
+func editableValue(key, typ string) (string, error) {
+	switch typ {
+	case "string":
+		return client.Get(key).Result()
+	case "hash":
+		fields, err := client.HGetAll(key).Result()
+		if err != nil {
+			return "", err
+		}
+		b, err := json.MarshalIndent(fields, "", "  ")
+		return string(b), err
+	case "ReJSON-RL":
+		s, err := client.Do("JSON.GET", key).String()
+		if err != nil {
+			return "", err
+		}
+		var b bytes.Buffer
+		if err := json.Indent(&b, []byte(s), "", "  "); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	case "none":
+		return "", fmt.Errorf("no such key %s", key)
+	}
+	return "", fmt.Errorf("can't edit a %s, only strings, hashes and JSON", typ)
+}
+
+// writeEdited stores the edited text of key.
+func writeEdited(key, typ, before, after string) error {
+	switch typ {
+	case "string":
+		// SET would drop the TTL
+		ttl, err := client.PTTL(key).Result()
+		if err != nil {
+			return err
+		}
+		if ttl < 0 {
+			ttl = 0
+		}
+		return client.Set(key, after, ttl).Err()
+	case "hash":
+		var old, fields map[string]string
+		if err := json.Unmarshal([]byte(before), &old); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(after), &fields); err != nil {
+			return fmt.Errorf("the hash must stay a JSON object of string fields: %v", err)
+		}
+		_, err := client.TxPipelined(func(pipe redis.Pipeliner) error {
+			for f := range old {
+				if _, ok := fields[f]; !ok {
+					pipe.HDel(key, f)
+				}
+			}
+			set := map[string]interface{}{}
+			for f, v := range fields {
+				if ov, ok := old[f]; !ok || ov != v {
+					set[f] = v
+				}
+			}
+			if len(set) > 0 {
+				pipe.HMSet(key, set)
+			}
+			return nil
+		})
+		return err
+	case "ReJSON-RL":
+		if !json.Valid([]byte(after)) {
+			return fmt.Errorf("the edited value is not valid JSON")
+		}
+		return client.Do("JSON.SET", key, ".", after).Err()
+	}
+	return fmt.Errorf("can't edit a %s", typ)
+}
+
+// runEditor opens text in the user's editor and returns the saved text.
+func runEditor(text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	ext := ".txt"
+	if strings.HasPrefix(strings.TrimSpace(text), "{") || strings.HasPrefix(strings.TrimSpace(text), "[") {
+		ext = ".json"
+	}
+	f, err := ioutil.TempFile("", "redis-cli-edit-*"+ext)
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	// $EDITOR may hold arguments, e.g. "code --wait"
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %v", fields[0], err)
+	}
+
+	b, err := ioutil.ReadFile(f.Name())
+	return string(b), err
+}
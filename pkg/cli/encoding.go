@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// encodingStats scans the keyspace and counts the keys of every type and
+// OBJECT ENCODING, so that encoding downgrades (listpack to hashtable,
+// intset to hashtable, embstr to raw...) show up.
+// Usage: ENCODING-STATS [pattern]
+func encodingStats(args []string) {
+	if len(args) > 1 {
+		fmt.Println("(error) invalid args. Should be ENCODING-STATS [pattern]")
+		return
+	}
+	pattern := ""
+	if len(args) == 1 {
+		pattern = lexer.TrimQuotes(args[0])
+	}
+	cliConnect()
+
+	counts := map[[2]string]int{}
+	total := 0
+	err := scanKeys(pattern, 1000, func(keys []string) error {
+		if len(keys) == 0 {
+			return nil
+		}
+		pipe := client.Pipeline()
+		defer pipe.Close()
+		types := make([]*redis.StatusCmd, len(keys))
+		encodings := make([]*redis.StringCmd, len(keys))
+		for i, k := range keys {
+			types[i] = pipe.Type(k)
+			encodings[i] = pipe.ObjectEncoding(k)
+		}
+		// keys deleted since the SCAN fail individually, skip them below
+		pipe.Exec()
+
+		for i := range keys {
+			typ, err := types[i].Result()
+			if err != nil || typ == "none" {
+				continue
+			}
+			enc, err := encodings[i].Result()
+			if err == redis.Nil {
+				continue
+			} else if err != nil {
+				return err
+			}
+			counts[[2]string{typ, enc}]++
+			total++
+		}
+		fmt.Printf("\rscanned %d keys", total)
+		return nil
+	})
+	fmt.Print("\r\033[K")
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if total == 0 {
+		fmt.Println("(no keys)")
+		return
+	}
+
+	var pairs [][2]string
+	for p := range counts {
+		pairs = append(pairs, p)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i][0] != pairs[j][0] {
+			return pairs[i][0] < pairs[j][0]
+		}
+		return counts[pairs[i]] > counts[pairs[j]]
+	})
+
+	var rows [][]string
+	for _, p := range pairs {
+		n := counts[p]
+		rows = append(rows, []string{p[0], p[1], strconv.Itoa(n), fmt.Sprintf("%.1f%%", float64(n)*100/float64(total))})
+	}
+	printTable([]string{"TYPE", "ENCODING", "KEYS", "SHARE"}, rows)
+	fmt.Printf("\n%d keys\n", total)
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// flagEnv maps flags to the environment variables they are read from.
+// Flags not listed here are read from REDISCLI_<NAME>, e.g. REDISCLI_RAW
+// for -raw or REDISCLI_NO_KEYS_GUARD for -no-keys-guard.
+var flagEnv = map[string]string{
+	"h":    "REDIS_HOST",
+	"p":    "REDIS_PORT",
+	"s":    "REDIS_SOCKET",
+	"a":    "REDISCLI_AUTH",
+	"user": "REDIS_USER",
+	"n":    "REDIS_DB",
+	"tls":  "REDIS_TLS",
+	"u":    "REDIS_URL",
+}
+
+// rcPath is the rc file holding default flag values, one "name value"
+// pair per line.
+var rcPath = path.Join(os.Getenv("HOME"), ".gorediscli_rc") // $HOME/.gorediscli_rc
+
+// rcConfig holds the settings of the rc file; a name may be repeated.
+var rcConfig = map[string][]string{}
+
+// rcSetting returns the last value of a setting of the rc file that is
+// not a flag, quotes removed.
+func rcSetting(name string) (string, bool) {
+	vs, ok := rcConfig[name]
+	if !ok {
+		return "", false
+	}
+	return lexer.TrimQuotes(vs[len(vs)-1]), true
+}
+
+func flagEnvName(name string) string {
+	if env, ok := flagEnv[name]; ok {
+		return env
+	}
+	return "REDISCLI_" + strings.ToUpper(strings.Replace(name, "-", "_", -1))
+}
+
+// loadRC reads the rc file. A missing file is not an error.
+func loadRC(name string) (map[string][]string, error) {
+	rc := map[string][]string{}
+	f, err := os.Open(name)
+	if os.IsNotExist(err) {
+		return rc, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		l := strings.TrimSpace(scanner.Text())
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		fields := strings.SplitN(l, " ", 2)
+		value := ""
+		if len(fields) == 2 {
+			value = strings.TrimSpace(fields[1])
+		}
+		rc[fields[0]] = append(rc[fields[0]], value)
+	}
+	return rc, scanner.Err()
+}
+
+// applyDefaults fills in the flags not given on the command line, first
+// from the environment and then from the rc file, so the precedence is
+// flag > env > rc file. A server URI is applied last.
+func applyDefaults() error {
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+
+	rc, err := loadRC(rcPath)
+	if err != nil {
+		return err
+	}
+	rcConfig = rc
+
+	var firstErr error
+	flag.VisitAll(func(f *flag.Flag) {
+		if explicit[f.Name] {
+			return
+		}
+		env := flagEnvName(f.Name)
+		if v, ok := os.LookupEnv(env); ok {
+			if err := f.Value.Set(v); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("invalid value %q for %s: %v", v, env, err)
+			}
+			return
+		}
+		if vs, ok := rc[f.Name]; ok && !rcLists[f.Name] {
+			v := vs[len(vs)-1]
+			if err := f.Value.Set(v); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("invalid value %q for %s in %s: %v", v, f.Name, rcPath, err)
+			}
+		}
+	})
+	if firstErr != nil {
+		return firstErr
+	}
+
+	if *uri != "" {
+		return applyURI(*uri, explicit)
+	}
+	return nil
+}
+
+// applyURI sets the connection flags from a redis://, rediss:// or
+// unix:// URI, leaving alone the flags given on the command line.
+func applyURI(raw string, explicit map[string]bool) error {
+	values, err := parseURI(raw)
+	if err != nil {
+		return err
+	}
+	for name, value := range values {
+		if !explicit[name] {
+			flag.Set(name, value)
+		}
+	}
+	return nil
+}
+
+// parseURI returns the connection flags set by a redis://, rediss:// or
+// unix:// URI, by flag name.
+func parseURI(raw string) (map[string]string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI: %v", err)
+	}
+
+	values := map[string]string{}
+	switch u.Scheme {
+	case "redis", "rediss":
+		values["tls"] = strconv.FormatBool(u.Scheme == "rediss")
+		if h := u.Hostname(); h != "" {
+			values["h"] = h
+		}
+		if p := u.Port(); p != "" {
+			values["p"] = p
+		}
+		if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+			if _, err := strconv.Atoi(db); err != nil {
+				return nil, fmt.Errorf("invalid database %q in URI", db)
+			}
+			values["n"] = db
+		}
+	case "unix":
+		values["s"] = u.Path
+	default:
+		return nil, fmt.Errorf("unsupported URI scheme %q", u.Scheme)
+	}
+
+	if db := u.Query().Get("db"); db != "" {
+		if _, err := strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid database %q in URI", db)
+		}
+		values["n"] = db
+	}
+	if u.User != nil {
+		if name := u.User.Username(); name != "" {
+			values["user"] = name
+		}
+		if passwd, ok := u.User.Password(); ok {
+			values["a"] = passwd
+		}
+	}
+	return values, nil
+}
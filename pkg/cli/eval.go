@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"strconv"
+	"strings"
+)
+
+// evalCommands take a script, SHA or function name followed by numkeys.
+var evalCommands = map[string]bool{
+	"eval":       true,
+	"evalsha":    true,
+	"eval_ro":    true,
+	"evalsha_ro": true,
+	"fcall":      true,
+	"fcall_ro":   true,
+}
+
+// inferNumKeys rewrites EVAL script key [key ...] , arg [arg ...] into
+// EVAL script numkeys key [key ...] arg [arg ...], counting the keys
+// before the comma as redis-cli --eval does, and adds a numkeys of 0 to a
+// bare EVAL script. A command line that gives numkeys, where a "," is an
+// argument like any other, is returned as it is, as are other commands.
+func inferNumKeys(cmds []string) []string {
+	if len(cmds) < 2 || !evalCommands[strings.ToLower(cmds[0])] {
+		return cmds
+	}
+	script := 1
+	if cmds[1] == "--script" {
+		script = 2
+	}
+	if len(cmds) == script+1 {
+		return append(cmds, "0")
+	}
+	if _, err := strconv.Atoi(cmds[script+1]); err == nil {
+		return cmds
+	}
+
+	comma := -1
+	for i := script + 1; i < len(cmds); i++ {
+		if cmds[i] == "," {
+			comma = i
+			break
+		}
+	}
+	if comma < 0 {
+		return cmds
+	}
+	keys := cmds[script+1 : comma]
+	out := make([]string, 0, len(cmds))
+	out = append(out, cmds[:script+1]...)
+	out = append(out, strconv.Itoa(len(keys)))
+	out = append(out, keys...)
+	return append(out, cmds[comma+1:]...)
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInferNumKeys(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		// numkeys is counted from the keys before the comma
+		{"EVAL s k1 k2 , a1 a2", "EVAL s 2 k1 k2 a1 a2"},
+		{"EVAL s , a1", "EVAL s 0 a1"},
+		{"EVAL s k1 ,", "EVAL s 1 k1"},
+		{"evalsha_ro sha k1 , a1", "evalsha_ro sha 1 k1 a1"},
+		{"EVAL --script f.lua k1 , a1", "EVAL --script f.lua 1 k1 a1"},
+		{"EVAL s", "EVAL s 0"},
+		// a given numkeys is kept, and so is a "," argument
+		{"EVAL s 1 k1 , a1", "EVAL s 1 k1 , a1"},
+		{"EVAL s 0 , ,", "EVAL s 0 , ,"},
+		{"EVAL --script f.lua 2 k1 k2 ,", "EVAL --script f.lua 2 k1 k2 ,"},
+		{"EVAL s k1 a1", "EVAL s k1 a1"},
+		{"SET k , v", "SET k , v"},
+	}
+	for _, tt := range tests {
+		got := inferNumKeys(strings.Fields(tt.line))
+		if want := strings.Fields(tt.want); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %q, want %q", tt.line, got, want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// ttlBuckets are the upper bounds of the TTL histogram of EXPIRY-REPORT.
+var ttlBuckets = []struct {
+	name  string
+	limit time.Duration
+}{
+	{"< 1m", time.Minute},
+	{"1m - 10m", 10 * time.Minute},
+	{"10m - 1h", time.Hour},
+	{"1h - 1d", 24 * time.Hour},
+	{"1d - 7d", 7 * 24 * time.Hour},
+	{">= 7d", 0},
+}
+
+var errSampled = errors.New("enough samples")
+
+// expiryReport samples expired_keys and evicted_keys over a window and
+// builds a TTL histogram of up to --samples keys found by SCAN.
+// Usage: EXPIRY-REPORT [pattern] [--window seconds] [--samples n]
+func expiryReport(args []string) {
+	const usage = "(error) invalid args. Should be EXPIRY-REPORT [pattern] [--window seconds] [--samples n]"
+	pattern := ""
+	window := 5 * time.Second
+	samples := 10000
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		if (opt == "--window" || opt == "--samples") && i+1 < len(args) {
+			v, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || v <= 0 {
+				fmt.Println(usage)
+				return
+			}
+			if opt == "--window" {
+				window = time.Duration(v * float64(time.Second))
+			} else {
+				samples = int(v)
+			}
+			i++
+		} else if pattern == "" {
+			pattern = lexer.TrimQuotes(args[i])
+		} else {
+			fmt.Println(usage)
+			return
+		}
+	}
+	cliConnect()
+
+	before, err := fetchInfo("stats")
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	start := time.Now()
+
+	// the TTL sampling runs during the window
+	hist := make([]int, len(ttlBuckets))
+	persistent, sampled := 0, 0
+	err = scanKeys(pattern, 1000, func(keys []string) error {
+		if len(keys) > samples-sampled {
+			keys = keys[:samples-sampled]
+		}
+		pipe := client.Pipeline()
+		defer pipe.Close()
+		ttls := make([]*redis.DurationCmd, len(keys))
+		for i, k := range keys {
+			ttls[i] = pipe.PTTL(k)
+		}
+		pipe.Exec()
+
+		for _, c := range ttls {
+			ttl, err := c.Result()
+			if err != nil || ttl == -2*time.Millisecond {
+				// gone since the SCAN
+				continue
+			}
+			sampled++
+			if ttl < 0 {
+				persistent++
+				continue
+			}
+			for b, bucket := range ttlBuckets {
+				if bucket.limit == 0 || ttl < bucket.limit {
+					hist[b]++
+					break
+				}
+			}
+		}
+		if sampled >= samples {
+			return errSampled
+		}
+		return nil
+	})
+	if err != nil && err != errSampled {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	if wait := window - time.Since(start); wait > 0 {
+		time.Sleep(wait)
+	}
+	after, err := fetchInfo("stats")
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	elapsed := time.Since(start).Seconds()
+
+	fmt.Printf("over %.1fs:\n", elapsed)
+	for _, field := range []string{"expired_keys", "evicted_keys", "expired_stale_perc", "expired_time_cap_reached_count"} {
+		if after.Get(field) == "" {
+			continue
+		}
+		if strings.HasSuffix(field, "_keys") {
+			delta := after.Int(field) - before.Int(field)
+			fmt.Printf("  %-32s %d (%.1f/s)\n", field, delta, float64(delta)/elapsed)
+		} else {
+			fmt.Printf("  %-32s %s\n", field, after.Get(field))
+		}
+	}
+
+	fmt.Printf("\nTTL of %d sampled keys:\n", sampled)
+	if sampled == 0 {
+		return
+	}
+	rows := [][]string{{"no TTL", strconv.Itoa(persistent), ttlBar(persistent, sampled)}}
+	for b, bucket := range ttlBuckets {
+		rows = append(rows, []string{bucket.name, strconv.Itoa(hist[b]), ttlBar(hist[b], sampled)})
+	}
+	printTable([]string{"TTL", "KEYS", ""}, rows)
+}
+
+// ttlBar draws n out of total as a bar of up to 40 characters.
+func ttlBar(n, total int) string {
+	return fmt.Sprintf("%s %.1f%%", strings.Repeat("#", n*40/total), float64(n)*100/float64(total))
+}
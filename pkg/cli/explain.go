@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// explain describes what a command would do without sending it: its
+// documentation, the COMMAND flags and key positions, the slot and node
+// its keys route to, and whether the server version, the client side
+// guards and the ACL of the current user let it run.
+// Usage: EXPLAIN command [arg ...]
+func explain(args []string) {
+	if len(args) == 0 {
+		fmt.Println("(error) invalid args. Should be EXPLAIN command [arg ...]")
+		return
+	}
+	cmds := make([]string, len(args))
+	for i, a := range args {
+		cmds[i] = lexer.TrimQuotes(a)
+	}
+	cliConnect()
+
+	name := strings.ToUpper(cmds[0])
+	d, documented := lookupCommandDoc(cmds)
+	info := commandInfo(cmds[0])
+	if !documented && info == nil {
+		fmt.Printf("(error) unknown command %s\n", name)
+		return
+	}
+
+	fmt.Println()
+	if documented {
+		fmt.Printf("  %s\n", strings.TrimSpace(d.Name+" "+d.Args))
+		fmt.Printf("  summary: %s\n", d.Summary)
+		fmt.Printf("  group: %s\n", d.Group)
+		if d.Complexity != "" {
+			fmt.Printf("  complexity: %s\n", d.Complexity)
+		}
+	} else {
+		fmt.Printf("  %s\n", name)
+	}
+
+	if info != nil {
+		fmt.Printf("  flags: %s\n", strings.Join(info.Flags, " "))
+		if info.FirstKeyPos > 0 {
+			fmt.Printf("  key spec: first %d, last %d, step %d\n", info.FirstKeyPos, info.LastKeyPos, info.StepCount)
+		} else if _, ok := numKeysCommands[strings.ToLower(cmds[0])]; !ok {
+			fmt.Println("  key spec: no keys")
+		}
+		fmt.Printf("  writes: %s\n", yesNo(hasFlag(info, "write")))
+	} else {
+		fmt.Println("  flags: unknown, the server doesn't answer COMMAND")
+	}
+
+	keys := commandKeys(cmds)
+	// without COMMAND, the documented syntax tells where a single key is
+	if keys == nil && info == nil && documented && strings.HasPrefix(d.Args, "key") && len(cmds) > 1 {
+		keys = cmds[1:2]
+	}
+	fmt.Printf("  routing: %s\n", explainRouting(keys))
+
+	if serverVersion != "" {
+		if documented && d.Since != "" && compareVersions(serverVersion, d.Since) < 0 {
+			fmt.Printf("  version: needs Redis %s, the server runs %s\n", d.Since, serverVersion)
+		} else if documented && d.Since != "" {
+			fmt.Printf("  version: since Redis %s, the server runs %s\n", d.Since, serverVersion)
+		}
+		if dep, ok := deprecatedCommands[d.Name]; documented && ok && compareVersions(serverVersion, dep.since) >= 0 {
+			fmt.Printf("  deprecated: since Redis %s, use %s instead\n", dep.since, dep.replacement)
+		}
+	}
+
+	if err := checkAllowed(cmds); err != nil {
+		fmt.Printf("  client guards: refused, %s\n", err.Error())
+	} else if *allowList != "" || *readOnly || connReadOnly {
+		fmt.Println("  client guards: allowed")
+	}
+	if err := checkSlots(cmds); err != nil {
+		fmt.Printf("  cluster: refused, %s\n", err.Error())
+	}
+
+	if serverVersion != "" && compareVersions(serverVersion, "7.0") >= 0 {
+		who, err := client.Do("ACL", "WHOAMI").Result()
+		if err == nil {
+			ok, reason, err := aclDryRun(fmt.Sprint(who), cmds)
+			switch {
+			case err != nil:
+				fmt.Printf("  acl: unknown, %s\n", err.Error())
+			case ok:
+				fmt.Printf("  acl: allowed for user %s\n", who)
+			default:
+				fmt.Printf("  acl: denied for user %s, %s\n", who, reason)
+			}
+		}
+	}
+}
+
+// explainRouting describes the slots and nodes keys map to.
+func explainRouting(keys []string) string {
+	if !clusterEnabled {
+		return addr()
+	}
+	if len(keys) == 0 {
+		return "any node, the command has no keys"
+	}
+
+	bySlot := map[int][]string{}
+	var slots []int
+	for _, k := range keys {
+		s := keySlot(k)
+		if _, ok := bySlot[s]; !ok {
+			slots = append(slots, s)
+		}
+		bySlot[s] = append(bySlot[s], k)
+	}
+	sort.Ints(slots)
+
+	nodes, _ := fetchClusterNodes()
+	parts := make([]string, len(slots))
+	for i, s := range slots {
+		node := "no node"
+		if owner := slotOwner(nodes, s); owner != nil {
+			node = owner.Addr
+		}
+		parts[i] = fmt.Sprintf("%s -> slot %d on %s", strings.Join(bySlot[s], " "), s, node)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
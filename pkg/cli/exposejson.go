@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis"
+)
+
+// exposeMaxItems caps the elements returned for a collection and the keys
+// listed by the index.
+const exposeMaxItems = 1000
+
+var errExposeFull = errors.New("enough keys")
+
+// parseExpose splits an -expose-json spec such as ":8090:cache:*" into
+// the listen address and the key pattern.
+func parseExpose(spec string) (string, string, error) {
+	i := strings.Index(spec, ":")
+	if i >= 0 {
+		if j := strings.Index(spec[i+1:], ":"); j >= 0 {
+			listen, pattern := spec[:i+1+j], spec[i+2+j:]
+			if pattern != "" {
+				return listen, pattern, nil
+			}
+		}
+	}
+	return "", "", fmt.Errorf("invalid -expose-json %q, should be [host]:port:pattern", spec)
+}
+
+// serveExposeJSON serves the keys matching pattern read-only over HTTP:
+// GET / lists them and GET /<key> returns a key as JSON.
+func serveExposeJSON(spec string) error {
+	listen, pattern, err := parseExpose(spec)
+	if err != nil {
+		return err
+	}
+	cliConnect()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "read-only, use GET"})
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		if key == "" {
+			exposeIndex(w, pattern)
+			return
+		}
+		if !matchGlob(pattern, key) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no such key"})
+			return
+		}
+		exposeKey(w, key)
+	})
+
+	fmt.Printf("serving %s keys matching %q as JSON on http://%s/\n", addr(), pattern, listen)
+	return http.ListenAndServe(listen, nil)
+}
+
+func exposeIndex(w http.ResponseWriter, pattern string) {
+	keys := []string{}
+	err := scanKeys(pattern, 1000, func(batch []string) error {
+		keys = append(keys, batch...)
+		if len(keys) >= exposeMaxItems {
+			return errExposeFull
+		}
+		return nil
+	})
+	if err != nil && err != errExposeFull {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	truncated := len(keys) >= exposeMaxItems
+	if truncated {
+		keys = keys[:exposeMaxItems]
+	}
+	sort.Strings(keys)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"pattern":   pattern,
+		"keys":      keys,
+		"truncated": truncated,
+	})
+}
+
+func exposeKey(w http.ResponseWriter, key string) {
+	typ, err := client.Type(key).Result()
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+	if typ == "none" {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "no such key"})
+		return
+	}
+	ttl, _ := client.TTL(key).Result()
+
+	var value interface{}
+	truncated := false
+	switch typ {
+	case "string":
+		value, err = client.Get(key).Result()
+	case "hash":
+		fields := map[string]string{}
+		truncated, err = exposeScan(func(cursor uint64) *redis.ScanCmd {
+			return client.HScan(key, cursor, "", 100)
+		}, func(kv []string) int {
+			for i := 0; i+1 < len(kv); i += 2 {
+				fields[kv[i]] = kv[i+1]
+			}
+			return len(fields)
+		})
+		if len(fields) > exposeMaxItems {
+			names := make([]string, 0, len(fields))
+			for f := range fields {
+				names = append(names, f)
+			}
+			sort.Strings(names)
+			for _, f := range names[exposeMaxItems:] {
+				delete(fields, f)
+			}
+		}
+		value = fields
+	case "list":
+		var items []string
+		items, err = client.LRange(key, 0, exposeMaxItems).Result()
+		if len(items) > exposeMaxItems {
+			items, truncated = items[:exposeMaxItems], true
+		}
+		value = items
+	case "set":
+		seen := map[string]bool{}
+		truncated, err = exposeScan(func(cursor uint64) *redis.ScanCmd {
+			return client.SScan(key, cursor, "", 100)
+		}, func(batch []string) int {
+			for _, m := range batch {
+				seen[m] = true
+			}
+			return len(seen)
+		})
+		members := make([]string, 0, len(seen))
+		for m := range seen {
+			members = append(members, m)
+		}
+		sort.Strings(members)
+		if len(members) > exposeMaxItems {
+			members = members[:exposeMaxItems]
+		}
+		value = members
+	case "zset":
+		var zs []redis.Z
+		zs, err = client.ZRangeWithScores(key, 0, exposeMaxItems).Result()
+		if len(zs) > exposeMaxItems {
+			zs, truncated = zs[:exposeMaxItems], true
+		}
+		members := make([]map[string]interface{}, len(zs))
+		for i, z := range zs {
+			members[i] = map[string]interface{}{"member": z.Member, "score": z.Score}
+		}
+		value = members
+	case "stream":
+		var entries []redis.XMessage
+		entries, err = client.XRangeN(key, "-", "+", exposeMaxItems+1).Result()
+		if len(entries) > exposeMaxItems {
+			entries, truncated = entries[:exposeMaxItems], true
+		}
+		msgs := make([]map[string]interface{}, len(entries))
+		for i, e := range entries {
+			msgs[i] = map[string]interface{}{"id": e.ID, "values": e.Values}
+		}
+		value = msgs
+	default:
+		err = fmt.Errorf("unsupported type %s", typ)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"key":       key,
+		"type":      typ,
+		"ttl":       int64(ttl.Seconds()),
+		"value":     value,
+		"truncated": truncated,
+	})
+}
+
+// exposeScan iterates a hash or a set with scan, HSCAN or SSCAN, rather
+// than reading it whole, until the iteration ends or add, which receives
+// every batch and returns the elements collected so far, has more than
+// exposeMaxItems. It reports whether elements were left out.
+func exposeScan(scan func(cursor uint64) *redis.ScanCmd, add func(batch []string) int) (bool, error) {
+	var cursor uint64
+	for {
+		batch, next, err := scan(cursor).Result()
+		if err != nil {
+			return false, err
+		}
+		n := add(batch)
+		if next == 0 {
+			return n > exposeMaxItems, nil
+		}
+		if n >= exposeMaxItems {
+			return true, nil
+		}
+		cursor = next
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// findInMax caps the matches FIND-IN reports.
+const findInMax = 100
+
+// findIn looks for value inside a key according to its type and reports
+// where it appears: list positions, set membership, sorted set rank and
+// score, hash fields, stream entries or string offsets.
+// Usage: FIND-IN key value
+func findIn(args []string) {
+	if len(args) != 2 {
+		fmt.Println("(error) invalid args. Should be FIND-IN key value")
+		return
+	}
+	cliConnect()
+	key, value := lexer.TrimQuotes(args[0]), lexer.TrimQuotes(args[1])
+
+	typ, err := client.Type(key).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	var found []string
+	switch typ {
+	case "none":
+		fmt.Println("(no such key)")
+		return
+	case "string":
+		s, err := client.Get(key).Result()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		for off := 0; len(found) < findInMax; {
+			i := strings.Index(s[off:], value)
+			if i < 0 || value == "" {
+				break
+			}
+			found = append(found, fmt.Sprintf("at byte offset %d", off+i))
+			off += i + 1
+		}
+	case "list":
+		found, err = findInList(key, value)
+	case "set":
+		var ok bool
+		if ok, err = client.SIsMember(key, value).Result(); ok {
+			found = append(found, "is a member")
+		}
+	case "zset":
+		var score float64
+		score, err = client.ZScore(key, value).Result()
+		if err == nil {
+			rank, _ := client.ZRank(key, value).Result()
+			found = append(found, fmt.Sprintf("at rank %d with score %s", rank, strconv.FormatFloat(score, 'f', -1, 64)))
+		} else if err == redis.Nil {
+			err = nil
+		}
+	case "hash":
+		var h map[string]string
+		h, err = client.HGetAll(key).Result()
+		for f, v := range h {
+			if f == value {
+				found = append(found, fmt.Sprintf("is field %q", f))
+			}
+			if v == value {
+				found = append(found, fmt.Sprintf("is the value of field %q", f))
+			}
+		}
+		sort.Strings(found)
+	case "stream":
+		found, err = findInStream(key, value)
+	default:
+		fmt.Printf("(error) unsupported type %s\n", typ)
+		return
+	}
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	if len(found) == 0 {
+		fmt.Printf("%q not found in %s %s\n", value, typ, key)
+		return
+	}
+	for i, f := range found {
+		fmt.Printf("%d) %s\n", i+1, f)
+	}
+	if len(found) >= findInMax {
+		fmt.Printf("stopped after %d matches\n", findInMax)
+	}
+}
+
+// findInList returns the positions of value in a list with LPOS, or by
+// reading the list in chunks on servers older than 6.0.6.
+func findInList(key, value string) ([]string, error) {
+	var found []string
+	r, err := client.Do("LPOS", key, value, "COUNT", findInMax).Result()
+	if err == nil {
+		for _, pos := range r.([]interface{}) {
+			found = append(found, fmt.Sprintf("at index %v", pos))
+		}
+		return found, nil
+	}
+	if !strings.Contains(strings.ToLower(err.Error()), "unknown command") {
+		return nil, err
+	}
+
+	const chunk = 1000
+	for start := int64(0); len(found) < findInMax; start += chunk {
+		items, err := client.LRange(key, start, start+chunk-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range items {
+			if v == value && len(found) < findInMax {
+				found = append(found, fmt.Sprintf("at index %d", start+int64(i)))
+			}
+		}
+		if len(items) < chunk {
+			break
+		}
+	}
+	return found, nil
+}
+
+// findInStream scans a stream in batches for entries holding value as a
+// field or a value.
+func findInStream(key, value string) ([]string, error) {
+	var found []string
+	start := "-"
+	for len(found) < findInMax {
+		entries, err := client.XRangeN(key, start, "+", 1000).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.ID == start {
+				continue
+			}
+			for f, v := range e.Values {
+				if f == value || fmt.Sprint(v) == value {
+					found = append(found, fmt.Sprintf("in entry %s, field %q", e.ID, f))
+				}
+			}
+		}
+		if len(entries) < 1000 {
+			break
+		}
+		start = entries[len(entries)-1].ID
+	}
+	return found, nil
+}
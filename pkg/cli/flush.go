@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/info"
+)
+
+// flushSafe runs FLUSHDB or FLUSHALL ASYNC on the connected instance
+// after showing its size and having the user type its address. Masters of
+// a cluster are refused unless --i-know is given.
+// Usage: FLUSHDB-SAFE [--i-know] / FLUSHALL-SAFE [--i-know]
+func flushSafe(flushCmd string, args []string) {
+	iKnow := false
+	for _, arg := range args {
+		if strings.ToLower(arg) != "--i-know" {
+			fmt.Printf("(error) invalid args. Should be %s-SAFE [--i-know]\n", flushCmd)
+			return
+		}
+		iKnow = true
+	}
+
+	instance := addr()
+	// every pooled connection selects the current database
+	c := conn.NewSingle(clientOptions(instance, *auth))
+	defer c.Close()
+
+	s, err := c.Info().Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	srv := info.Parse(s)
+	if srv.Get("cluster_enabled") == "1" && srv.Get("role") == "master" && !iKnow {
+		fmt.Printf("(error) %s is a cluster master, pass --i-know to flush it anyway\n", instance)
+		return
+	}
+
+	size, err := c.DBSize().Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if flushCmd == "FLUSHALL" {
+		fmt.Printf("%s holds %d keys in all databases\n", instance, srv.TotalKeys())
+	} else {
+		fmt.Printf("%s holds %d keys in database %d\n", instance, size, *dbn)
+	}
+
+	if !*assumeYes {
+		answer, err := askUser(fmt.Sprintf("Type %s to confirm %s: ", instance, flushCmd))
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		if answer != instance {
+			fmt.Println("aborted")
+			return
+		}
+	}
+
+	r, err := c.Do(flushCmd, "ASYNC").Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	printReply(0, r, mode)
+	fmt.Printf("\n")
+}
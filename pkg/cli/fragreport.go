@@ -0,0 +1,170 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/holys/redis-cli/pkg/info"
+)
+
+// memStats is the MEMORY STATS reply with the INFO memory fields, for
+// the servers and fields MEMORY STATS lacks.
+type memStats struct {
+	stats map[string]interface{}
+	info  info.Reply
+}
+
+// bytes returns the MEMORY STATS field stat, or else the INFO field.
+func (m memStats) bytes(stat, field string) int64 {
+	if v, ok := m.stats[stat].(int64); ok {
+		return v
+	}
+	return m.info.Int(field)
+}
+
+// ratio returns the MEMORY STATS field stat, a double sent as a string,
+// or else the INFO field.
+func (m memStats) ratio(stat, field string) float64 {
+	if s, ok := m.stats[stat].(string); ok {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return m.info.Float(field)
+}
+
+// fragReport explains where the memory of the server goes: the
+// fragmentation at each layer between the dataset and the RSS, the
+// overhead of buffers and caches, and the likely causes of what looks
+// wrong, in plain words.
+// Usage: FRAG-REPORT
+func fragReport(args []string) {
+	if len(args) != 0 {
+		fmt.Println("(error) invalid args. Should be FRAG-REPORT")
+		return
+	}
+	cliConnect()
+
+	mem, err := fetchInfo("memory")
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if mem.Get("used_memory") == "" {
+		fmt.Println("(error) INFO memory has no used_memory field")
+		return
+	}
+	// MEMORY STATS is 4.0 or later, INFO covers the rest
+	r, _ := client.Do("MEMORY", "STATS").Result()
+	m := memStats{replyFields(r), mem}
+
+	used := m.bytes("total.allocated", "used_memory")
+	rss := mem.Int("used_memory_rss")
+	peak := m.bytes("peak.allocated", "used_memory_peak")
+	fmt.Printf("%sused%s %s, rss %s, peak %s", highlightStart, highlightEnd, humanBytes(used), humanBytes(rss), humanBytes(peak))
+	if max := mem.Int("maxmemory"); max > 0 {
+		fmt.Printf(", maxmemory %s (%s used)", humanBytes(max), percent(used, max))
+	}
+	if alloc := mem.Get("mem_allocator"); alloc != "" {
+		fmt.Printf(", allocator %s", alloc)
+	}
+	fmt.Println()
+
+	// each layer, from the allocations of redis to the RSS of the process
+	fmt.Printf("\n%sfragmentation%s\n", highlightStart, highlightEnd)
+	layers := [][]string{
+		{"total", "rss / used", fmt.Sprintf("%.2f", m.ratio("fragmentation", "mem_fragmentation_ratio")), signedBytes(m.bytes("fragmentation.bytes", "mem_fragmentation_bytes"))},
+		{"allocator", "active / allocated", fmt.Sprintf("%.2f", m.ratio("allocator-fragmentation.ratio", "allocator_frag_ratio")), signedBytes(m.bytes("allocator-fragmentation.bytes", "allocator_frag_bytes"))},
+		{"allocator rss", "resident / active", fmt.Sprintf("%.2f", m.ratio("allocator.rss-ratio", "allocator_rss_ratio")), signedBytes(m.bytes("allocator.rss-bytes", "allocator_rss_bytes"))},
+		{"rss overhead", "rss / resident", fmt.Sprintf("%.2f", m.ratio("rss-overhead.ratio", "rss_overhead_ratio")), signedBytes(m.bytes("rss-overhead.bytes", "rss_overhead_bytes"))},
+	}
+	printTable([]string{"LAYER", "MEASURE", "RATIO", "BYTES"}, layers)
+
+	fmt.Printf("\n%soverhead%s\n", highlightStart, highlightEnd)
+	backlog := m.bytes("replication.backlog", "mem_replication_backlog")
+	replicaBufs := m.bytes("clients.slaves", "mem_clients_slaves")
+	clientBufs := m.bytes("clients.normal", "mem_clients_normal")
+	parts := []struct {
+		name  string
+		bytes int64
+	}{
+		{"dataset", m.bytes("dataset.bytes", "used_memory_dataset")},
+		{"startup", m.bytes("startup.allocated", "used_memory_startup")},
+		{"replication backlog", backlog},
+		{"replica output buffers", replicaBufs},
+		{"client buffers", clientBufs},
+		{"cluster links", m.bytes("cluster.links", "mem_cluster_links")},
+		{"AOF buffer", m.bytes("aof.buffer", "mem_aof_buffer")},
+		{"Lua and function caches", m.bytes("lua.caches", "used_memory_scripts") + m.bytes("functions.caches", "")},
+	}
+	var rows [][]string
+	for _, p := range parts {
+		rows = append(rows, []string{p.name, humanBytes(p.bytes), percent(p.bytes, used)})
+	}
+	printTable([]string{"PART", "BYTES", "OF USED"}, rows)
+
+	fmt.Printf("\n%shints%s\n", highlightStart, highlightEnd)
+	hints := fragHints(m, used, peak, replicaBufs, clientBufs, backlog)
+	if len(hints) == 0 {
+		hints = []string{"nothing stands out: fragmentation and buffers are within the usual range"}
+	}
+	for _, h := range hints {
+		fmt.Printf("- %s\n", h)
+	}
+}
+
+// fragMinBytes is the waste below which fragmentation isn't worth a hint.
+const fragMinBytes = 100 << 20
+
+// fragHints returns the likely causes of the fragmentation and overhead.
+func fragHints(m memStats, used, peak, replicaBufs, clientBufs, backlog int64) []string {
+	var hints []string
+	ratio := m.ratio("fragmentation", "mem_fragmentation_ratio")
+	switch {
+	case ratio > 0 && ratio < 1:
+		hints = append(hints, fmt.Sprintf("rss is below used memory (ratio %.2f): part of the process was swapped out, check the swap usage of the host, as swapped pages make every command slow", ratio))
+	case ratio > 1.5 && used < fragMinBytes:
+		hints = append(hints, fmt.Sprintf("the ratio of %.2f comes from the small dataset: the fixed rss of the process weighs more than the data, there is nothing to fix", ratio))
+	}
+
+	if f, b := m.ratio("allocator-fragmentation.ratio", "allocator_frag_ratio"), m.bytes("allocator-fragmentation.bytes", "allocator_frag_bytes"); f > 1.1 && b > fragMinBytes {
+		hint := fmt.Sprintf("the allocator wastes %s in partly used pages (ratio %.2f), typical after deleting or overwriting many values of mixed sizes", humanBytes(b), f)
+		if m.info.Get("active_defrag_running") != "" && m.info.Get("active_defrag_running") != "0" {
+			hint += "; active defrag is running on it"
+		} else {
+			hint += "; CONFIG SET activedefrag yes moves the values to compact the pages (jemalloc only)"
+		}
+		hints = append(hints, hint)
+	}
+	if f, b := m.ratio("allocator.rss-ratio", "allocator_rss_ratio"), m.bytes("allocator.rss-bytes", "allocator_rss_bytes"); f > 1.1 && b > fragMinBytes {
+		hints = append(hints, fmt.Sprintf("the allocator holds %s of free pages it hasn't returned to the OS, MEMORY PURGE releases them", humanBytes(b)))
+	}
+	if f, b := m.ratio("rss-overhead.ratio", "rss_overhead_ratio"), m.bytes("rss-overhead.bytes", "rss_overhead_bytes"); f > 1.1 && b > fragMinBytes {
+		hints = append(hints, fmt.Sprintf("%s of rss is outside the allocator, usually pages copied on write while a fork saves an RDB or rewrites the AOF", humanBytes(b)))
+	}
+	if peak > used*3/2 && peak-used > fragMinBytes {
+		hints = append(hints, fmt.Sprintf("used memory is well below its peak of %s: the pages freed since then stay in rss until they are reused", humanBytes(peak)))
+	}
+
+	if clientBufs > used/10 && clientBufs > 32<<20 {
+		hints = append(hints, fmt.Sprintf("client buffers take %s (%s): slow readers such as subscribers, MONITOR or large replies, see omem in CLIENT LIST", humanBytes(clientBufs), percent(clientBufs, used)))
+	}
+	if replicaBufs > used/10 && replicaBufs > 32<<20 {
+		hints = append(hints, fmt.Sprintf("replica output buffers take %s: a replica is syncing or can't keep up, see INFO replication", humanBytes(replicaBufs)))
+	}
+	if backlog > used/10 && backlog > 32<<20 {
+		hints = append(hints, fmt.Sprintf("the replication backlog takes %s (%s), set by repl-backlog-size", humanBytes(backlog), percent(backlog, used)))
+	}
+	if n := m.info.Int("lazyfree_pending_objects"); n > 0 {
+		hints = append(hints, fmt.Sprintf("%d objects are waiting to be freed in the background (lazyfree)", n))
+	}
+	return hints
+}
+
+// signedBytes formats a byte count that may be negative.
+func signedBytes(n int64) string {
+	if n < 0 {
+		return "-" + humanBytes(-n)
+	}
+	return humanBytes(n)
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+const generateUsage = "GENERATE [--template tpl] [--type string|hash|list|set|zset] [--fields n] [--count n] [--size bytes] [--batch n]"
+
+// generateData creates synthetic keys from a template with pipelining.
+// {seq} in the template is replaced with the key number and {rand} with a
+// random number.
+// Usage: GENERATE [--template tpl] [--type t] [--fields n] [--count n] [--size bytes] [--batch n]
+func generateData(args []string) {
+	template, typ := "key:{seq}", "string"
+	fields, count, size, batch := 10, 1000, 16, 1000
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			fmt.Printf("(error) invalid args. Should be %s\n", generateUsage)
+			return
+		}
+		value := args[i+1]
+		var err error
+		switch strings.ToLower(args[i]) {
+		case "--template":
+			template = value
+		case "--type":
+			typ = strings.ToLower(value)
+		case "--fields":
+			fields, err = parseCount(value)
+		case "--count":
+			count, err = parseCount(value)
+		case "--size":
+			size, err = parseCount(value)
+		case "--batch":
+			batch, err = parseCount(value)
+		default:
+			err = fmt.Errorf("unknown option %s", args[i])
+		}
+		if err != nil {
+			fmt.Printf("(error) %s. Should be %s\n", err.Error(), generateUsage)
+			return
+		}
+	}
+	switch typ {
+	case "string", "hash", "list", "set", "zset":
+	default:
+		fmt.Printf("(error) unsupported type %q\n", typ)
+		return
+	}
+	if fields < 1 || batch < 1 {
+		fmt.Println("(error) --fields and --batch must be positive")
+		return
+	}
+	cliConnect()
+
+	start := time.Now()
+	for done := 0; done < count; {
+		pipe := client.Pipeline()
+		n := 0
+		for ; n < batch && done+n < count; n++ {
+			key := generateKey(template, done+n)
+			switch typ {
+			case "string":
+				pipe.Set(key, randomValue(size), 0)
+			case "hash":
+				values := map[string]interface{}{}
+				for f := 0; f < fields; f++ {
+					values["field"+strconv.Itoa(f)] = randomValue(size)
+				}
+				pipe.HMSet(key, values)
+			case "list":
+				pipe.RPush(key, randomValues(fields, size)...)
+			case "set":
+				pipe.SAdd(key, randomValues(fields, size)...)
+			case "zset":
+				pipe.ZAdd(key, randomMembers(fields, size)...)
+			}
+		}
+		if _, err := pipe.Exec(); err != nil {
+			fmt.Printf("\n(error) %s\n", err.Error())
+			pipe.Close()
+			return
+		}
+		pipe.Close()
+
+		done += n
+		fmt.Printf("\rgenerated %d/%d keys", done, count)
+	}
+
+	elapsed := time.Since(start)
+	fmt.Printf("\ngenerated %d %s keys in %s (%.0f keys/s)\n", count, typ, elapsed.Round(time.Millisecond), float64(count)/elapsed.Seconds())
+}
+
+// parseCount parses a positive count, accepting 1e6 style notation.
+func parseCount(s string) (int, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 || f != float64(int(f)) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return int(f), nil
+}
+
+func generateKey(template string, seq int) string {
+	key := strings.Replace(template, "{seq}", strconv.Itoa(seq), -1)
+	for strings.Contains(key, "{rand}") {
+		key = strings.Replace(key, "{rand}", strconv.Itoa(rand.Int()), 1)
+	}
+	return key
+}
+
+const valueChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+func randomValue(size int) string {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = valueChars[rand.Intn(len(valueChars))]
+	}
+	return string(b)
+}
+
+func randomValues(n, size int) []interface{} {
+	values := make([]interface{}, n)
+	for i := range values {
+		values[i] = randomValue(size)
+	}
+	return values
+}
+
+func randomMembers(n, size int) []redis.Z {
+	members := make([]redis.Z, n)
+	for i := range members {
+		members[i] = redis.Z{Score: rand.Float64() * 1000, Member: randomValue(size)}
+	}
+	return members
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// geoSearchCommands reply with members, optionally followed by distance,
+// hash and coordinates when asked for WITHDIST, WITHHASH and WITHCOORD.
+var geoSearchCommands = map[string]bool{
+	"geosearch":            true,
+	"georadius":            true,
+	"georadius_ro":         true,
+	"georadiusbymember":    true,
+	"georadiusbymember_ro": true,
+}
+
+// printGeo renders GEOPOS and GEOSEARCH-like replies as a table of
+// member, longitude, latitude and distance. It returns false, printing
+// nothing, in raw mode or when the reply isn't one it knows how to render.
+func printGeo(cmd string, args []interface{}, reply interface{}) bool {
+	arr, ok := reply.([]interface{})
+	if !ok || len(arr) == 0 || mode != format.Std {
+		return false
+	}
+
+	var rows [][]string
+	var coords [][2]string
+	switch {
+	case cmd == "geopos":
+		// GEOPOS key member [member ...]
+		if len(args) != len(arr)+2 {
+			return false
+		}
+		for i, v := range arr {
+			member := fmt.Sprint(args[i+2])
+			pos, ok := v.([]interface{})
+			if !ok || len(pos) != 2 {
+				rows = append(rows, []string{member, "(nil)", "(nil)", ""})
+				coords = append(coords, [2]string{})
+				continue
+			}
+			lon, lat := fmt.Sprint(pos[0]), fmt.Sprint(pos[1])
+			rows = append(rows, []string{member, lon, lat, ""})
+			coords = append(coords, [2]string{lon, lat})
+		}
+	case geoSearchCommands[cmd]:
+		withDist, withHash, withCoord := false, false, false
+		for _, a := range args {
+			switch strings.ToLower(fmt.Sprint(a)) {
+			case "withdist":
+				withDist = true
+			case "withhash":
+				withHash = true
+			case "withcoord":
+				withCoord = true
+			}
+		}
+		if !withDist && !withCoord {
+			return false
+		}
+
+		for _, v := range arr {
+			item, ok := v.([]interface{})
+			if !ok || len(item) == 0 {
+				return false
+			}
+			row := []string{fmt.Sprint(item[0]), "", "", ""}
+			var lon, lat string
+			i := 1
+			if withDist && i < len(item) {
+				row[3] = fmt.Sprint(item[i])
+				i++
+			}
+			if withHash {
+				i++
+			}
+			if withCoord && i < len(item) {
+				if pos, ok := item[i].([]interface{}); ok && len(pos) == 2 {
+					lon, lat = fmt.Sprint(pos[0]), fmt.Sprint(pos[1])
+					row[1], row[2] = lon, lat
+				}
+			}
+			rows = append(rows, row)
+			coords = append(coords, [2]string{lon, lat})
+		}
+	default:
+		return false
+	}
+
+	headers := []string{"MEMBER", "LONGITUDE", "LATITUDE", "DISTANCE"}
+	if *geoLinks {
+		headers = append(headers, "MAP")
+		for i, c := range coords {
+			link := ""
+			if c[0] != "" {
+				link = osmURL(c[0], c[1])
+			}
+			rows[i] = append(rows[i], link)
+		}
+	}
+	printTable(headers, rows)
+	return true
+}
+
+// osmURL returns an OpenStreetMap link centered on a point.
+func osmURL(lon, lat string) string {
+	return fmt.Sprintf("https://www.openstreetmap.org/?mlat=%s&mlon=%s#map=15/%s/%s", lat, lon, lat, lon)
+}
@@ -0,0 +1,79 @@
+package cli
+
+// matchGlob reports whether s matches a Redis glob-style pattern, as used
+// by KEYS and SCAN MATCH: * and ? wildcards, [abc], [^abc] and [a-z]
+// classes, and \ escapes.
+func matchGlob(pattern, s string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+			if len(pattern) == 1 {
+				return true
+			}
+			for i := 0; i <= len(s); i++ {
+				if matchGlob(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+		case '[':
+			if len(s) == 0 {
+				return false
+			}
+			pattern = pattern[1:]
+			not := len(pattern) > 0 && pattern[0] == '^'
+			if not {
+				pattern = pattern[1:]
+			}
+			match := false
+			for len(pattern) > 0 && pattern[0] != ']' {
+				if pattern[0] == '\\' && len(pattern) > 1 {
+					pattern = pattern[1:]
+					if pattern[0] == s[0] {
+						match = true
+					}
+				} else if len(pattern) > 2 && pattern[1] == '-' && pattern[2] != ']' {
+					lo, hi := pattern[0], pattern[2]
+					if lo > hi {
+						lo, hi = hi, lo
+					}
+					if s[0] >= lo && s[0] <= hi {
+						match = true
+					}
+					pattern = pattern[2:]
+				} else if pattern[0] == s[0] {
+					match = true
+				}
+				pattern = pattern[1:]
+			}
+			if len(pattern) > 0 {
+				pattern = pattern[1:] // skip ]
+			}
+			if match == not {
+				return false
+			}
+			s = s[1:]
+		case '\\':
+			if len(pattern) > 1 {
+				pattern = pattern[1:]
+			}
+			fallthrough
+		default:
+			if len(s) == 0 || pattern[0] != s[0] {
+				return false
+			}
+			s = s[1:]
+			pattern = pattern[1:]
+		}
+	}
+	return len(s) == 0
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"", "", true},
+		{"", "a", false},
+		{"*", "", true},
+		{"*", "anything", true},
+		{"user:*", "user:1", true},
+		{"user:*", "user:", true},
+		{"user:*", "users:1", false},
+		{"*:1", "user:1", true},
+		{"*:1", "user:12", false},
+		{"a**b", "axyzb", true},
+		{"*a*b*", "xxaxxbxx", true},
+		{"*a*b*", "xxbxxaxx", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-c]llo", "hbllo", true},
+		{"h[c-a]llo", "hbllo", true},
+		{"h[a-c]llo", "hdllo", false},
+		{`h[\]]llo`, "h]llo", true},
+		{`\*`, "*", true},
+		{`\*`, "a", false},
+		{`a\?`, "a?", true},
+		{`a\?`, "ab", false},
+		{"[", "a", false},
+		{"x[", "x", false},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+// TestMatchGlobKeys checks that matchGlob picks the keys KEYS picks on the
+// miniredis of -mock.
+func TestMatchGlobKeys(t *testing.T) {
+	m, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	keys := []string{"user:1", "user:12", "user:a", "users", "session:x", "h*llo", "hello", "hallo", "a?b", "axb"}
+	for _, k := range keys {
+		m.Set(k, "v")
+	}
+
+	c := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	defer c.Close()
+
+	for _, pattern := range []string{"*", "user:*", "user:?", "user:[0-9]*", "*s*", "h[ae]llo", "h[^e]llo", `h\*llo`, `a\?b`, "a?b"} {
+		want, err := c.Keys(pattern).Result()
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(want)
+
+		var got []string
+		for _, k := range keys {
+			if matchGlob(pattern, k) {
+				got = append(got, k)
+			}
+		}
+		sort.Strings(got)
+		if len(got) != len(want) {
+			t.Errorf("%s: matchGlob picks %q, KEYS %q", pattern, got, want)
+			continue
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Errorf("%s: matchGlob picks %q, KEYS %q", pattern, got, want)
+				break
+			}
+		}
+	}
+}
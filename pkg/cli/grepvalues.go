@@ -0,0 +1,190 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// grepMaxElements skips collections bigger than this, to keep GREP-VALUES
+// from reading huge keys.
+const grepMaxElements = 10000
+
+var errGrepDone = errors.New("limit reached")
+
+// grepValues scans the keyspace and searches the contents of string, hash
+// and list keys for a regular expression. It stops after --max-keys keys
+// or --max-matches matches and reads at most --rate keys per second.
+// Usage: GREP-VALUES regex [--key-pattern pattern] [--type string|hash|list] [--max-keys n] [--max-matches n] [--rate keys/s]
+func grepValues(args []string) {
+	const usage = "(error) invalid args. Should be GREP-VALUES regex [--key-pattern pattern] [--type string|hash|list] [--max-keys n] [--max-matches n] [--rate keys/s]"
+	var expr, keyPattern, onlyType string
+	maxKeys, maxMatches, rate := 100000, 100, 1000
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		if !strings.HasPrefix(opt, "--") {
+			if expr != "" {
+				fmt.Println(usage)
+				return
+			}
+			expr = lexer.TrimQuotes(args[i])
+			continue
+		}
+		if i+1 >= len(args) {
+			fmt.Println(usage)
+			return
+		}
+		v := lexer.TrimQuotes(args[i+1])
+		i++
+		switch opt {
+		case "--key-pattern":
+			keyPattern = v
+		case "--type":
+			onlyType = strings.ToLower(v)
+			if onlyType != "string" && onlyType != "hash" && onlyType != "list" {
+				fmt.Println(usage)
+				return
+			}
+		case "--max-keys", "--max-matches", "--rate":
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				fmt.Println(usage)
+				return
+			}
+			switch opt {
+			case "--max-keys":
+				maxKeys = n
+			case "--max-matches":
+				maxMatches = n
+			default:
+				rate = n
+			}
+		default:
+			fmt.Println(usage)
+			return
+		}
+	}
+	if expr == "" {
+		fmt.Println(usage)
+		return
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	cliConnect()
+
+	scanned, matches, skipped := 0, 0, 0
+	start := time.Now()
+	report := func(key, where, value string) {
+		matches++
+		fmt.Printf("%s%s: %s\n", key, where, excerpt(re, value))
+	}
+
+	err = scanKeys(keyPattern, 100, func(keys []string) error {
+		for _, key := range keys {
+			if scanned >= maxKeys || (maxMatches > 0 && matches >= maxMatches) {
+				return errGrepDone
+			}
+			scanned++
+			if rate > 0 {
+				if ahead := time.Duration(scanned)*time.Second/time.Duration(rate) - time.Since(start); ahead > 0 {
+					time.Sleep(ahead)
+				}
+			}
+
+			typ, err := client.Type(key).Result()
+			if err != nil {
+				return err
+			}
+			if onlyType != "" && typ != onlyType {
+				continue
+			}
+
+			switch typ {
+			case "string":
+				v, err := client.Get(key).Result()
+				if err == redis.Nil {
+					continue
+				} else if err != nil {
+					return err
+				}
+				if re.MatchString(v) {
+					report(key, "", v)
+				}
+			case "hash":
+				if n, _ := client.HLen(key).Result(); n > grepMaxElements {
+					skipped++
+					continue
+				}
+				h, err := client.HGetAll(key).Result()
+				if err != nil {
+					return err
+				}
+				for f, v := range h {
+					if re.MatchString(v) {
+						report(key, " "+f, v)
+					}
+				}
+			case "list":
+				if n, _ := client.LLen(key).Result(); n > grepMaxElements {
+					skipped++
+					continue
+				}
+				items, err := client.LRange(key, 0, -1).Result()
+				if err != nil {
+					return err
+				}
+				for i, v := range items {
+					if re.MatchString(v) {
+						report(key, fmt.Sprintf("[%d]", i), v)
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil && err != errGrepDone {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	fmt.Printf("\n%d matches in %d keys scanned", matches, scanned)
+	if skipped > 0 {
+		fmt.Printf(", %d keys with more than %d elements skipped", skipped, grepMaxElements)
+	}
+	if err == errGrepDone {
+		fmt.Print(", stopped at the limit")
+	}
+	fmt.Println()
+}
+
+// excerpt returns the part of v around the first match of re, with the
+// match highlighted.
+func excerpt(re *regexp.Regexp, v string) string {
+	const around = 30
+	loc := re.FindStringIndex(v)
+	if loc == nil {
+		return strconv.Quote(v)
+	}
+	from, to := loc[0]-around, loc[1]+around
+	prefix, suffix := "...", "..."
+	if from <= 0 {
+		from, prefix = 0, ""
+	}
+	if to >= len(v) {
+		to, suffix = len(v), ""
+	}
+	q := func(s string) string {
+		s = strconv.Quote(s)
+		return s[1 : len(s)-1]
+	}
+	return prefix + q(v[from:loc[0]]) + highlightStart + q(v[loc[0]:loc[1]]) + highlightEnd + q(v[loc[1]:to]) + suffix
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+// commandTable holds the COMMAND reply of a server, loaded once on first
+// use. A failure is kept too, so a server that doesn't answer COMMAND is
+// only asked once.
+type commandTable struct {
+	once  sync.Once
+	infos map[string]*redis.CommandInfo
+	err   error
+}
+
+// serverCommands is the COMMAND table of the active connection, kept by
+// its session.
+var serverCommands = &commandTable{}
+
+// commandInfo returns the COMMAND metadata of name, loading it from the
+// server on first use. It returns nil for unknown commands, and for all
+// of them when the server doesn't answer COMMAND.
+func commandInfo(name string) *redis.CommandInfo {
+	t, c := serverCommands, client
+	t.once.Do(func() {
+		r, err := c.Do("COMMAND").Result()
+		if err == nil {
+			t.infos, err = parseCommandInfos(r)
+		}
+		t.err = err
+	})
+	return t.infos[strings.ToLower(name)]
+}
+
+// parseCommandInfos reads a COMMAND reply. Only the name, arity, flags and
+// key positions are used: go-redis insists on the six fields of Redis 5
+// and before, when later versions add ACL categories, tips, key specs and
+// subcommands.
+func parseCommandInfos(reply interface{}) (map[string]*redis.CommandInfo, error) {
+	entries, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected COMMAND reply: %v", reply)
+	}
+	infos := make(map[string]*redis.CommandInfo, len(entries))
+	for _, e := range entries {
+		fields, ok := e.([]interface{})
+		if !ok || len(fields) < 6 {
+			continue
+		}
+		name, _ := fields[0].(string)
+		if name == "" {
+			continue
+		}
+		info := &redis.CommandInfo{Name: strings.ToLower(name)}
+		info.Arity = int8Field(fields[1])
+		flags, _ := fields[2].([]interface{})
+		for _, f := range flags {
+			if s, ok := f.(string); ok {
+				info.Flags = append(info.Flags, s)
+			}
+		}
+		info.FirstKeyPos = int8Field(fields[3])
+		info.LastKeyPos = int8Field(fields[4])
+		info.StepCount = int8Field(fields[5])
+		info.ReadOnly = hasFlag(info, "readonly")
+		infos[info.Name] = info
+	}
+	return infos, nil
+}
+
+// int8Field reads an integer field of a COMMAND entry.
+func int8Field(v interface{}) int8 {
+	n, _ := v.(int64)
+	return int8(n)
+}
+
+// hasFlag reports whether a command has one of the given COMMAND flags.
+func hasFlag(info *redis.CommandInfo, flags ...string) bool {
+	for _, f := range info.Flags {
+		for _, want := range flags {
+			if f == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkAllowed enforces -allow and -read-only on a command.
+func checkAllowed(cmds []string) error {
+	if len(cmds) == 0 {
+		return nil
+	}
+	name := strings.ToLower(cmds[0])
+
+	if *allowList != "" {
+		allowed := false
+		for _, a := range strings.Split(*allowList, ",") {
+			if strings.ToLower(strings.TrimSpace(a)) == name {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%s is not in the allowed commands", strings.ToUpper(name))
+		}
+	}
+
+	if *readOnly || connReadOnly {
+		info := commandInfo(name)
+		if info == nil || hasFlag(info, "write", "admin") {
+			return fmt.Errorf("%s is not allowed in read-only mode", strings.ToUpper(name))
+		}
+	}
+	return nil
+}
+
+// nonWrites are the commands, or command and subcommand, that never write
+// although COMMAND doesn't say so, being admin commands, or that
+// redis-cli sends to set up its connections and load the COMMAND table.
+// They are let through without asking COMMAND.
+var nonWrites = map[string]bool{
+	"command": true, "ping": true, "echo": true, "auth": true, "hello": true,
+	"select": true, "readonly": true, "readwrite": true, "asking": true,
+	"info": true, "role": true, "time": true, "monitor": true,
+	"multi": true, "exec": true, "discard": true,
+	"cluster info": true, "cluster nodes": true, "cluster slots": true,
+	"cluster shards": true, "cluster myid": true, "cluster keyslot": true,
+	"cluster countkeysinslot": true, "cluster getkeysinslot": true,
+	"cluster replicas": true, "cluster slaves": true, "cluster links": true,
+	"config get": true, "client list": true, "client info": true,
+	"client id": true, "client getname": true, "slowlog get": true,
+	"slowlog len": true, "latency latest": true, "latency history": true,
+	"latency doctor": true, "latency histogram": true, "debug object": true,
+	"acl whoami": true, "acl list": true, "acl users": true,
+	"acl getuser": true, "acl cat": true, "acl log": true, "acl dryrun": true,
+	"module list": true, "sentinel masters": true, "sentinel master": true,
+	"sentinel replicas": true, "sentinel slaves": true,
+	"sentinel sentinels": true, "sentinel get-master-addr-by-name": true,
+	"sentinel ckquorum": true, "sentinel myid": true,
+}
+
+// mayWrite reports whether a command may change the server: COMMAND flags
+// it write or admin, or doesn't know it.
+func mayWrite(cmds []string) bool {
+	name := strings.ToLower(cmds[0])
+	if nonWrites[name] || (len(cmds) > 1 && nonWrites[name+" "+strings.ToLower(cmds[1])]) {
+		return false
+	}
+	info := commandInfo(name)
+	return info == nil || hasFlag(info, "write", "admin")
+}
+
+// guardWrite enforces -read-only and -allow on the commands that may write,
+// whoever sends them. It is the conn.Check of every client, so that the
+// built-in commands, FLUSHDB-SAFE, RESTOREKEY or scripts, can't write past
+// the guards; what the user types goes through checkAllowed first, reads
+// included.
+func guardWrite(args []interface{}) error {
+	if len(args) == 0 || (*allowList == "" && !*readOnly && !connReadOnly) {
+		return nil
+	}
+	cmds := make([]string, len(args))
+	for i, a := range args {
+		cmds[i] = fmt.Sprint(a)
+	}
+	if !mayWrite(cmds) {
+		return nil
+	}
+	return checkAllowed(cmds)
+}
+
+// checkRawMode refuses, with -read-only or -allow, a mode that passes the
+// commands it reads to the server as they are.
+func checkRawMode(mode string) error {
+	if *allowList != "" || *readOnly || connReadOnly {
+		return fmt.Errorf("%s passes commands through unchecked, it can't be used with -read-only or -allow", mode)
+	}
+	return nil
+}
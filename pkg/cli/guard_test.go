@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alicebob/miniredis/v2/server"
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+)
+
+func TestParseCommandInfos(t *testing.T) {
+	// Redis 6 adds the ACL categories as a seventh field, Redis 7 the
+	// tips, key specs and subcommands.
+	reply := []interface{}{
+		[]interface{}{"get", int64(2), []interface{}{"readonly", "fast"}, int64(1), int64(1), int64(1),
+			[]interface{}{"@read", "@string", "@fast"}},
+		[]interface{}{"mset", int64(-3), []interface{}{"write", "denyoom"}, int64(1), int64(-1), int64(2),
+			[]interface{}{"@write", "@string", "@slow"}, []interface{}{}, []interface{}{}, []interface{}{}},
+		[]interface{}{"PING", int64(-1), []interface{}{"fast"}, int64(0), int64(0), int64(0)},
+		[]interface{}{"short", int64(1)},
+		"garbage",
+	}
+	infos, err := parseCommandInfos(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*redis.CommandInfo{
+		"get":  {Name: "get", Arity: 2, Flags: []string{"readonly", "fast"}, FirstKeyPos: 1, LastKeyPos: 1, StepCount: 1, ReadOnly: true},
+		"mset": {Name: "mset", Arity: -3, Flags: []string{"write", "denyoom"}, FirstKeyPos: 1, LastKeyPos: -1, StepCount: 2},
+		"ping": {Name: "ping", Arity: -1, Flags: []string{"fast"}},
+	}
+	if !reflect.DeepEqual(infos, want) {
+		for name, info := range infos {
+			t.Logf("%s: %+v", name, *info)
+		}
+		t.Errorf("got %d commands, want %v", len(infos), want)
+	}
+
+	if _, err := parseCommandInfos("ERR unknown command"); err == nil {
+		t.Error("a reply that is not an array should fail")
+	}
+}
+
+// TestReadOnlyFlushSafe checks that -read-only refuses the FLUSHDB that
+// FLUSHDB-SAFE sends on a connection of its own.
+func TestReadOnlyFlushSafe(t *testing.T) {
+	m, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	m.Set("k", "v")
+	// miniredis has no INFO, FLUSHDB-SAFE only needs the role
+	m.Server().Register("INFO", func(c *server.Peer, cmd string, args []string) {
+		c.WriteBulk("# Replication\r\nrole:master\r\n")
+	})
+
+	defer useCommands(t, []interface{}{
+		[]interface{}{"flushdb", int64(-1), []interface{}{"write"}, int64(0), int64(0), int64(0)},
+		[]interface{}{"dbsize", int64(1), []interface{}{"readonly", "fast"}, int64(0), int64(0), int64(0)},
+	})()
+	defer func(h, p string, tls, yes, ro bool, check func([]interface{}) error) {
+		*hostname, *port, *useTLS, *assumeYes, *readOnly, conn.Check = h, p, tls, yes, ro, check
+	}(*hostname, *port, *useTLS, *assumeYes, *readOnly, conn.Check)
+	*hostname, *port, *useTLS, *assumeYes = m.Host(), m.Port(), false, true
+	conn.Check = guardWrite
+
+	*readOnly = true
+	flushSafe("FLUSHDB", nil)
+	if !m.Exists("k") {
+		t.Fatal("FLUSHDB-SAFE flushed the database in read-only mode")
+	}
+
+	*readOnly = false
+	flushSafe("FLUSHDB", nil)
+	if m.Exists("k") {
+		t.Error("FLUSHDB-SAFE didn't flush the database")
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// crc16Table is the CRC16-CCITT (XModem) table cluster slots are hashed
+// with.
+var crc16Table = func() [256]uint16 {
+	var t [256]uint16
+	for i := range t {
+		crc := uint16(i) << 8
+		for b := 0; b < 8; b++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+		t[i] = crc
+	}
+	return t
+}()
+
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc = crc<<8 ^ crc16Table[byte(crc>>8)^s[i]]
+	}
+	return crc
+}
+
+// hashTag returns the hash tag of key, the part between the first { and
+// the next }, and false when it has none and the whole key is hashed.
+func hashTag(key string) (string, bool) {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return "", false
+	}
+	end := strings.IndexByte(key[start+1:], '}')
+	// an empty {} isn't a tag either
+	if end <= 0 {
+		return "", false
+	}
+	return key[start+1 : start+1+end], true
+}
+
+// keySlot returns the cluster slot of key, as CLUSTER KEYSLOT does.
+func keySlot(key string) int {
+	if tag, ok := hashTag(key); ok {
+		key = tag
+	}
+	return int(crc16(key)) % clusterSlots
+}
+
+// hashTagView shows the hash tag and slot of every key, and whether they
+// share a slot.
+// Usage: HASHTAG key [key ...]
+func hashTagView(args []string) {
+	if len(args) == 0 {
+		fmt.Println("(error) invalid args. Should be HASHTAG key [key ...]")
+		return
+	}
+	slots := map[int]bool{}
+	rows := make([][]string, len(args))
+	for i, arg := range args {
+		key := lexer.TrimQuotes(arg)
+		tag := "-"
+		if t, ok := hashTag(key); ok {
+			tag = "{" + t + "}"
+		}
+		slot := keySlot(key)
+		slots[slot] = true
+		rows[i] = []string{key, tag, strconv.Itoa(slot)}
+	}
+	printTable([]string{"KEY", "TAG", "SLOT"}, rows)
+
+	if len(args) > 1 {
+		if len(slots) == 1 {
+			fmt.Println("\nall keys share one slot, multi-key commands can use them together")
+		} else {
+			fmt.Printf("\nthe keys span %d slots, give them a common {tag} to use them together\n", len(slots))
+		}
+	}
+}
+
+// maxTagCheckKeys caps the keys listed per slot by TAG-CHECK.
+const maxTagCheckKeys = 3
+
+// tagCheck scans the keys matching pattern and reports whether they all
+// share a slot, or how they spread over slots.
+// Usage: TAG-CHECK pattern
+func tagCheck(args []string) {
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be TAG-CHECK pattern")
+		return
+	}
+	pattern := lexer.TrimQuotes(args[0])
+	cliConnect()
+
+	bySlot := map[int][]string{}
+	total := 0
+	err := scanKeys(pattern, 1000, func(keys []string) error {
+		for _, k := range keys {
+			s := keySlot(k)
+			bySlot[s] = append(bySlot[s], k)
+			total++
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	switch len(bySlot) {
+	case 0:
+		fmt.Printf("no keys match %s\n", pattern)
+		return
+	case 1:
+		for s, keys := range bySlot {
+			fmt.Printf("all %d keys matching %s share slot %d\n", total, pattern, s)
+			if tag, ok := hashTag(keys[0]); ok {
+				fmt.Printf("hash tag: {%s}\n", tag)
+			}
+		}
+		return
+	}
+
+	fmt.Printf("the %d keys matching %s span %d slots\n\n", total, pattern, len(bySlot))
+	slots := make([]int, 0, len(bySlot))
+	for s := range bySlot {
+		slots = append(slots, s)
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if len(bySlot[slots[i]]) != len(bySlot[slots[j]]) {
+			return len(bySlot[slots[i]]) > len(bySlot[slots[j]])
+		}
+		return slots[i] < slots[j]
+	})
+	var rows [][]string
+	for _, s := range slots {
+		keys := bySlot[s]
+		sort.Strings(keys)
+		sample := keys
+		if len(sample) > maxTagCheckKeys {
+			sample = sample[:maxTagCheckKeys]
+		}
+		examples := strings.Join(sample, " ")
+		if len(keys) > len(sample) {
+			examples += " ..."
+		}
+		rows = append(rows, []string{strconv.Itoa(s), strconv.Itoa(len(keys)), examples})
+	}
+	printTable([]string{"SLOT", "KEYS", "EXAMPLES"}, rows)
+}
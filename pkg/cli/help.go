@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func printGenericHelp() {
+	fmt.Printf(`redis-cli
+To get help about Redis commands type:
+      "help @<group>" to get a list of commands in <group>
+      "help <command>" for help on <command>
+      "quit" to exit
+
+Groups: @%s
+`, strings.Join(commandGroups, ", @"))
+}
+
+// printHelp shows the reference of a command, with its subcommands, or of
+// every command of a group.
+// Usage: HELP [command [subcommand]] | HELP @group
+func printHelp(cmds []string) {
+	args := cmds[1:]
+	if len(args) == 0 {
+		printGenericHelp()
+		return
+	}
+
+	if strings.HasPrefix(args[0], "@") {
+		group := strings.ToLower(strings.TrimPrefix(args[0], "@"))
+		found := false
+		for _, d := range commandDocs {
+			if d.Group == group {
+				printCommandDoc(d, false)
+				found = true
+			}
+		}
+		if !found {
+			fmt.Printf("(error) unknown group %s, should be one of @%s\n", args[0], strings.Join(commandGroups, ", @"))
+		}
+		return
+	}
+
+	// "help time to live" is a search, "help client info" is not
+	query := strings.Join(args, " ")
+	d, ok := findCommandDoc(query)
+	if !ok && len(args) == 1 {
+		d, ok = findCommandDoc(args[0])
+	}
+	matches := searchCommandDocs(query)
+	if !ok {
+		if len(matches) == 0 {
+			fmt.Printf("(error) no help for %s\n", query)
+			return
+		}
+		printMatches(matches)
+		return
+	}
+	printCommandDoc(d, true)
+
+	// HELP CLIENT also lists the CLIENT subcommands
+	if !strings.Contains(d.Name, " ") {
+		for _, sub := range commandDocs {
+			if strings.HasPrefix(sub.Name, d.Name+" ") {
+				printCommandDoc(sub, false)
+			}
+		}
+	}
+
+	var related []string
+	for _, m := range matches {
+		if m.Name != d.Name && !strings.HasPrefix(m.Name, d.Name+" ") {
+			related = append(related, m.Name)
+		}
+	}
+	if len(related) > 0 {
+		fmt.Printf("\n  related: %s\n", strings.Join(related, ", "))
+	}
+}
+
+// helpSynonyms widens help searches to words the summaries don't use.
+var helpSynonyms = map[string][]string{
+	"expire": {"time to live"},
+	"ttl":    {"time to live", "expir"},
+	"delete": {"remove"},
+	"remove": {"delete"},
+	"size":   {"length", "number of", "cardinality"},
+	"length": {"number of", "cardinality"},
+	"count":  {"number of", "cardinality"},
+	"copy":   {"dump", "restore", "serialized"},
+}
+
+// maxHelpMatches caps the commands a help search lists.
+const maxHelpMatches = 25
+
+// searchCommandDocs returns the commands whose name or summary match
+// query, best matches first: names containing it, names one typo away,
+// then summaries mentioning it or a synonym.
+func searchCommandDocs(query string) []commandDoc {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	terms := append([]string{helpStem(query)}, helpSynonyms[query]...)
+
+	type match struct {
+		doc   commandDoc
+		score int
+	}
+	var matches []match
+	for _, d := range commandDocs {
+		name := strings.ToLower(d.Name)
+		summary := " " + strings.ToLower(d.Summary)
+		score := 0
+		switch {
+		case strings.Contains(name, query):
+			score = 3
+		case len(query) > 3 && !strings.Contains(query, " ") && levenshtein(name, query) <= 2:
+			score = 2
+		default:
+			for _, t := range terms {
+				// match whole words or their start only
+				if strings.Contains(summary, " "+t) {
+					score = 1
+					break
+				}
+			}
+		}
+		if score > 0 {
+			matches = append(matches, match{d, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	docs := make([]commandDoc, len(matches))
+	for i, m := range matches {
+		docs[i] = m.doc
+	}
+	return docs
+}
+
+// helpStem strips common English endings so "expire" also finds
+// "expiration" and "deleted" finds "delete".
+func helpStem(word string) string {
+	for _, suffix := range []string{"ation", "ing", "ed", "es", "e", "s"} {
+		if len(word)-len(suffix) >= 4 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// printMatches lists the commands found by a help search, one per line.
+func printMatches(matches []commandDoc) {
+	more := 0
+	if len(matches) > maxHelpMatches {
+		more = len(matches) - maxHelpMatches
+		matches = matches[:maxHelpMatches]
+	}
+	width := 0
+	for _, m := range matches {
+		if len(m.Name) > width {
+			width = len(m.Name)
+		}
+	}
+	for _, m := range matches {
+		fmt.Printf("  %-*s  %s\n", width, m.Name, m.Summary)
+	}
+	if more > 0 {
+		fmt.Printf("  ... and %d more, narrow the search\n", more)
+	}
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// printCommandDoc prints d the way the official redis-cli does, with its
+// complexity and example when full is set.
+func printCommandDoc(d commandDoc, full bool) {
+	fmt.Println()
+	fmt.Printf("  %s\n", strings.TrimSpace(d.Name+" "+d.Args))
+	fmt.Printf("  summary: %s\n", d.Summary)
+	fmt.Printf("  since: %s\n", d.Since)
+	fmt.Printf("  group: %s\n", d.Group)
+	if !full {
+		return
+	}
+	if d.Complexity != "" {
+		fmt.Printf("  complexity: %s\n", d.Complexity)
+	}
+	if d.Example != "" {
+		fmt.Printf("  example: %s\n", d.Example)
+	}
+}
+
+// commandsSheet prints a cheat sheet of the syntax of every command of a
+// group, or of all groups.
+// Usage: COMMANDS [@group]
+func commandsSheet(args []string) {
+	groups := commandGroups
+	if len(args) == 1 {
+		group := strings.ToLower(strings.TrimPrefix(args[0], "@"))
+		if !validGroup(group) {
+			fmt.Printf("(error) unknown group %s, should be one of @%s\n", args[0], strings.Join(commandGroups, ", @"))
+			return
+		}
+		groups = []string{group}
+	} else if len(args) > 1 {
+		fmt.Println("(error) invalid args. Should be COMMANDS [@group]")
+		return
+	}
+
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s@%s%s\n", highlightStart, group, highlightEnd)
+		for _, d := range commandDocs {
+			// containers such as CLIENT are covered by their subcommands
+			if d.Group == group && d.Args != "subcommand [arg ...]" {
+				fmt.Printf("  %s\n", strings.TrimSpace(d.Name+" "+d.Args))
+			}
+		}
+	}
+}
+
+func validGroup(group string) bool {
+	for _, g := range commandGroups {
+		if g == group {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+)
+
+// historyLimit is the number of entries kept in the history file, the
+// same as the in-memory history of liner.
+const historyLimit = 1000
+
+func loadHistory() {
+	if f, err := os.Open(historyPath); err == nil {
+		line.ReadHistory(f)
+		f.Close()
+	}
+}
+
+// appendHistoryFile adds an entry to the history file as soon as it is
+// typed, so parallel sessions add to the file instead of overwriting
+// each other's history on exit.
+func appendHistoryFile(entry string) {
+	unlock, err := lockFile(historyPath + ".lock")
+	if err != nil {
+		fmt.Printf("Error writing history file: %s\n", err.Error())
+		return
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(historyPath, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		fmt.Printf("Error writing history file: %s\n", err.Error())
+		return
+	}
+	defer f.Close()
+	if _, err := f.WriteString(entry + "\n"); err != nil {
+		fmt.Printf("Error writing history file: %s\n", err.Error())
+	}
+}
+
+// saveHistory trims the history file to its last historyLimit entries.
+// The entries themselves were written by appendHistoryFile.
+func saveHistory() {
+	unlock, err := lockFile(historyPath + ".lock")
+	if err != nil {
+		fmt.Printf("Error writing history file: %s\n", err.Error())
+		return
+	}
+	defer unlock()
+
+	b, err := ioutil.ReadFile(historyPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			fmt.Printf("Error writing history file: %s\n", err.Error())
+		}
+		return
+	}
+	entries := strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+	if len(entries) <= historyLimit {
+		return
+	}
+	entries = entries[len(entries)-historyLimit:]
+	if err := writeFileAtomic(historyPath, []byte(strings.Join(entries, "\n")+"\n"), 0600); err != nil {
+		fmt.Printf("Error writing history file: %s\n", err.Error())
+	}
+}
+
+// lockFile takes an exclusive lock by creating name, waiting up to a
+// second for another session to release it. A lock older than 10 seconds
+// was left behind by a crashed session and is taken over.
+func lockFile(name string) (func(), error) {
+	deadline := time.Now().Add(time.Second)
+	for {
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(name) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if fi, err := os.Stat(name); err == nil && time.Since(fi.ModTime()) > 10*time.Second {
+			os.Remove(name)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another session", name)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"github.com/holys/redis-cli/pkg/info"
+)
+
+// fetchInfo runs INFO with the given sections and parses the reply.
+// Callers needing the fields of several sections pass none rather than
+// listing them: servers before 7.0 take a single section, and the default
+// ones hold server, clients, memory, persistence, stats, replication and
+// keyspace.
+func fetchInfo(sections ...string) (info.Reply, error) {
+	args := []interface{}{"INFO"}
+	for _, s := range sections {
+		args = append(args, s)
+	}
+	s, err := client.Do(args...).String()
+	if err != nil {
+		return nil, err
+	}
+	return info.Parse(s), nil
+}
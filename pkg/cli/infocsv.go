@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+// defaultInfoCSVFields are the INFO fields recorded when -info-fields is
+// not given.
+const defaultInfoCSVFields = "connected_clients,blocked_clients,used_memory,used_memory_rss,mem_fragmentation_ratio," +
+	"instantaneous_ops_per_sec,total_commands_processed,keyspace_hits,keyspace_misses,evicted_keys,expired_keys," +
+	"rejected_connections,master_link_status"
+
+// infoCSV appends a row of INFO fields to path every interval until
+// interrupted. The header is written when the file is new or empty.
+func infoCSV(path string, fields []string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval %s", interval)
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if st.Size() == 0 {
+		w.Write(append([]string{"time"}, fields...))
+	}
+
+	cliConnect()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		srv, err := fetchInfo("everything")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "(error) %s\n", err.Error())
+		} else {
+			row := []string{time.Now().Format(time.RFC3339)}
+			for _, field := range fields {
+				row = append(row, srv.Get(field))
+			}
+			w.Write(row)
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-interrupt:
+			return nil
+		}
+	}
+}
+
+// splitFields splits a comma separated list, dropping empty entries.
+func splitFields(s string) []string {
+	var fields []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
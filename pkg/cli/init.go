@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// rcLists are the rc file settings read as a list, one entry per line,
+// rather than as the default of the flag of the same name.
+var rcLists = map[string]bool{"init": true}
+
+// initCommands returns the commands -init runs after connecting, or
+// those of the init lines of the rc file when the flag isn't given.
+func initCommands() [][]string {
+	lines := rcConfig["init"]
+	if *initCmds != "" {
+		lines = []string{*initCmds}
+	}
+	var cmds [][]string
+	for _, l := range lines {
+		cmds = append(cmds, splitCommands(lexer.TrimQuotes(l))...)
+	}
+	return cmds
+}
+
+// splitCommands splits a line into the commands separated by semicolons
+// outside quotes.
+func splitCommands(l string) [][]string {
+	var cmds [][]string
+	var cmd []string
+	for _, arg := range lexer.Split(l) {
+		if arg[0] == '"' || arg[0] == '\'' {
+			cmd = append(cmd, arg)
+			continue
+		}
+		for i, part := range strings.Split(arg, ";") {
+			if i > 0 && len(cmd) > 0 {
+				cmds = append(cmds, cmd)
+				cmd = nil
+			}
+			if part != "" {
+				cmd = append(cmd, part)
+			}
+		}
+	}
+	if len(cmd) > 0 {
+		cmds = append(cmds, cmd)
+	}
+	return cmds
+}
+
+// runInit runs the -init commands, as if typed at the prompt.
+func runInit() {
+	for _, cmds := range initCommands() {
+		execCommand(cmds)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/itchyny/gojq"
+)
+
+// jqValue returns the value a jq stage filters: the decoded JSON of a
+// string reply holding JSON, such as those of JSON.GET, or the reply as
+// -format sees it, field/value replies such as HGETALL becoming objects.
+func jqValue(cmds []string, reply interface{}) interface{} {
+	s, ok := reply.(string)
+	if !ok || !json.Valid([]byte(s)) {
+		if cmds != nil {
+			reply = structureReply(cmds, reply)
+		}
+		// gojq only takes the types encoding/json decodes to
+		b, _ := json.Marshal(format.JSONValue(reply))
+		s = string(b)
+	}
+	d := json.NewDecoder(strings.NewReader(s))
+	d.UseNumber()
+	var v interface{}
+	d.Decode(&v)
+	return v
+}
+
+// runJQ filters v with a jq expression.
+func runJQ(expr string, v interface{}) ([]interface{}, error) {
+	q, err := gojq.Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("jq: %s", err.Error())
+	}
+	var outs []interface{}
+	iter := q.Run(v)
+	for {
+		o, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := o.(error); ok {
+			return nil, fmt.Errorf("jq: %s", err.Error())
+		}
+		outs = append(outs, o)
+	}
+	return outs, nil
+}
+
+// printJQ prints the outputs of a jq stage as indented JSON, strings
+// without quotes in raw mode as jq -r does.
+func printJQ(outs []interface{}) {
+	for _, o := range outs {
+		if s, ok := o.(string); ok && mode == format.Raw {
+			fmt.Println(s)
+			continue
+		}
+		b, err := json.MarshalIndent(o, "", "  ")
+		if err != nil {
+			fmt.Printf("(error) jq: %s\n", err.Error())
+			return
+		}
+		fmt.Println(string(b))
+	}
+}
+
+// jqLines turns the outputs of a jq stage into lines for the stages after
+// it: strings as they are, other values as compact JSON.
+func jqLines(outs []interface{}) []interface{} {
+	lines := make([]interface{}, len(outs))
+	for i, o := range outs {
+		if s, ok := o.(string); ok {
+			lines[i] = s
+			continue
+		}
+		b, _ := json.Marshal(o)
+		lines[i] = string(b)
+	}
+	return lines
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRunJQ(t *testing.T) {
+	hash := []interface{}{"name", "ada", "visits", "12345678901234567890"}
+	tests := []struct {
+		cmds  []string
+		reply interface{}
+		expr  string
+		want  string
+	}{
+		{[]string{"HGETALL", "h"}, hash, ".name", `["ada"]`},
+		{[]string{"HGETALL", "h"}, hash, "keys", `[["name","visits"]]`},
+		{[]string{"HGETALL", "h"}, hash, ".visits | tonumber > 0", `[true]`},
+		{[]string{"JSON.GET", "j"}, `{"n":12345678901234567890,"a":[1,2,3]}`, ".n", `[12345678901234567890]`},
+		{[]string{"JSON.GET", "j"}, `{"n":1,"a":[1,2,3]}`, "reduce .a[] as $x (0; . + $x)", `[6]`},
+		{[]string{"LRANGE", "l", "0", "-1"}, []interface{}{"a", int64(1), nil}, ".[] | select(. != null)", `["a",1]`},
+	}
+	for _, tt := range tests {
+		outs, err := runJQ(tt.expr, jqValue(tt.cmds, tt.reply))
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		var got, want interface{}
+		b, _ := json.Marshal(outs)
+		json.Unmarshal(b, &got)
+		json.Unmarshal([]byte(tt.want), &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %s, want %s", tt.expr, b, tt.want)
+		}
+	}
+
+	if _, err := runJQ(".[", nil); err == nil {
+		t.Error("a syntax error should fail")
+	}
+	if _, err := runJQ(".a", jqValue(nil, []interface{}{"x"})); err == nil {
+		t.Error("indexing an array with a field should fail")
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keysGuardThreshold is the DBSIZE above which KEYS asks for confirmation.
+const keysGuardThreshold = 10000
+
+// keysGuard intercepts KEYS on large databases. It returns true when the
+// command has been handled (replaced by SCAN or aborted) and must not be
+// sent as is.
+func keysGuard(pattern string) bool {
+	if *noKeysGuard {
+		return false
+	}
+
+	size, err := client.Do("DBSIZE").Int64()
+	if err != nil || size <= keysGuardThreshold {
+		return false
+	}
+
+	fmt.Printf("Warning: KEYS blocks the server, and this database holds %d keys.\n", size)
+	answer, err := askUser("Run an equivalent SCAN instead? (y = scan / n = abort / k = keys anyway) ")
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return true
+	}
+
+	switch strings.ToLower(answer) {
+	case "k", "keys":
+		return false
+	case "y", "yes", "s", "scan":
+		keysByScan(pattern)
+	default:
+		fmt.Println("aborted")
+	}
+	return true
+}
+
+// keysByScan collects every key matching pattern with SCAN and prints
+// them the same way a KEYS reply would be printed.
+func keysByScan(pattern string) {
+	var reply []interface{}
+	err := scanKeys(pattern, 1000, func(keys []string) error {
+		for _, k := range keys {
+			reply = append(reply, k)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	printReply(0, reply, mode)
+	fmt.Printf("\n")
+}
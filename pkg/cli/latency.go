@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+var sparkChars = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws values as a row of block characters scaled to the
+// maximum.
+func sparkline(values []int64) string {
+	var max int64
+	for _, v := range values {
+		if v > max {
+			max = v
+		}
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if max > 0 {
+			i = int(v * int64(len(sparkChars)-1) / max)
+		}
+		b.WriteRune(sparkChars[i])
+	}
+	return b.String()
+}
+
+// latencySample is one [timestamp, milliseconds] entry of LATENCY HISTORY.
+type latencySample struct {
+	at time.Time
+	ms int64
+}
+
+func parseLatencyHistory(reply interface{}) ([]latencySample, bool) {
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return nil, false
+	}
+	samples := make([]latencySample, 0, len(arr))
+	for _, v := range arr {
+		pair, ok := v.([]interface{})
+		if !ok || len(pair) != 2 {
+			return nil, false
+		}
+		ts, ok1 := pair[0].(int64)
+		ms, ok2 := pair[1].(int64)
+		if !ok1 || !ok2 {
+			return nil, false
+		}
+		samples = append(samples, latencySample{time.Unix(ts, 0), ms})
+	}
+	return samples, true
+}
+
+// printLatency renders LATENCY HISTORY as a table with a sparkline and
+// prints LATENCY GRAPH as the text it is. It returns false, printing
+// nothing, in raw mode or for other replies.
+func printLatency(cmd string, args []interface{}, reply interface{}) bool {
+	if cmd != "latency" || len(args) < 2 || mode != format.Std {
+		return false
+	}
+
+	switch strings.ToLower(fmt.Sprint(args[1])) {
+	case "history":
+		samples, ok := parseLatencyHistory(reply)
+		if !ok || len(samples) == 0 {
+			return false
+		}
+		values := make([]int64, len(samples))
+		var rows [][]string
+		for i, s := range samples {
+			values[i] = s.ms
+			rows = append(rows, []string{s.at.Format("2006-01-02 15:04:05"), strconv.FormatInt(s.ms, 10)})
+		}
+		printTable([]string{"TIME", "LATENCY (ms)"}, rows)
+		fmt.Printf("\n%s\n", sparkline(values))
+		return true
+	case "graph":
+		s, ok := reply.(string)
+		if !ok {
+			return false
+		}
+		fmt.Print(strings.TrimRight(s, "\n"))
+		return true
+	}
+	return false
+}
+
+// latencyEvents shows LATENCY LATEST with a sparkline of the history of
+// every event.
+// Usage: LATENCY-EVENTS [--watch [seconds]]
+func latencyEvents(args []string) {
+	rest, interval, err := parseWatch(args, 2*time.Second)
+	if err != nil || len(rest) != 0 {
+		fmt.Println("(error) invalid args. Should be LATENCY-EVENTS [--watch [seconds]]")
+		return
+	}
+	cliConnect()
+
+	if interval > 0 {
+		watchLoop(interval, printLatencyEvents)
+		return
+	}
+	printLatencyEvents()
+}
+
+func printLatencyEvents() {
+	r, err := client.Do("LATENCY", "LATEST").Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	events, _ := r.([]interface{})
+	if len(events) == 0 {
+		fmt.Println("no latency events, is latency-monitor-threshold set?")
+		return
+	}
+
+	var rows [][]string
+	for _, e := range events {
+		// event name, unix time of the latest spike, latest and max latency
+		fields, ok := e.([]interface{})
+		if !ok || len(fields) < 4 {
+			continue
+		}
+		name := fmt.Sprint(fields[0])
+		last := ""
+		if ts, ok := fields[1].(int64); ok {
+			last = time.Unix(ts, 0).Format("15:04:05")
+		}
+
+		spark := ""
+		if h, err := client.Do("LATENCY", "HISTORY", name).Result(); err == nil {
+			if samples, ok := parseLatencyHistory(h); ok {
+				values := make([]int64, len(samples))
+				for i, s := range samples {
+					values[i] = s.ms
+				}
+				spark = sparkline(values)
+			}
+		}
+		rows = append(rows, []string{name, last, fmt.Sprint(fields[2]), fmt.Sprint(fields[3]), spark})
+	}
+	printTable([]string{"EVENT", "LAST", "LATEST (ms)", "MAX (ms)", "HISTORY"}, rows)
+}
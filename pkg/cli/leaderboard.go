@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// leaderboard prints the top members of a sorted set, highest score
+// first. When watching, rank changes since the previous refresh are
+// highlighted.
+// Usage: LEADERBOARD key [--top N] [--watch [seconds]]
+func leaderboard(args []string) {
+	const usage = "(error) invalid args. Should be LEADERBOARD key [--top N] [--watch [seconds]]"
+	rest, interval, err := parseWatch(args, 2*time.Second)
+	if err != nil {
+		fmt.Println(usage)
+		return
+	}
+
+	var key string
+	top := int64(10)
+	for i := 0; i < len(rest); i++ {
+		if strings.ToLower(rest[i]) == "--top" && i+1 < len(rest) {
+			top, err = strconv.ParseInt(rest[i+1], 10, 64)
+			if err != nil || top <= 0 {
+				fmt.Println(usage)
+				return
+			}
+			i++
+		} else if key == "" {
+			key = lexer.TrimQuotes(rest[i])
+		} else {
+			fmt.Println(usage)
+			return
+		}
+	}
+	if key == "" {
+		fmt.Println(usage)
+		return
+	}
+	cliConnect()
+
+	var previous map[string]int
+	show := func() {
+		current, err := printLeaderboard(key, top, previous)
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		previous = current
+	}
+	if interval > 0 {
+		watchLoop(interval, show)
+		return
+	}
+	show()
+}
+
+// printLeaderboard prints the table and returns the rank of every member
+// shown. Members whose rank differs from previous get a highlighted
+// marker.
+func printLeaderboard(key string, top int64, previous map[string]int) (map[string]int, error) {
+	members, err := client.ZRevRangeWithScores(key, 0, top-1).Result()
+	if err != nil {
+		return nil, err
+	}
+	total, err := client.ZCard(key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		fmt.Println("(empty sorted set)")
+		return map[string]int{}, nil
+	}
+
+	ranks := map[string]int{}
+	var rows [][]string
+	for i, z := range members {
+		member := fmt.Sprint(z.Member)
+		rank := i + 1
+		ranks[member] = rank
+
+		change := ""
+		if previous != nil {
+			if old, ok := previous[member]; !ok {
+				change = highlightStart + "new" + highlightEnd
+			} else if old > rank {
+				change = fmt.Sprintf("%s▲%d%s", highlightStart, old-rank, highlightEnd)
+			} else if old < rank {
+				change = fmt.Sprintf("%s▼%d%s", highlightStart, rank-old, highlightEnd)
+			}
+		}
+		rows = append(rows, []string{strconv.Itoa(rank), member, strconv.FormatFloat(z.Score, 'f', -1, 64), change})
+	}
+	printTable([]string{"RANK", "MEMBER", "SCORE", ""}, rows)
+	fmt.Printf("\n%d of %d members\n", len(members), total)
+	return ranks, nil
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// macroDir holds recorded macros, one command per line. $1, $2 ... in a
+// macro are replaced by the arguments given to MACRO PLAY, $@ by all of
+// them.
+var macroDir = path.Join(os.Getenv("HOME"), ".gorediscli_macros") // $HOME/.gorediscli_macros
+
+// maxMacroDepth stops macros that play themselves.
+const maxMacroDepth = 8
+
+var (
+	recording  string
+	recorded   []string
+	macroDepth int
+)
+
+// macroCommand manages macros.
+// Usage: MACRO RECORD name | MACRO STOP | MACRO PLAY name [arg ...] | MACRO LIST | MACRO SHOW name
+func macroCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Println("(error) invalid args. Should be MACRO RECORD name | STOP | PLAY name [arg ...] | LIST | SHOW name")
+		return
+	}
+
+	sub := strings.ToLower(args[0])
+	switch {
+	case sub == "record" && len(args) == 2:
+		if recording != "" {
+			fmt.Printf("(error) already recording %s\n", recording)
+			return
+		}
+		name := lexer.TrimQuotes(args[1])
+		if !validMacroName(name) {
+			fmt.Printf("(error) invalid macro name %q\n", name)
+			return
+		}
+		recording, recorded = name, nil
+		fmt.Printf("Recording %s, run MACRO STOP to save it\n", name)
+	case sub == "stop" && len(args) == 1:
+		if recording == "" {
+			fmt.Println("(error) not recording")
+			return
+		}
+		if err := saveMacro(recording, recorded); err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		fmt.Printf("Saved %s (%d commands)\n", recording, len(recorded))
+		recording, recorded = "", nil
+	case sub == "play" && len(args) >= 2:
+		playMacro(lexer.TrimQuotes(args[1]), args[2:])
+	case sub == "list" && len(args) == 1:
+		listMacros()
+	case sub == "show" && len(args) == 2:
+		lines, err := loadMacro(lexer.TrimQuotes(args[1]))
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		for i, l := range lines {
+			fmt.Printf("%d) %s\n", i+1, l)
+		}
+	default:
+		fmt.Println("(error) invalid args. Should be MACRO RECORD name | STOP | PLAY name [arg ...] | LIST | SHOW name")
+	}
+}
+
+// recordMacro adds a command line to the macro being recorded, if any.
+// Macros are saved in clear text, so the lines holding a password are
+// left out, as the history would mask them.
+func recordMacro(cmds []string) {
+	if recording == "" || strings.ToLower(cmds[0]) == "macro" {
+		return
+	}
+	l := strings.Join(cmds, " ")
+	if strings.Join(maskSecrets(cmds), " ") != l {
+		fmt.Printf("%s not recorded in %s, macros don't keep passwords\n", strings.ToUpper(cmds[0]), recording)
+		return
+	}
+	recorded = append(recorded, l)
+}
+
+// playMacro runs every command of a macro, substituting its arguments.
+func playMacro(name string, args []string) {
+	if macroDepth >= maxMacroDepth {
+		fmt.Printf("(error) macros nested more than %d deep\n", maxMacroDepth)
+		return
+	}
+	lines, err := loadMacro(name)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	macroDepth++
+	defer func() { macroDepth-- }()
+	for _, l := range lines {
+		l = expandMacroArgs(l, args)
+		cmds := lexer.Split(l)
+		if len(cmds) == 0 {
+			continue
+		}
+		fmt.Printf("%s> %s\n", name, l)
+		execCommand(cmds)
+	}
+}
+
+// expandMacroArgs replaces $1..$n and $@ in l. Placeholders without an
+// argument are left untouched.
+func expandMacroArgs(l string, args []string) string {
+	l = strings.Replace(l, "$@", strings.Join(args, " "), -1)
+	// replace from the highest index so $1 doesn't eat the start of $10
+	for i := len(args); i >= 1; i-- {
+		l = strings.Replace(l, "$"+strconv.Itoa(i), args[i-1], -1)
+	}
+	return l
+}
+
+func validMacroName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, `/\`) && name[0] != '.'
+}
+
+func saveMacro(name string, lines []string) error {
+	if err := os.MkdirAll(macroDir, 0700); err != nil {
+		return err
+	}
+	data := strings.Join(lines, "\n") + "\n"
+	return writeFileAtomic(path.Join(macroDir, name), []byte(data), 0600)
+}
+
+func loadMacro(name string) ([]string, error) {
+	if !validMacroName(name) {
+		return nil, fmt.Errorf("invalid macro name %q", name)
+	}
+	data, err := ioutil.ReadFile(path.Join(macroDir, name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no macro named %s", name)
+	} else if err != nil {
+		return nil, err
+	}
+
+	var lines []string
+	for _, l := range strings.Split(string(data), "\n") {
+		if l = strings.TrimSpace(l); l != "" && !strings.HasPrefix(l, "#") {
+			lines = append(lines, l)
+		}
+	}
+	return lines, nil
+}
+
+func listMacros() {
+	files, err := ioutil.ReadDir(macroDir)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	var names []string
+	for _, f := range files {
+		if !f.IsDir() && validMacroName(f.Name()) {
+			names = append(names, f.Name())
+		}
+	}
+	if len(names) == 0 {
+		fmt.Println("(empty list or set)")
+		return
+	}
+	sort.Strings(names)
+	for i, n := range names {
+		fmt.Printf("%d) %s\n", i+1, n)
+	}
+}
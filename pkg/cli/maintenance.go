@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/conn"
+)
+
+// maintenance pauses the clients of the connected instance with CLIENT
+// PAUSE and counts down until the pause ends. Ctrl-C unpauses right away,
+// so that a pause is never left behind by accident.
+// Usage: MAINTENANCE ON [ms] [WRITE|ALL] / MAINTENANCE OFF
+func maintenance(args []string) {
+	const usage = "(error) invalid args. Should be MAINTENANCE ON [ms] [WRITE|ALL] or MAINTENANCE OFF"
+	if len(args) == 0 {
+		fmt.Println(usage)
+		return
+	}
+
+	instance := addr()
+	c := conn.NewSingle(connOptions(instance, *auth))
+	defer c.Close()
+
+	switch strings.ToLower(args[0]) {
+	case "off":
+		if len(args) != 1 {
+			fmt.Println(usage)
+			return
+		}
+		if err := c.Do("CLIENT", "UNPAUSE").Err(); err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		fmt.Printf("%s unpaused\n", instance)
+		return
+	case "on":
+	default:
+		fmt.Println(usage)
+		return
+	}
+
+	ms := int64(10000)
+	pauseMode := ""
+	for _, a := range args[1:] {
+		switch strings.ToLower(a) {
+		case "write", "all":
+			pauseMode = strings.ToUpper(a)
+		default:
+			n, err := strconv.ParseInt(a, 10, 64)
+			if err != nil || n <= 0 {
+				fmt.Println(usage)
+				return
+			}
+			ms = n
+		}
+	}
+
+	pause := []interface{}{"CLIENT", "PAUSE", ms}
+	if pauseMode != "" {
+		pause = append(pause, pauseMode)
+	} else {
+		pauseMode = "ALL"
+	}
+	if err := c.Do(pause...).Err(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	end := time.Now().Add(time.Duration(ms) * time.Millisecond)
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		left := time.Until(end)
+		if left <= 0 {
+			fmt.Printf("\r\033[K%s unpaused\n", instance)
+			return
+		}
+		fmt.Printf("\r\033[K%s paused (%s), %s left, Ctrl-C to unpause", instance, pauseMode, left.Round(time.Second))
+
+		select {
+		case <-ticker.C:
+		case <-interrupt:
+			fmt.Print("\r\033[K")
+			if err := c.Do("CLIENT", "UNPAUSE").Err(); err != nil {
+				fmt.Printf("(error) %s, the pause ends at %s\n", err.Error(), end.Format("15:04:05"))
+				return
+			}
+			fmt.Printf("%s unpaused\n", instance)
+			return
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+type keyMemory struct {
+	key   string
+	bytes int64
+}
+
+// memoryByPattern sums MEMORY USAGE over the keys matching pattern,
+// reporting the total, the average and the biggest keys. --samples is
+// passed to MEMORY USAGE, 0 meaning every element of nested values.
+// Usage: MEMORY-BY-PATTERN pattern [--samples n] [--top n] [--replica]
+func memoryByPattern(args []string) {
+	const usage = "(error) invalid args. Should be MEMORY-BY-PATTERN pattern [--samples n] [--top n] [--replica]"
+	var pattern string
+	samples, top := -1, 10
+	onReplica := false
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		if (opt == "--samples" || opt == "--top") && i+1 < len(args) {
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				fmt.Println(usage)
+				return
+			}
+			if opt == "--samples" {
+				samples = n
+			} else {
+				top = n
+			}
+			i++
+		} else if opt == "--replica" {
+			onReplica = true
+		} else if pattern == "" {
+			pattern = lexer.TrimQuotes(args[i])
+		} else {
+			fmt.Println(usage)
+			return
+		}
+	}
+	if pattern == "" {
+		fmt.Println(usage)
+		return
+	}
+	cliConnect()
+
+	var total, count int64
+	var biggest []keyMemory
+	var c redis.Cmdable = client
+	sum := func(keys []string) error {
+		if len(keys) == 0 {
+			return nil
+		}
+		pipe := c.Pipeline()
+		usages := make([]*redis.IntCmd, len(keys))
+		for i, k := range keys {
+			if samples >= 0 {
+				usages[i] = pipe.MemoryUsage(k, samples)
+			} else {
+				usages[i] = pipe.MemoryUsage(k)
+			}
+		}
+		pipe.Exec()
+		pipe.Close()
+
+		for i, k := range keys {
+			n, err := usages[i].Result()
+			if err == redis.Nil {
+				continue
+			} else if err != nil {
+				return err
+			}
+			total += n
+			count++
+			biggest = addBiggest(biggest, keyMemory{k, n}, top)
+		}
+		fmt.Printf("\r%d keys, %s", count, humanBytes(total))
+		return nil
+	}
+
+	var err error
+	if onReplica {
+		var replica string
+		if replica, err = replicaAddr(); err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		rc := conn.NewSingle(clientOptions(replica, *auth))
+		defer rc.Close()
+		c = rc
+		fmt.Printf("reading from replica %s\n", replica)
+		err = scanNodeKeys(rc, pattern, 1000, sum)
+	} else {
+		// in cluster mode every master holds its own keys
+		err = scanKeys(pattern, 1000, sum)
+	}
+	if err != nil {
+		fmt.Printf("\n(error) %s\n", err.Error())
+		return
+	}
+	fmt.Print("\r\033[K")
+
+	if count == 0 {
+		fmt.Println("(no keys)")
+		return
+	}
+	fmt.Printf("keys:    %d\n", count)
+	fmt.Printf("total:   %s (%d bytes)\n", humanBytes(total), total)
+	fmt.Printf("average: %s\n", humanBytes(total/count))
+	if len(biggest) == 0 {
+		return
+	}
+
+	fmt.Println()
+	var rows [][]string
+	for _, k := range biggest {
+		rows = append(rows, []string{k.key, humanBytes(k.bytes), fmt.Sprintf("%.1f%%", float64(k.bytes)*100/float64(total))})
+	}
+	printTable([]string{"KEY", "MEMORY", "SHARE"}, rows)
+}
+
+// addBiggest inserts k in list, kept sorted by decreasing size and at most
+// n long.
+func addBiggest(list []keyMemory, k keyMemory, n int) []keyMemory {
+	if n == 0 || (len(list) == n && list[n-1].bytes >= k.bytes) {
+		return list
+	}
+	i := sort.Search(len(list), func(i int) bool { return list[i].bytes < k.bytes })
+	list = append(list, keyMemory{})
+	copy(list[i+1:], list[i:])
+	list[i] = k
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
+
+// humanBytes formats a byte count the way INFO does, e.g. 1.50M.
+func humanBytes(n int64) string {
+	const units = "KMGTP"
+	if n < 1024 {
+		return fmt.Sprintf("%dB", n)
+	}
+	f := float64(n)
+	i := -1
+	for f >= 1024 && i < len(units)-1 {
+		f /= 1024
+		i++
+	}
+	return fmt.Sprintf("%.2f%c", f, units[i])
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// printInputs prints the replies of MGET and HMGET next to the key or
+// field they belong to, so that nils can't be misattributed:
+//
+//	key1 => "v1"
+//	key2 => (nil)
+//
+// It returns false, printing nothing, in other modes or for other
+// commands.
+func printInputs(cmd string, args []interface{}, reply interface{}) bool {
+	arr, ok := reply.([]interface{})
+	if !ok || mode != format.Std || replyTmpl != nil {
+		return false
+	}
+
+	var inputs []interface{}
+	switch cmd {
+	case "mget":
+		inputs = args[1:]
+	case "hmget":
+		if len(args) < 2 {
+			return false
+		}
+		inputs = args[2:]
+	default:
+		return false
+	}
+	if len(inputs) != len(arr) {
+		return false
+	}
+
+	width := 0
+	for _, in := range inputs {
+		if n := len(fmt.Sprint(in)); n > width {
+			width = n
+		}
+	}
+	lines := make([]string, len(arr))
+	for i, v := range arr {
+		value := format.NilStd
+		if v != nil {
+			value = strconv.Quote(fmt.Sprint(v))
+		}
+		lines[i] = fmt.Sprintf("%-*s => %s", width, fmt.Sprint(inputs[i]), value)
+	}
+	fmt.Print(strings.Join(lines, "\n"))
+	return true
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"github.com/alicebob/miniredis/v2"
+)
+
+// startMock starts an in-process miniredis and points the connection
+// flags at it.
+func startMock() (*miniredis.Miniredis, error) {
+	m, err := miniredis.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	h, p := m.Host(), m.Port()
+	hostname = &h
+	port = &p
+	*socket = ""
+	*auth = ""
+	*user = ""
+	*useTLS = false
+	return m, nil
+}
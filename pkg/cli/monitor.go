@@ -0,0 +1,411 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// monitorEntry is a parsed line of MONITOR output:
+// 1339518083.107412 [0 127.0.0.1:60866] "keys" "*"
+type monitorEntry struct {
+	Time   time.Time `json:"time"`
+	DB     int       `json:"db"`
+	Client string    `json:"client"`
+	Args   []string  `json:"args"`
+}
+
+// parseMonitorLine parses a MONITOR line, without its leading +.
+func parseMonitorLine(l string) (monitorEntry, error) {
+	var e monitorEntry
+	open, closing := strings.Index(l, " ["), strings.Index(l, "] ")
+	if open < 0 || closing < open {
+		return e, fmt.Errorf("unexpected MONITOR line %q", l)
+	}
+
+	secs, err := strconv.ParseFloat(l[:open], 64)
+	if err != nil {
+		return e, fmt.Errorf("unexpected MONITOR line %q", l)
+	}
+	e.Time = time.Unix(0, int64(secs*float64(time.Second)))
+
+	origin := strings.SplitN(l[open+2:closing], " ", 2)
+	e.DB, _ = strconv.Atoi(origin[0])
+	if len(origin) == 2 {
+		e.Client = origin[1]
+	}
+
+	rest := l[closing+2:]
+	for len(rest) > 0 {
+		rest = strings.TrimLeft(rest, " ")
+		if rest == "" || rest[0] != '"' {
+			break
+		}
+		// find the closing quote, skipping escaped characters
+		end := 1
+		for end < len(rest) && rest[end] != '"' {
+			if rest[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(rest) {
+			return e, fmt.Errorf("unterminated argument in MONITOR line %q", l)
+		}
+		arg, err := strconv.Unquote(rest[:end+1])
+		if err != nil {
+			arg = rest[1:end]
+		}
+		e.Args = append(e.Args, arg)
+		rest = rest[end+1:]
+	}
+	return e, nil
+}
+
+// monitorFilter selects the MONITOR lines to print and count.
+type monitorFilter struct {
+	commands map[string]bool
+	key      string
+	client   string
+	// sample keeps one line in every sample.
+	sample int
+}
+
+// parseMonitorArgs parses the options of MONITOR.
+func parseMonitorArgs(args []string) (monitorFilter, int, error) {
+	f := monitorFilter{sample: 1}
+	top := 10
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		if i+1 >= len(args) {
+			return f, top, fmt.Errorf("unexpected %s", args[i])
+		}
+		val := lexer.TrimQuotes(args[i+1])
+		i++
+		switch opt {
+		case "--cmd":
+			f.commands = map[string]bool{}
+			for _, c := range strings.Split(val, ",") {
+				f.commands[strings.ToLower(strings.TrimSpace(c))] = true
+			}
+		case "--key":
+			f.key = val
+		case "--client":
+			f.client = val
+		case "--sample":
+			n, err := strconv.Atoi(strings.TrimPrefix(val, "1/"))
+			if err != nil || n < 1 {
+				return f, top, fmt.Errorf("invalid sample %s, should be 1/n", val)
+			}
+			f.sample = n
+		case "--top":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return f, top, fmt.Errorf("invalid top %s", val)
+			}
+			top = n
+		default:
+			return f, top, fmt.Errorf("unexpected %s", args[i-1])
+		}
+	}
+	return f, top, nil
+}
+
+// match reports whether e passes the filter.
+func (f monitorFilter) match(e monitorEntry) bool {
+	if len(e.Args) == 0 {
+		return f.commands == nil && f.key == "" && f.client == ""
+	}
+	if f.commands != nil && !f.commands[strings.ToLower(e.Args[0])] {
+		return false
+	}
+	if f.client != "" {
+		host := e.Client
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		if !matchGlob(f.client, e.Client) && host != f.client {
+			return false
+		}
+	}
+	if f.key != "" {
+		for _, k := range entryKeys(e) {
+			if matchGlob(f.key, k) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// entryKeys returns the keys of a MONITOR entry, the first argument when
+// the command's key positions are unknown.
+func entryKeys(e monitorEntry) []string {
+	if len(e.Args) < 2 {
+		return nil
+	}
+	if keys := commandKeys(e.Args); keys != nil {
+		return keys
+	}
+	if commandInfo(e.Args[0]) != nil {
+		// a command without keys, such as PING or INFO
+		return nil
+	}
+	return e.Args[1:2]
+}
+
+// monitorMaxKeys bounds the distinct keys counted by monitorStats, so a
+// long run on a busy server doesn't grow without end.
+const monitorMaxKeys = 100000
+
+// monitorStats counts the commands and keys seen by MONITOR.
+type monitorStats struct {
+	start     time.Time
+	total     int64
+	commands  map[string]int64
+	keys      map[string]int64
+	truncated bool
+}
+
+func newMonitorStats() *monitorStats {
+	return &monitorStats{start: time.Now(), commands: map[string]int64{}, keys: map[string]int64{}}
+}
+
+func (s *monitorStats) add(e monitorEntry) {
+	if len(e.Args) == 0 {
+		return
+	}
+	s.total++
+	s.commands[strings.ToUpper(e.Args[0])]++
+	for _, k := range entryKeys(e) {
+		if _, ok := s.keys[k]; !ok && len(s.keys) >= monitorMaxKeys {
+			s.truncated = true
+			continue
+		}
+		s.keys[k]++
+	}
+}
+
+// print prints the top commands and keys, the counts scaled by sample.
+func (s *monitorStats) print(out io.Writer, top, sample int) {
+	elapsed := time.Since(s.start)
+	if s.total == 0 {
+		fmt.Fprintln(out, "no commands seen")
+		return
+	}
+	fmt.Fprintf(out, "%d commands in %s", s.total, elapsed.Round(time.Second))
+	if sample > 1 {
+		fmt.Fprintf(out, ", sampled 1/%d, counts are estimates", sample)
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out)
+	fprintTable(out, []string{"COMMAND", "COUNT", "SHARE", "PER SEC"}, topCounts(s.commands, s.total, top, sample, elapsed))
+	if len(s.keys) > 0 {
+		fmt.Fprintln(out)
+		fprintTable(out, []string{"KEY", "COUNT", "SHARE", "PER SEC"}, topCounts(s.keys, s.total, top, sample, elapsed))
+		if s.truncated {
+			fmt.Fprintf(out, "(only the first %d distinct keys were counted)\n", monitorMaxKeys)
+		}
+	}
+}
+
+// topCounts returns the rows of the n biggest counts.
+func topCounts(counts map[string]int64, total int64, n, sample int, elapsed time.Duration) [][]string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	rows := make([][]string, len(names))
+	for i, name := range names {
+		c := counts[name] * int64(sample)
+		rows[i] = []string{name, strconv.FormatInt(c, 10), percent(counts[name], total),
+			fmt.Sprintf("%.1f", float64(c)/elapsed.Seconds())}
+	}
+	return rows
+}
+
+// monitor streams the commands processed by the server until Ctrl-C,
+// then prints the top commands and keys. --cmd, --key and --client keep
+// the commands of the given names, touching keys matching a glob pattern,
+// or sent from a client address (host or host:port glob). --sample 1/n
+// keeps one line in n, to follow a busy server.
+// Usage: MONITOR [--cmd name[,name ...]] [--key pattern] [--client addr] [--sample 1/n] [--top n]
+func monitor(args []string) {
+	filter, top, err := parseMonitorArgs(args)
+	if err != nil {
+		fmt.Printf("(error) %s. Should be MONITOR [--cmd name[,name ...]] [--key pattern] [--client addr] [--sample 1/n] [--top n]\n", err.Error())
+		return
+	}
+	if err := checkAllowed([]string{"monitor"}); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	// the key positions come from COMMAND
+	cliConnect()
+	node := addr()
+	lines, closeMonitor, err := startMonitor(node)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	defer closeMonitor()
+	if !*jsonl {
+		fmt.Println("monitoring, press Ctrl-C to stop")
+	}
+
+	interrupt, stop := interrupted()
+	defer stop()
+	stats := newMonitorStats()
+	defer stats.print(os.Stderr, top, filter.sample)
+
+	var seen int
+	for {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				return
+			}
+			seen++
+			if seen%filter.sample != 0 {
+				continue
+			}
+			e, err := parseMonitorLine(l)
+			if err != nil {
+				if filter.match(monitorEntry{}) {
+					emitEvent(streamEvent{Time: time.Now(), Node: node, Source: "monitor", Payload: l}, l)
+				}
+				continue
+			}
+			if !filter.match(e) {
+				continue
+			}
+			stats.add(e)
+			emitEvent(streamEvent{Time: e.Time, Node: node, Source: "monitor", Payload: e}, l)
+		case <-interrupt:
+			return
+		}
+	}
+}
+
+// startMonitor sends MONITOR to node on a connection of its own, and
+// returns the lines that follow, without their leading +, and the
+// function that closes the connection and stops the reader.
+func startMonitor(node string) (<-chan string, func(), error) {
+	c, err := dialRaw(node)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bufio.NewReader(c)
+	if err := rawHandshake(c, r); err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+
+	writeRESP(c, []string{"MONITOR"})
+	reply, err := r.ReadString('\n')
+	if err != nil {
+		c.Close()
+		return nil, nil, err
+	}
+	if strings.HasPrefix(reply, "-") {
+		c.Close()
+		return nil, nil, fmt.Errorf("%s", strings.TrimSpace(reply[1:]))
+	}
+
+	lines := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		defer close(lines)
+		for {
+			l, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			select {
+			case lines <- strings.TrimPrefix(strings.TrimRight(l, "\r\n"), "+"):
+			case <-done:
+				return
+			}
+		}
+	}()
+	return lines, func() {
+		close(done)
+		c.Close()
+	}, nil
+}
+
+// topKeys runs MONITOR for a while and prints the hottest keys and
+// commands of that time, rather than relying on OBJECT FREQ, which needs
+// an LFU maxmemory-policy and a scan of the whole keyspace.
+func topKeys(seconds int) error {
+	cliConnect()
+	if err := checkAllowed([]string{"monitor"}); err != nil {
+		return err
+	}
+	lines, closeMonitor, err := startMonitor(addr())
+	if err != nil {
+		return err
+	}
+	defer closeMonitor()
+
+	interrupt, stop := interrupted()
+	defer stop()
+	fmt.Fprintf(os.Stderr, "monitoring %s for %ds, press Ctrl-C to stop early\n", addr(), seconds)
+	stats := newMonitorStats()
+	deadline := time.After(time.Duration(seconds) * time.Second)
+loop:
+	for {
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				break loop
+			}
+			if e, err := parseMonitorLine(l); err == nil {
+				stats.add(e)
+			}
+		case <-deadline:
+			break loop
+		case <-interrupt:
+			break loop
+		}
+	}
+
+	elapsed := time.Since(stats.start)
+	if stats.total == 0 {
+		fmt.Println("no commands seen")
+		return nil
+	}
+	fmt.Printf("%shottest keys%s, %d commands in %s\n", highlightStart, highlightEnd, stats.total, elapsed.Round(time.Second))
+	rows := topCounts(stats.keys, stats.total, 20, 1, elapsed)
+	for i := range rows {
+		rows[i] = append([]string{strconv.Itoa(i + 1)}, rows[i]...)
+	}
+	if len(rows) == 0 {
+		fmt.Println("no keys accessed")
+	} else {
+		printTable([]string{"RANK", "KEY", "COUNT", "SHARE", "PER SEC"}, rows)
+	}
+	if stats.truncated {
+		fmt.Printf("(only the first %d distinct keys were counted)\n", monitorMaxKeys)
+	}
+	fmt.Printf("\n%stop commands%s\n", highlightStart, highlightEnd)
+	printTable([]string{"COMMAND", "COUNT", "SHARE", "PER SEC"}, topCounts(stats.commands, stats.total, 10, 1, elapsed))
+	return nil
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+)
+
+const (
+	migrateBatch   = 100
+	migrateTimeout = 60000 // milliseconds
+	migrateRetries = 5
+)
+
+// moveSlots moves slots between two masters with CLUSTER SETSLOT and
+// MIGRATE. Slots already migrating from the source to the target are
+// finished first, so an interrupted run can simply be started again.
+// Usage: CLUSTER MOVE-SLOTS --from node --to node --slots count
+func moveSlots(args []string) {
+	var fromArg, toArg string
+	count := 0
+	for i := 0; i < len(args); i++ {
+		if i+1 >= len(args) {
+			fmt.Println("(error) invalid args. Should be CLUSTER MOVE-SLOTS --from node --to node --slots count")
+			return
+		}
+		switch strings.ToLower(args[i]) {
+		case "--from":
+			fromArg = args[i+1]
+		case "--to":
+			toArg = args[i+1]
+		case "--slots":
+			count, _ = strconv.Atoi(args[i+1])
+		default:
+			fmt.Println("(error) invalid args. Should be CLUSTER MOVE-SLOTS --from node --to node --slots count")
+			return
+		}
+		i++
+	}
+	if fromArg == "" || toArg == "" || count <= 0 {
+		fmt.Println("(error) invalid args. Should be CLUSTER MOVE-SLOTS --from node --to node --slots count")
+		return
+	}
+	cliConnect()
+
+	nodes, err := fetchClusterNodes()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	from, to := findNode(nodes, fromArg), findNode(nodes, toArg)
+	if from == nil || to == nil || !from.HasFlag("master") || !to.HasFlag("master") {
+		fmt.Println("(error) --from and --to must be the address or ID of master nodes")
+		return
+	}
+	if from.ID == to.ID {
+		fmt.Println("(error) --from and --to are the same node")
+		return
+	}
+
+	slots := slotsToMove(from, to, count)
+	if len(slots) == 0 {
+		fmt.Printf("(error) %s has no slots to move\n", from.Addr)
+		return
+	}
+	if !confirm(fmt.Sprintf("Move %d slots from %s to %s?", len(slots), from.Addr, to.Addr)) {
+		fmt.Println("aborted")
+		return
+	}
+
+	source := conn.NewSingle(connOptions(from.Addr, *auth))
+	defer source.Close()
+	target := conn.NewSingle(connOptions(to.Addr, *auth))
+	defer target.Close()
+
+	moved := 0
+	for i, slot := range slots {
+		keys, err := moveSlot(source, target, from, to, slot, func(keys int) {
+			fmt.Printf("\rmoving slot %d (%d/%d), %d keys", slot, i+1, len(slots), keys)
+		})
+		if err != nil {
+			fmt.Printf("\n(error) slot %d: %s\n", slot, err.Error())
+			fmt.Println("run the same command again to resume")
+			return
+		}
+		moved += keys
+
+		// let every master know about the new owner
+		for _, n := range nodes {
+			if n.HasFlag("master") && n.ID != from.ID && n.ID != to.ID {
+				c := conn.NewSingle(connOptions(n.Addr, *auth))
+				c.Do("CLUSTER", "SETSLOT", slot, "NODE", to.ID)
+				c.Close()
+			}
+		}
+	}
+	fmt.Printf("\nmoved %d slots and %d keys from %s to %s\n", len(slots), moved, from.Addr, to.Addr)
+}
+
+// findNode finds a node by address or ID (or unique ID prefix).
+func findNode(nodes []*clusterNode, name string) *clusterNode {
+	var found *clusterNode
+	for _, n := range nodes {
+		if n.Addr == name || n.ID == name {
+			return n
+		}
+		if len(name) >= 8 && strings.HasPrefix(n.ID, name) {
+			if found != nil {
+				return nil
+			}
+			found = n
+		}
+	}
+	return found
+}
+
+// slotsToMove returns up to count slots: the ones already migrating from
+// the source to the target first, then the lowest ones the source owns.
+func slotsToMove(from, to *clusterNode, count int) []int {
+	var slots []int
+	seen := map[int]bool{}
+	for slot, peer := range from.Migrating {
+		if peer == to.ID {
+			slots = append(slots, slot)
+			seen[slot] = true
+		}
+	}
+	sort.Ints(slots)
+
+	for _, r := range from.Slots {
+		for s := r[0]; s <= r[1] && len(slots) < count; s++ {
+			if !seen[s] {
+				slots = append(slots, s)
+			}
+		}
+	}
+	if len(slots) > count {
+		slots = slots[:count]
+	}
+	return slots
+}
+
+// moveSlot migrates the keys of one slot and assigns it to the target.
+func moveSlot(source, target *redis.Client, from, to *clusterNode, slot int, progress func(int)) (int, error) {
+	if _, ok := to.Importing[slot]; !ok {
+		if err := target.Do("CLUSTER", "SETSLOT", slot, "IMPORTING", from.ID).Err(); err != nil {
+			return 0, err
+		}
+	}
+	if _, ok := from.Migrating[slot]; !ok {
+		if err := source.Do("CLUSTER", "SETSLOT", slot, "MIGRATING", to.ID).Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	host, port, err := net.SplitHostPort(to.Addr)
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	progress(moved)
+	for {
+		keys, err := source.Do("CLUSTER", "GETKEYSINSLOT", slot, migrateBatch).Result()
+		if err != nil {
+			return moved, err
+		}
+		batch := replyStrings(keys)
+		if len(batch) == 0 {
+			break
+		}
+
+		args := []interface{}{"MIGRATE", host, port, "", 0, migrateTimeout}
+		if *user != "" {
+			args = append(args, "AUTH2", *user, *auth)
+		} else if *auth != "" {
+			args = append(args, "AUTH", *auth)
+		}
+		args = append(args, "KEYS")
+		for _, k := range batch {
+			args = append(args, k)
+		}
+		if err := retryMigrate(source, args); err != nil {
+			return moved, err
+		}
+		moved += len(batch)
+		progress(moved)
+	}
+
+	if err := target.Do("CLUSTER", "SETSLOT", slot, "NODE", to.ID).Err(); err != nil {
+		return moved, err
+	}
+	if err := source.Do("CLUSTER", "SETSLOT", slot, "NODE", to.ID).Err(); err != nil {
+		return moved, err
+	}
+	return moved, nil
+}
+
+// retryMigrate runs MIGRATE, retrying with backoff on the transient
+// errors a cluster returns while slots move (ASK, TRYAGAIN, IOERR).
+func retryMigrate(c *redis.Client, args []interface{}) error {
+	backoff := 100 * time.Millisecond
+	var err error
+	for attempt := 0; attempt < migrateRetries; attempt++ {
+		err = c.Do(args...).Err()
+		if err == nil || err == redis.Nil {
+			return nil
+		}
+		msg := err.Error()
+		if !strings.HasPrefix(msg, "ASK") && !strings.HasPrefix(msg, "TRYAGAIN") && !strings.HasPrefix(msg, "IOERR") {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+)
+
+// targetNode is the node the command being run goes to, set by an
+// @host:port prefix, whatever the cluster routing says.
+var targetNode string
+
+// targetReplica is set by the @replica prefix: the command being run goes
+// to a replica of the shard of its key.
+var targetReplica bool
+
+// commandNode returns the node a command is forced to, "" to route it as
+// usual: that of an @host:port prefix or -node, or with @replica and
+// -prefer-replica a replica of the shard of its first key, in which case
+// replica is true. -prefer-replica only moves read-only commands, and
+// leaves them on the master when the shard has no replica.
+func commandNode(cmds []string) (addr string, replica bool, err error) {
+	if targetNode != "" {
+		return targetNode, false, nil
+	}
+	if targetReplica || (*readReplica && isReadOnly(cmds[0])) {
+		key := ""
+		if keys := commandKeys(cmds); len(keys) > 0 {
+			key = keys[0]
+		}
+		addr, err := keyReplica(key)
+		if err == nil {
+			return addr, true, nil
+		}
+		if targetReplica {
+			return "", false, err
+		}
+	}
+	return *nodeFlag, false, nil
+}
+
+// isReadOnly reports whether COMMAND flags name as readonly.
+func isReadOnly(name string) bool {
+	info := commandInfo(name)
+	return info != nil && hasFlag(info, "readonly")
+}
+
+// keyReplica returns the address of a replica serving key: in cluster
+// mode a connected replica of the master of its slot, otherwise one of
+// the current server.
+func keyReplica(key string) (string, error) {
+	if !clusterEnabled {
+		return replicaAddr()
+	}
+	if key == "" {
+		return "", fmt.Errorf("@replica needs a command with a key in cluster mode, to pick the shard")
+	}
+	nodes, err := fetchClusterNodes()
+	if err != nil {
+		return "", err
+	}
+	slot := keySlot(key)
+	owner := slotOwner(nodes, slot)
+	if owner == nil {
+		return "", fmt.Errorf("slot %d is not served by any node", slot)
+	}
+	for _, n := range nodes {
+		if n.MasterID == owner.ID && n.LinkState == "connected" && !n.HasFlag("fail") && !n.HasFlag("fail?") {
+			return n.Addr, nil
+		}
+	}
+	return "", fmt.Errorf("no connected replica of %s, the master of slot %d", owner.Addr, slot)
+}
+
+// doOnNode runs a command on node, after READONLY with readOnly so that a
+// cluster replica serves the keys of its master, and returns the trace
+// line when tracing.
+func doOnNode(node *redis.Client, args []interface{}, readOnly bool) (interface{}, string, error) {
+	if activeSession().tracing {
+		return traceOn(node, args, readOnly)
+	}
+	if !readOnly {
+		r, err := node.Do(args...).Result()
+		return r, "", err
+	}
+	do := redis.NewCmd(args...)
+	node.Pipelined(func(pipe redis.Pipeliner) error {
+		pipe.Process(redis.NewStatusCmd("readonly"))
+		pipe.Process(do)
+		return nil
+	})
+	r, err := do.Result()
+	return r, "", err
+}
+
+// parseNodePrefix returns the node of an @host:port prefix.
+func parseNodePrefix(prefix string) (string, error) {
+	addr := prefix[1:]
+	if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+		return "", fmt.Errorf("invalid node %q, should be @host:port", addr)
+	}
+	return addr, nil
+}
+
+// nodeClient returns a connection to the node addr, opened on first use.
+// The connections are kept by the session, by their options, so that a
+// new database or new credentials get a new connection.
+func nodeClient(addr string) *redis.Client {
+	s := activeSession()
+	if s.nodeClients == nil {
+		s.nodeClients = map[conn.Options]*redis.Client{}
+	}
+	opt := clientOptions(addr, *auth)
+	c, ok := s.nodeClients[opt]
+	if !ok {
+		c = conn.NewSingle(opt)
+		s.nodeClients[opt] = c
+	}
+	return c
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+// redis7Commands is part of the COMMAND reply of Redis 7, with its ACL
+// categories, tips, key specs and subcommands.
+var redis7Commands = []interface{}{
+	[]interface{}{"get", int64(2), []interface{}{"readonly", "fast"}, int64(1), int64(1), int64(1),
+		[]interface{}{"@read", "@string", "@fast"}, []interface{}{},
+		[]interface{}{[]interface{}{"flags", []interface{}{"RO", "access"}}}, []interface{}{}},
+	[]interface{}{"set", int64(-3), []interface{}{"write", "denyoom"}, int64(1), int64(1), int64(1),
+		[]interface{}{"@write", "@string", "@slow"}, []interface{}{},
+		[]interface{}{[]interface{}{"flags", []interface{}{"RW", "access", "update"}}}, []interface{}{}},
+	[]interface{}{"mget", int64(-2), []interface{}{"readonly", "fast"}, int64(1), int64(-1), int64(1),
+		[]interface{}{"@read", "@string", "@fast"}, []interface{}{"request_policy:multi_shard"},
+		[]interface{}{[]interface{}{"flags", []interface{}{"RO", "access"}}}, []interface{}{}},
+	[]interface{}{"ping", int64(-1), []interface{}{"fast"}, int64(0), int64(0), int64(0),
+		[]interface{}{"@fast", "@connection"}, []interface{}{}, []interface{}{}, []interface{}{}},
+}
+
+// useCommands makes reply the COMMAND table of the active connection,
+// until the returned function restores the previous one.
+func useCommands(t *testing.T, reply interface{}) func() {
+	infos, err := parseCommandInfos(reply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := serverCommands
+	serverCommands = &commandTable{infos: infos}
+	serverCommands.once.Do(func() {})
+	return func() { serverCommands = prev }
+}
+
+func TestCommandInfoRedis7(t *testing.T) {
+	defer useCommands(t, redis7Commands)()
+
+	if !isReadOnly("GET") || isReadOnly("set") || isReadOnly("unknown") {
+		t.Error("isReadOnly should only hold for GET")
+	}
+
+	if got, want := commandKeys([]string{"MGET", "a", "b", "c"}), []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MGET keys: got %q, want %q", got, want)
+	}
+	if got, want := entryKeys(monitorEntry{Args: []string{"set", "k", "v"}}), []string{"k"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SET entry keys: got %q, want %q", got, want)
+	}
+	if got := entryKeys(monitorEntry{Args: []string{"ping", "hello"}}); got != nil {
+		t.Errorf("PING entry keys: got %q, want none", got)
+	}
+
+	defer func(ro bool) { *readOnly = ro }(*readOnly)
+	*readOnly = true
+	if err := checkAllowed([]string{"get", "k"}); err != nil {
+		t.Errorf("GET in read-only mode: %v", err)
+	}
+	if err := checkAllowed([]string{"set", "k", "v"}); err == nil {
+		t.Error("SET in read-only mode should be refused")
+	}
+}
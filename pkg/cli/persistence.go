@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/info"
+)
+
+// persistence summarizes INFO persistence: the last RDB save and how long
+// ago it was, the AOF status, and the saves, rewrites and loading in
+// progress. A failed BGSAVE, AOF rewrite or AOF write is printed first,
+// as a warning.
+// Usage: PERSISTENCE
+func persistence(args []string) {
+	if len(args) != 0 {
+		fmt.Println("(error) invalid args. Should be PERSISTENCE")
+		return
+	}
+	cliConnect()
+
+	p, err := fetchInfo("persistence")
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if p.Get("rdb_last_save_time") == "" {
+		fmt.Println("(error) INFO persistence has no rdb_last_save_time field")
+		return
+	}
+	now := time.Now()
+	lastSave := time.Unix(p.Int("rdb_last_save_time"), 0)
+	changes := p.Int("rdb_changes_since_last_save")
+
+	for _, w := range persistenceWarnings(p, now.Sub(lastSave), changes) {
+		fmt.Printf("%sWARNING: %s%s\n", highlightStart, w, highlightEnd)
+	}
+
+	fmt.Printf("%srdb%s\n", highlightStart, highlightEnd)
+	fmt.Printf("  %-14s %s (%s ago), %d changes since\n", "last save", lastSave.Format("2006-01-02 15:04:05"), sinceSeconds(now.Sub(lastSave)), changes)
+	fmt.Printf("  %-14s %s", "last bgsave", p.Get("rdb_last_bgsave_status"))
+	if secs := p.Int("rdb_last_bgsave_time_sec"); secs >= 0 {
+		fmt.Printf(", took %ds", secs)
+	}
+	if cow := p.Int("rdb_last_cow_size"); cow > 0 {
+		fmt.Printf(", copy-on-write %s", humanBytes(cow))
+	}
+	fmt.Println()
+	if p.Get("rdb_bgsave_in_progress") == "1" {
+		fmt.Printf("  %-14s running for %ds%s\n", "bgsave", p.Int("rdb_current_bgsave_time_sec"), forkProgress(p))
+	}
+
+	fmt.Printf("%saof%s\n", highlightStart, highlightEnd)
+	if p.Get("aof_enabled") != "1" {
+		fmt.Printf("  %-14s no\n", "enabled")
+	} else {
+		fmt.Printf("  %-14s yes\n", "enabled")
+		fmt.Printf("  %-14s %s\n", "last write", p.Get("aof_last_write_status"))
+		fmt.Printf("  %-14s %s", "last rewrite", p.Get("aof_last_bgrewrite_status"))
+		if secs := p.Int("aof_last_rewrite_time_sec"); secs >= 0 {
+			fmt.Printf(", took %ds", secs)
+		}
+		if cow := p.Int("aof_last_cow_size"); cow > 0 {
+			fmt.Printf(", copy-on-write %s", humanBytes(cow))
+		}
+		fmt.Println()
+		if delayed := p.Int("aof_delayed_fsync"); delayed > 0 {
+			fmt.Printf("  %-14s %d\n", "delayed fsync", delayed)
+		}
+	}
+	if p.Get("aof_rewrite_in_progress") == "1" {
+		fmt.Printf("  %-14s running for %ds%s\n", "rewrite", p.Int("aof_current_rewrite_time_sec"), forkProgress(p))
+	} else if p.Get("aof_rewrite_scheduled") == "1" {
+		fmt.Printf("  %-14s scheduled after the running bgsave\n", "rewrite")
+	}
+
+	if p.Get("loading") == "1" || p.Get("async_loading") == "1" {
+		fmt.Printf("%sloading%s\n", highlightStart, highlightEnd)
+		fmt.Printf("  %-14s %s%% of %s", "progress", p.Get("loading_loaded_perc"), humanBytes(p.Int("loading_total_bytes")))
+		if eta := p.Get("loading_eta_seconds"); eta != "" {
+			fmt.Printf(", %ss left", eta)
+		}
+		fmt.Println()
+	}
+}
+
+// persistenceWarnings returns what failed in INFO persistence.
+func persistenceWarnings(p info.Reply, sinceSave time.Duration, changes int64) []string {
+	var warnings []string
+	if p.Get("rdb_last_bgsave_status") == "err" {
+		warnings = append(warnings, fmt.Sprintf("the last BGSAVE failed, the last successful save was %s ago and %d changes are not on disk; see the server log (disk full, permissions, fork failing for lack of memory)", sinceSeconds(sinceSave), changes))
+	}
+	if p.Get("aof_last_bgrewrite_status") == "err" {
+		warnings = append(warnings, "the last AOF rewrite failed, the AOF keeps growing until one succeeds; see the server log")
+	}
+	if p.Get("aof_last_write_status") == "err" {
+		warnings = append(warnings, "the last AOF write failed, the server refuses writes until it succeeds; see the server log")
+	}
+	return warnings
+}
+
+// forkProgress describes how far the running fork is, when the server
+// says (7.0 or later).
+func forkProgress(p info.Reply) string {
+	perc := p.Get("current_fork_perc")
+	if perc == "" || strings.Trim(perc, "0.") == "" {
+		return ""
+	}
+	return fmt.Sprintf(", %s%% (%d of %d keys)", perc, p.Int("current_save_keys_processed"), p.Int("current_save_keys_total"))
+}
+
+// sinceSeconds formats a duration to the second.
+func sinceSeconds(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
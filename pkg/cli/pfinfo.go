@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// pfMaxIntersect caps the keys for which PFINFO estimates the
+// intersection, which takes a PFCOUNT per subset of keys.
+const pfMaxIntersect = 6
+
+// pfInfo shows the cardinality and encoding of HyperLogLog keys, and for
+// several keys their merged count and intersection estimate.
+// Usage: PFINFO key [key ...]
+func pfInfo(args []string) {
+	if len(args) == 0 {
+		fmt.Println("(error) invalid args. Should be PFINFO key [key ...]")
+		return
+	}
+	cliConnect()
+
+	keys := make([]string, len(args))
+	for i, a := range args {
+		keys[i] = lexer.TrimQuotes(a)
+	}
+
+	var rows [][]string
+	for _, k := range keys {
+		n, err := client.PFCount(k).Result()
+		if err != nil {
+			rows = append(rows, []string{k, "(error) " + err.Error(), ""})
+			continue
+		}
+		rows = append(rows, []string{k, strconv.FormatInt(n, 10), hllEncoding(k)})
+	}
+	printTable([]string{"KEY", "PFCOUNT", "ENCODING"}, rows)
+	if len(keys) == 1 {
+		return
+	}
+
+	fmt.Println()
+	union, err := pfUnion(keys)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Printf("merged count:          %d\n", union)
+	if len(keys) > pfMaxIntersect {
+		fmt.Printf("intersection estimate: (skipped, more than %d keys)\n", pfMaxIntersect)
+		return
+	}
+	inter, err := pfIntersection(keys)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Printf("intersection estimate: %d\n", inter)
+}
+
+// hllEncoding reads the encoding byte from the HYLL header of key.
+func hllEncoding(key string) string {
+	header, err := client.GetRange(key, 0, 4).Result()
+	if err != nil || len(header) < 5 || header[:4] != "HYLL" {
+		return "unknown"
+	}
+	switch header[4] {
+	case 0:
+		return "dense"
+	case 1:
+		return "sparse"
+	}
+	return "unknown"
+}
+
+// pfUnion returns PFCOUNT of the union of keys. When the keys live in
+// different cluster slots it counts temporary copies sharing a hash tag.
+func pfUnion(keys []string) (int64, error) {
+	n, err := client.PFCount(keys...).Result()
+	if err == nil || !strings.HasPrefix(err.Error(), "CROSSSLOT") {
+		return n, err
+	}
+
+	tag := fmt.Sprintf("{pfinfo:%d}", rand.New(rand.NewSource(time.Now().UnixNano())).Int63())
+	tmp := make([]string, 0, len(keys))
+	defer func() {
+		for _, t := range tmp {
+			client.Del(t)
+		}
+	}()
+	for i, k := range keys {
+		v, err := client.Get(k).Result()
+		if err != nil {
+			return 0, err
+		}
+		t := fmt.Sprintf("%s:%d", tag, i)
+		if err := client.Set(t, v, time.Minute).Err(); err != nil {
+			return 0, err
+		}
+		tmp = append(tmp, t)
+	}
+	return client.PFCount(tmp...).Result()
+}
+
+// pfIntersection estimates the intersection of keys by inclusion-exclusion
+// over the union counts of every subset.
+func pfIntersection(keys []string) (int64, error) {
+	var total int64
+	for mask := 1; mask < 1<<uint(len(keys)); mask++ {
+		var subset []string
+		for i, k := range keys {
+			if mask&(1<<uint(i)) != 0 {
+				subset = append(subset, k)
+			}
+		}
+		n, err := pfUnion(subset)
+		if err != nil {
+			return 0, err
+		}
+		if len(subset)%2 == 1 {
+			total += n
+		} else {
+			total -= n
+		}
+	}
+	if total < 0 {
+		total = 0
+	}
+	return total, nil
+}
@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// pipeReportErrors is how many failing commands the final report lists.
+const pipeReportErrors = 10
+
+type pipeError struct {
+	line int
+	cmd  string
+	err  string
+}
+
+// pipeCommand is a command read from the input, with the line it starts on.
+type pipeCommand struct {
+	line int
+	args []string
+}
+
+// pipeMode sends the commands read from stdin, either RESP or one inline
+// command per line, over a single connection and reports the errors. It
+// aborts when more than maxErrors replies are errors (0 means no limit) or
+// when no reply arrives within timeout. It returns the exit code.
+func pipeMode(timeout time.Duration, maxErrors int) int {
+	if err := checkRawMode("-pipe"); err != nil {
+		fmt.Fprintf(os.Stderr, "(error) %s\n", err.Error())
+		return 1
+	}
+	c, err := dialRaw(addr())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "(error) %s\n", err.Error())
+		return 1
+	}
+	defer c.Close()
+	r := bufio.NewReader(c)
+	if err := rawHandshake(c, r); err != nil {
+		fmt.Fprintf(os.Stderr, "(error) %s\n", err.Error())
+		return 1
+	}
+
+	var aborted int32
+	sent := make(chan pipeCommand, 100000)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(sent)
+		w := bufio.NewWriter(c)
+		in := &pipeReader{r: bufio.NewReader(os.Stdin)}
+		for atomic.LoadInt32(&aborted) == 0 {
+			cmd, err := in.next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				readErr <- err
+				break
+			}
+			writeRESP(w, cmd.args)
+			sent <- cmd
+			if in.r.Buffered() == 0 {
+				w.Flush()
+			}
+		}
+		w.Flush()
+	}()
+
+	var replies, errCount int
+	var failures []pipeError
+	abortReason := ""
+	for cmd := range sent {
+		c.SetReadDeadline(time.Now().Add(timeout))
+		msg, isErr, err := readRESPReply(r)
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Timeout() {
+				abortReason = fmt.Sprintf("no reply within %s", timeout)
+			} else {
+				abortReason = err.Error()
+			}
+			break
+		}
+		replies++
+		if !isErr {
+			continue
+		}
+
+		errCount++
+		if len(failures) < pipeReportErrors {
+			failures = append(failures, pipeError{line: cmd.line, cmd: strings.Join(cmd.args, " "), err: msg})
+		}
+		if maxErrors > 0 && errCount > maxErrors {
+			abortReason = fmt.Sprintf("more than %d errors", maxErrors)
+			break
+		}
+	}
+	atomic.StoreInt32(&aborted, 1)
+	select {
+	case err := <-readErr:
+		if abortReason == "" {
+			abortReason = "invalid input: " + err.Error()
+		}
+	default:
+	}
+
+	fmt.Printf("replies: %d, errors: %d\n", replies, errCount)
+	for _, f := range failures {
+		cmd := f.cmd
+		if len(cmd) > 60 {
+			cmd = cmd[:57] + "..."
+		}
+		fmt.Printf("  line %d: %s: %s\n", f.line, cmd, f.err)
+	}
+	if errCount > len(failures) {
+		fmt.Printf("  ... and %d more\n", errCount-len(failures))
+	}
+	if abortReason != "" {
+		fmt.Printf("aborted: %s\n", abortReason)
+		return 1
+	}
+	if errCount > 0 {
+		return 1
+	}
+	return 0
+}
+
+// pipeReader reads commands in RESP or inline form, counting lines.
+type pipeReader struct {
+	r    *bufio.Reader
+	line int
+}
+
+func (p *pipeReader) readLine() (string, error) {
+	l, err := p.r.ReadString('\n')
+	if err != nil && (err != io.EOF || l == "") {
+		return "", err
+	}
+	p.line++
+	return strings.TrimRight(l, "\r\n"), nil
+}
+
+func (p *pipeReader) next() (pipeCommand, error) {
+	for {
+		l, err := p.readLine()
+		if err != nil {
+			return pipeCommand{}, err
+		}
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		start := p.line
+
+		if !strings.HasPrefix(l, "*") {
+			args := lexer.Split(l)
+			for i := range args {
+				args[i] = lexer.TrimQuotes(args[i])
+			}
+			return pipeCommand{line: start, args: args}, nil
+		}
+
+		n, err := strconv.Atoi(l[1:])
+		if err != nil || n <= 0 {
+			return pipeCommand{}, fmt.Errorf("line %d: bad array header %q", start, l)
+		}
+		args := make([]string, n)
+		for i := range args {
+			h, err := p.readLine()
+			if err != nil {
+				return pipeCommand{}, err
+			}
+			size, err := strconv.Atoi(strings.TrimPrefix(h, "$"))
+			if !strings.HasPrefix(h, "$") || err != nil || size < 0 {
+				return pipeCommand{}, fmt.Errorf("line %d: bad bulk header %q", p.line, h)
+			}
+			buf := make([]byte, size+2)
+			if _, err := io.ReadFull(p.r, buf); err != nil {
+				return pipeCommand{}, err
+			}
+			p.line += strings.Count(string(buf), "\n")
+			args[i] = string(buf[:size])
+		}
+		return pipeCommand{line: start, args: args}, nil
+	}
+}
+
+// writeRESP encodes a command as a RESP array of bulk strings.
+func writeRESP(w io.Writer, args []string) {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+}
+
+// readRESPReply reads one reply, returning the error message of error
+// replies.
+func readRESPReply(r *bufio.Reader) (string, bool, error) {
+	l, err := r.ReadString('\n')
+	if err != nil {
+		return "", false, err
+	}
+	l = strings.TrimRight(l, "\r\n")
+	if l == "" {
+		return "", false, fmt.Errorf("empty reply line")
+	}
+
+	switch l[0] {
+	case '-', '!':
+		return l[1:], true, nil
+	case '$', '=':
+		n, _ := strconv.Atoi(l[1:])
+		if n >= 0 {
+			if _, err := io.CopyN(ioutil.Discard, r, int64(n+2)); err != nil {
+				return "", false, err
+			}
+		}
+	case '*', '~', '>', '%', '|':
+		n, _ := strconv.Atoi(l[1:])
+		if l[0] == '%' || l[0] == '|' {
+			n *= 2
+		}
+		for i := 0; i < n; i++ {
+			if _, _, err := readRESPReply(r); err != nil {
+				return "", false, err
+			}
+		}
+	}
+	return "", false, nil
+}
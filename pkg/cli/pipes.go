@@ -0,0 +1,299 @@
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// pipeStage is a filter run by the CLI on a reply, after a | on the
+// command line, since shell pipes are not available in the REPL.
+type pipeStage struct {
+	name string
+	args []string
+}
+
+// pipeStages are the filters a reply can be piped into.
+var pipeStages = map[string]bool{
+	"grep":  true,
+	"sort":  true,
+	"uniq":  true,
+	"count": true,
+	"head":  true,
+	"tail":  true,
+	"jq":    true,
+	"copy":  true,
+}
+
+// splitPipes splits a command line into the command and the stages after
+// it, e.g. LRANGE q 0 -1 | grep err | count. A | only starts a stage when
+// every part after it starts with a stage name, so a "|" argument is still
+// sent as such, except after jq whose expression can hold pipes of its
+// own.
+func splitPipes(cmds []string) ([]string, []pipeStage) {
+	first := -1
+	for i := 1; i < len(cmds); i++ {
+		if cmds[i] == "|" {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return cmds, nil
+	}
+
+	var stages []pipeStage
+	for i := first; i < len(cmds); {
+		// cmds[i] is a |
+		if i+1 >= len(cmds) {
+			return cmds, nil
+		}
+		name := strings.ToLower(cmds[i+1])
+		end := i + 2
+		for end < len(cmds) && cmds[end] != "|" {
+			end++
+		}
+		if !pipeStages[name] {
+			last := len(stages) - 1
+			if last < 0 || stages[last].name != "jq" {
+				return cmds, nil
+			}
+			// jq .a | .b: the rest of the expression
+			stages[last].args = append(stages[last].args, cmds[i:end]...)
+		} else {
+			stages = append(stages, pipeStage{name: name, args: cmds[i+2 : end]})
+		}
+		i = end
+	}
+	for _, s := range stages[:len(stages)-1] {
+		if s.name == "copy" {
+			return cmds, nil
+		}
+	}
+	return cmds[:first], stages
+}
+
+// pipeReply sends a command and runs its reply through the stages. The
+// reply of a SCAN-like command is the full iteration, as KEYS would be.
+func pipeReply(cmds []string, stages []pipeStage) {
+	cliConnect()
+	args := make([]interface{}, len(cmds))
+	for i, c := range cmds {
+		args[i] = lexer.TrimQuotes(c)
+	}
+	node, replica, ok := checkCommand(cmds, args)
+	if !ok {
+		return
+	}
+
+	var r interface{}
+	var err error
+	if idx := scanCursorIndex(strings.ToLower(cmds[0])); idx > 0 && idx < len(args) {
+		r, err = scanAll(node, args, idx)
+	} else {
+		r, _, _, err = sendCommand(args, node, replica)
+	}
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	for i, s := range stages {
+		switch s.name {
+		case "copy":
+			copyReply(r)
+			return
+		case "jq":
+			expr := "."
+			if len(s.args) > 0 {
+				parts := make([]string, len(s.args))
+				for j, a := range s.args {
+					parts[j] = lexer.TrimQuotes(a)
+				}
+				expr = strings.Join(parts, " ")
+			}
+			src := cmds
+			if i > 0 {
+				src = nil
+			}
+			outs, err := runJQ(expr, jqValue(src, r))
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			if i == len(stages)-1 {
+				printJQ(outs)
+				return
+			}
+			r = jqLines(outs)
+		default:
+			if r, err = textStage(s, replyLines(r)); err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+		}
+	}
+	printReply(0, r, mode)
+	fmt.Println()
+}
+
+// scanAll iterates a SCAN-like command routed to node to the end and
+// returns every element, from every master in cluster mode.
+func scanAll(node string, args []interface{}, idx int) (interface{}, error) {
+	nodes, err := commandScanNodes(strings.ToLower(fmt.Sprint(args[0])), node)
+	if err != nil {
+		return nil, err
+	}
+	all := []interface{}{}
+	start := args[idx]
+	for _, n := range nodes {
+		args[idx] = start
+		for {
+			r, err := n.Do(args...).Result()
+			if err != nil {
+				return nil, err
+			}
+			items, _ := r.([]interface{})
+			if len(items) == 2 {
+				elems, _ := items[1].([]interface{})
+				all = append(all, elems...)
+			}
+			cursor := scanCursor(r)
+			if cursor == "0" {
+				break
+			}
+			args[idx] = cursor
+		}
+		start = "0"
+	}
+	return all, nil
+}
+
+// replyLines flattens a reply into the lines the text stages filter: the
+// elements of arrays, nested ones included, or the lines of a string such
+// as INFO.
+func replyLines(reply interface{}) []string {
+	switch reply := reply.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var lines []string
+		for _, e := range reply {
+			if _, nested := e.([]interface{}); nested {
+				lines = append(lines, replyLines(e)...)
+			} else {
+				lines = append(lines, format.Sprint(e, format.Raw))
+			}
+		}
+		return lines
+	case string:
+		lines := strings.Split(strings.TrimRight(reply, "\r\n"), "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight(l, "\r")
+		}
+		return lines
+	}
+	return []string{format.Sprint(reply, format.Raw)}
+}
+
+// textStage runs grep, sort, uniq, count, head or tail on lines.
+func textStage(s pipeStage, lines []string) (interface{}, error) {
+	flags := map[string]bool{}
+	var operands []string
+	for _, a := range s.args {
+		a = lexer.TrimQuotes(a)
+		if len(a) > 1 && a[0] == '-' && len(operands) == 0 {
+			if _, err := strconv.Atoi(a[1:]); err != nil {
+				flags[a] = true
+				continue
+			}
+		}
+		operands = append(operands, a)
+	}
+
+	switch s.name {
+	case "grep":
+		if len(operands) != 1 {
+			return nil, fmt.Errorf("invalid args. Should be grep [-v] [-i] regex")
+		}
+		pattern := operands[0]
+		if flags["-i"] {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		var kept []string
+		for _, l := range lines {
+			if re.MatchString(l) != flags["-v"] {
+				kept = append(kept, l)
+			}
+		}
+		return linesReply(kept), nil
+	case "sort":
+		sorted := append([]string(nil), lines...)
+		less := func(i, j int) bool { return sorted[i] < sorted[j] }
+		if flags["-n"] {
+			less = func(i, j int) bool {
+				a, _ := strconv.ParseFloat(sorted[i], 64)
+				b, _ := strconv.ParseFloat(sorted[j], 64)
+				return a < b
+			}
+		}
+		if flags["-r"] {
+			asc := less
+			less = func(i, j int) bool { return asc(j, i) }
+		}
+		sort.SliceStable(sorted, less)
+		return linesReply(sorted), nil
+	case "uniq":
+		var kept []string
+		var counts []int
+		for _, l := range lines {
+			if n := len(kept); n > 0 && kept[n-1] == l {
+				counts[n-1]++
+				continue
+			}
+			kept = append(kept, l)
+			counts = append(counts, 1)
+		}
+		if flags["-c"] {
+			for i := range kept {
+				kept[i] = fmt.Sprintf("%d %s", counts[i], kept[i])
+			}
+		}
+		return linesReply(kept), nil
+	case "count":
+		return int64(len(lines)), nil
+	case "head", "tail":
+		n := 10
+		if len(operands) > 0 {
+			var err error
+			if n, err = strconv.Atoi(strings.TrimPrefix(operands[0], "-")); err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid args. Should be %s [n]", s.name)
+			}
+		}
+		if n > len(lines) {
+			n = len(lines)
+		}
+		if s.name == "head" {
+			return linesReply(lines[:n]), nil
+		}
+		return linesReply(lines[len(lines)-n:]), nil
+	}
+	return nil, fmt.Errorf("unknown stage %s", s.name)
+}
+
+func linesReply(lines []string) []interface{} {
+	reply := make([]interface{}, len(lines))
+	for i, l := range lines {
+		reply[i] = l
+	}
+	return reply
+}
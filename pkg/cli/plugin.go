@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// pluginDir holds external plugins: any executable in it becomes a REPL
+// command of the same name.
+var pluginDir = path.Join(os.Getenv("HOME"), ".gorediscli_plugins") // $HOME/.gorediscli_plugins
+
+// PluginFunc runs a custom command with its arguments, quotes removed,
+// and the live client.
+type PluginFunc func(c *redis.ClusterClient, args []string) error
+
+type plugin struct {
+	name  string
+	usage string
+	run   PluginFunc
+}
+
+var plugins = map[string]plugin{}
+
+// RegisterPlugin adds a custom REPL command. In-process plugins live in
+// their own file of cmd/redis-cli, guarded by a build tag, and register
+// from init:
+//
+//	// +build acme
+//
+//	package main
+//
+//	import "github.com/holys/redis-cli/pkg/cli"
+//
+//	func init() {
+//		cli.RegisterPlugin("acme-session", "acme-session id", decodeSession)
+//	}
+//
+// and are compiled in with go build -tags acme.
+func RegisterPlugin(name, usage string, run PluginFunc) {
+	name = strings.ToLower(name)
+	plugins[name] = plugin{name: name, usage: usage, run: run}
+}
+
+// lookupPlugin finds a plugin by command name, registered ones first and
+// then executables in pluginDir.
+func lookupPlugin(name string) (plugin, bool) {
+	name = strings.ToLower(name)
+	if p, ok := plugins[name]; ok {
+		return p, true
+	}
+
+	if strings.ContainsAny(name, `/\`) {
+		return plugin{}, false
+	}
+	file := path.Join(pluginDir, name)
+	if fi, err := os.Stat(file); err != nil || fi.IsDir() || fi.Mode()&0111 == 0 {
+		return plugin{}, false
+	}
+	return plugin{name: name, usage: name + " [args ...]", run: externalPlugin(file)}, true
+}
+
+// externalPlugin runs an executable with the command arguments. The
+// current connection is passed in REDIS_URL, with the password. As the
+// executable connects on its own, it is refused with -read-only or -allow.
+func externalPlugin(file string) PluginFunc {
+	return func(c *redis.ClusterClient, args []string) error {
+		if err := checkRawMode("an external plugin"); err != nil {
+			return err
+		}
+		cmd := exec.Command(file, args...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Env = append(os.Environ(), "REDIS_URL="+connURI(true))
+		return cmd.Run()
+	}
+}
+
+// runPlugin runs p with the raw REPL arguments.
+func runPlugin(p plugin, cmds []string) {
+	cliConnect()
+
+	args := make([]string, len(cmds))
+	for i, arg := range cmds {
+		args[i] = lexer.TrimQuotes(arg)
+	}
+	if err := p.run(client, args); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+	}
+}
+
+// listPlugins prints the registered and external plugins.
+func listPlugins() {
+	var usages []string
+	for _, p := range plugins {
+		usages = append(usages, p.usage)
+	}
+	if dir, err := os.Open(pluginDir); err == nil {
+		names, _ := dir.Readdirnames(-1)
+		dir.Close()
+		for _, name := range names {
+			if _, ok := plugins[strings.ToLower(name)]; ok {
+				continue
+			}
+			if p, ok := lookupPlugin(name); ok {
+				usages = append(usages, p.usage)
+			}
+		}
+	}
+
+	if len(usages) == 0 {
+		fmt.Println("(empty list)")
+		return
+	}
+	sort.Strings(usages)
+	for _, u := range usages {
+		fmt.Println(u)
+	}
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var stdinReader *bufio.Reader
+
+// errNoInput is returned when a confirmation is needed but -no-input is set.
+var errNoInput = errors.New("confirmation required, refusing to prompt because of -no-input")
+
+// askUser shows msg and reads one line of answer, through the line
+// editor in interactive mode or from stdin otherwise. With -yes the
+// answer is always "y", with -no-input it fails without prompting.
+func askUser(msg string) (string, error) {
+	if *assumeYes {
+		return "y", nil
+	}
+	if *noInput {
+		return "", errNoInput
+	}
+
+	if line != nil {
+		answer, err := line.Prompt(msg)
+		return strings.TrimSpace(answer), err
+	}
+
+	if stdinReader == nil {
+		stdinReader = bufio.NewReader(os.Stdin)
+	}
+	fmt.Print(msg)
+	answer, err := stdinReader.ReadString('\n')
+	if err != nil && answer == "" {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// confirm asks a yes/no question and reports whether the user agreed.
+// Errors, including -no-input, count as a refusal.
+func confirm(msg string) bool {
+	answer, err := askUser(msg + " (y/n) ")
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return false
+	}
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes"
+}
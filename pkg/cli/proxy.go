@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+)
+
+// delayProxy forwards plain TCP connections accepted on listen to the
+// server, delaying every chunk of data by delay plus up to jitter, and
+// cutting the connection with probability drop per chunk.
+func delayProxy(listen string, delay, jitter time.Duration, drop float64) error {
+	if err := checkRawMode("-delay-proxy"); err != nil {
+		return err
+	}
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	fmt.Printf("proxying %s to %s (delay %s, jitter %s, drop %.2f%%)\n", listen, addr(), delay, jitter, drop*100)
+
+	for {
+		downstream, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			defer downstream.Close()
+			upstream, err := dialRaw(addr())
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			defer upstream.Close()
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				delayCopy(upstream, downstream, delay, jitter, drop)
+				upstream.Close()
+			}()
+			go func() {
+				defer wg.Done()
+				delayCopy(downstream, upstream, delay, jitter, drop)
+				downstream.Close()
+			}()
+			wg.Wait()
+		}()
+	}
+}
+
+// delayCopy copies src to dst chunk by chunk, sleeping before each write.
+func delayCopy(dst io.Writer, src io.Reader, delay, jitter time.Duration, drop float64) {
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := src.Read(buf)
+		if n > 0 {
+			if drop > 0 && rand.Float64() < drop {
+				return
+			}
+			d := delay
+			if jitter > 0 {
+				d += time.Duration(rand.Int63n(int64(jitter)))
+			}
+			time.Sleep(d)
+			if _, err := dst.Write(buf[:n]); err != nil {
+				return
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
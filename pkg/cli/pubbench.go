@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// benchEnd is published when a PUBLISH-BENCH run ends, so listeners print
+// their summary and stop.
+const benchEnd = "pb end"
+
+// publishBench publishes synthetic messages at a steady rate. With
+// --listen it is the other end: it subscribes to the channel and reports
+// how long the messages took to arrive, and how many got lost. Each
+// message carries its sequence number and send time, so run the listener
+// on the same host or one with a synchronized clock.
+// Usage: PUBLISH-BENCH channel [--rate n] [--payload bytes] [--duration d] [--listen]
+func publishBench(args []string) {
+	const usage = "(error) invalid args. Should be PUBLISH-BENCH channel [--rate n] [--payload bytes] [--duration d] [--listen]"
+	var channel string
+	rate, payload := 1000, 128
+	duration := 10 * time.Second
+	listen := false
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		var err error
+		switch {
+		case opt == "--listen":
+			listen = true
+		case opt == "--rate" && i+1 < len(args):
+			rate, err = strconv.Atoi(args[i+1])
+			if rate <= 0 {
+				err = fmt.Errorf("invalid rate")
+			}
+			i++
+		case opt == "--payload" && i+1 < len(args):
+			payload, err = strconv.Atoi(args[i+1])
+			i++
+		case opt == "--duration" && i+1 < len(args):
+			duration, err = time.ParseDuration(args[i+1])
+			i++
+		case channel == "":
+			channel = lexer.TrimQuotes(args[i])
+		default:
+			err = fmt.Errorf("unexpected %s", args[i])
+		}
+		if err != nil {
+			fmt.Println(usage)
+			return
+		}
+	}
+	if channel == "" {
+		fmt.Println(usage)
+		return
+	}
+	cliConnect()
+	if err := checkAllowed([]string{"publish", channel}); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	if listen {
+		listenBench(channel)
+		return
+	}
+	runPublishBench(channel, rate, payload, duration)
+}
+
+// benchTick is how often the publisher sends the messages due.
+const benchTick = 10 * time.Millisecond
+
+func runPublishBench(channel string, rate, payload int, duration time.Duration) {
+	interrupt, stop := interrupted()
+	defer stop()
+
+	fmt.Printf("publishing %d messages/s of %d bytes to %s for %s, press Ctrl-C to stop\n", rate, payload, channel, duration)
+	tick := time.NewTicker(benchTick)
+	defer tick.Stop()
+	report := time.NewTicker(time.Second)
+	defer report.Stop()
+
+	start := time.Now()
+	var sent, receivers, lastSent int64
+	lastReport := start
+loop:
+	for {
+		select {
+		case <-interrupt:
+			break loop
+		case now := <-report.C:
+			fmt.Printf("%s sent %d (%.0f/s), %.1f subscribers per message\n",
+				now.Sub(start).Round(time.Second), sent, float64(sent-lastSent)/now.Sub(lastReport).Seconds(), float64(receivers)/float64(max64(sent, 1)))
+			lastSent, lastReport = sent, now
+		case now := <-tick.C:
+			if now.Sub(start) >= duration {
+				break loop
+			}
+			due := int64(now.Sub(start).Seconds()*float64(rate)) - sent
+			if due <= 0 {
+				continue
+			}
+			var cmds []*redis.IntCmd
+			_, err := client.Pipelined(func(pipe redis.Pipeliner) error {
+				for i := int64(0); i < due; i++ {
+					cmds = append(cmds, pipe.Publish(channel, benchMessage(sent+i, payload)))
+				}
+				return nil
+			})
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			for _, c := range cmds {
+				receivers += c.Val()
+			}
+			sent += due
+		}
+	}
+	client.Publish(channel, benchEnd)
+
+	elapsed := time.Since(start)
+	fmt.Printf("\nsent %d messages in %s, %.0f/s, %.1f subscribers per message\n",
+		sent, elapsed.Round(time.Millisecond), float64(sent)/elapsed.Seconds(), float64(receivers)/float64(max64(sent, 1)))
+	if receivers == 0 {
+		fmt.Printf("no subscriber received them, start one with PUBLISH-BENCH %s --listen\n", channel)
+	}
+}
+
+// benchMessage returns message seq, stamped with the time and padded to
+// size bytes.
+func benchMessage(seq int64, size int) string {
+	msg := fmt.Sprintf("pb %d %d ", seq, time.Now().UnixNano())
+	if len(msg) < size {
+		msg += strings.Repeat("x", size-len(msg))
+	}
+	return msg
+}
+
+// parseBenchMessage returns the sequence number and send time of a bench
+// message.
+func parseBenchMessage(msg string) (int64, time.Time, bool) {
+	fields := strings.SplitN(msg, " ", 4)
+	if len(fields) < 3 || fields[0] != "pb" {
+		return 0, time.Time{}, false
+	}
+	seq, err1 := strconv.ParseInt(fields[1], 10, 64)
+	ns, err2 := strconv.ParseInt(fields[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, time.Time{}, false
+	}
+	return seq, time.Unix(0, ns), true
+}
+
+func listenBench(channel string) {
+	pubsub := client.Subscribe(channel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	interrupt, stop := interrupted()
+	defer stop()
+	fmt.Printf("waiting for PUBLISH-BENCH messages on %s, press Ctrl-C to stop\n", channel)
+
+	report := time.NewTicker(time.Second)
+	defer report.Stop()
+
+	var all, window []time.Duration
+	var lost int64
+	next := int64(-1)
+	messages := pubsub.Channel()
+loop:
+	for {
+		select {
+		case <-interrupt:
+			break loop
+		case <-report.C:
+			if len(window) > 0 {
+				fmt.Printf("received %d/s, latency %s\n", len(window), latencySummary(window))
+				window = window[:0]
+			}
+		case msg, ok := <-messages:
+			if !ok {
+				break loop
+			}
+			if msg.Payload == benchEnd {
+				break loop
+			}
+			seq, sentAt, ok := parseBenchMessage(msg.Payload)
+			if !ok {
+				continue
+			}
+			d := time.Since(sentAt)
+			all = append(all, d)
+			window = append(window, d)
+			// a new run starts again at 0
+			if next >= 0 && seq > next {
+				lost += seq - next
+			}
+			next = seq + 1
+		}
+	}
+
+	if len(all) == 0 {
+		fmt.Println("no messages received")
+		return
+	}
+	fmt.Printf("\nreceived %d messages, %d lost, latency %s\n", len(all), lost, latencySummary(all))
+}
+
+// latencySummary formats the min, percentiles and max of durations.
+func latencySummary(ds []time.Duration) string {
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	p := func(q float64) time.Duration {
+		return sorted[int(q*float64(len(sorted)-1))].Round(time.Microsecond)
+	}
+	return fmt.Sprintf("min %s p50 %s p90 %s p99 %s max %s", p(0), p(0.5), p(0.9), p(0.99), p(1))
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
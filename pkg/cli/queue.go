@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// queueView shows the length of a list, its first and last elements, and
+// how fast it grows over a sampling window.
+// Usage: QUEUE key [--n count] [--window seconds]
+func queueView(args []string) {
+	const usage = "(error) invalid args. Should be QUEUE key [--n count] [--window seconds]"
+	var key string
+	n := int64(5)
+	window := 5 * time.Second
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		if (opt == "--n" || opt == "--window") && i+1 < len(args) {
+			v, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || v < 0 {
+				fmt.Println(usage)
+				return
+			}
+			if opt == "--n" {
+				n = int64(v)
+			} else {
+				window = time.Duration(v * float64(time.Second))
+			}
+			i++
+		} else if key == "" {
+			key = lexer.TrimQuotes(args[i])
+		} else {
+			fmt.Println(usage)
+			return
+		}
+	}
+	if key == "" {
+		fmt.Println(usage)
+		return
+	}
+	cliConnect()
+
+	before, err := client.LLen(key).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Printf("length: %d\n", before)
+
+	if n > 0 && before > 0 {
+		head, err := client.LRange(key, 0, n-1).Result()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		fmt.Println("\nhead:")
+		for i, v := range head {
+			fmt.Printf("%6d) %q\n", i, v)
+		}
+
+		if before > n {
+			tail, err := client.LRange(key, -n, -1).Result()
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			fmt.Println("\ntail:")
+			for i, v := range tail {
+				fmt.Printf("%6d) %q\n", before-int64(len(tail))+int64(i), v)
+			}
+		}
+	}
+
+	if window <= 0 {
+		return
+	}
+	fmt.Printf("\nsampling for %s...\n", window)
+	start := time.Now()
+	time.Sleep(window)
+	after, err := client.LLen(key).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	elapsed := time.Since(start).Seconds()
+	rate := float64(after-before) / elapsed
+	fmt.Printf("length: %d -> %d, %+.2f/s\n", before, after, rate)
+	if rate < 0 {
+		fmt.Printf("drains in about %s\n", time.Duration(float64(after)/-rate*float64(time.Second)).Round(time.Second))
+	}
+}
@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+	"github.com/holys/redis-cli/pkg/rdb"
+)
+
+// rdbTypeStats counts the keys of a type in a database.
+type rdbTypeStats struct {
+	db    int
+	typ   string
+	keys  int64
+	bytes int64
+}
+
+// rdbInfo reads an RDB file offline, without a server: the keys and bytes
+// per database and type, the biggest keys, and the TTLs the keys had when
+// the file was saved.
+// Usage: RDB-INFO file [--top n]
+func rdbInfo(args []string) {
+	const usage = "(error) invalid args. Should be RDB-INFO file [--top n]"
+	file := ""
+	top := 10
+	for i := 0; i < len(args); i++ {
+		if strings.ToLower(args[i]) == "--top" && i+1 < len(args) {
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 0 {
+				fmt.Println(usage)
+				return
+			}
+			top = n
+			i++
+		} else if file == "" {
+			file = lexer.TrimQuotes(args[i])
+		} else {
+			fmt.Println(usage)
+			return
+		}
+	}
+	if file == "" {
+		fmt.Println(usage)
+		return
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	d, err := rdb.NewDecoder(f)
+	if err != nil {
+		fmt.Printf("(error) %s: %s\n", file, err.Error())
+		return
+	}
+
+	stats := map[string]*rdbTypeStats{}
+	var biggest []*rdb.Entry
+	hist := make([]int, len(ttlBuckets))
+	var withTTL, expired int
+	var total, totalBytes int64
+	var readErr error
+	for {
+		e, err := d.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			readErr = err
+			break
+		}
+		total++
+		totalBytes += e.Bytes
+		k := fmt.Sprintf("%d %s", e.DB, e.Type)
+		s, ok := stats[k]
+		if !ok {
+			s = &rdbTypeStats{db: e.DB, typ: e.Type}
+			stats[k] = s
+		}
+		s.keys++
+		s.bytes += e.Bytes
+
+		if top > 0 && (len(biggest) < top || e.Bytes > biggest[len(biggest)-1].Bytes) {
+			i := sort.Search(len(biggest), func(i int) bool { return biggest[i].Bytes < e.Bytes })
+			biggest = append(biggest, nil)
+			copy(biggest[i+1:], biggest[i:])
+			biggest[i] = e
+			if len(biggest) > top {
+				biggest = biggest[:top]
+			}
+		}
+		if e.Expiry.IsZero() {
+			continue
+		}
+		withTTL++
+		ttl := e.Expiry.Sub(rdbSaveTime(d, st.ModTime()))
+		if ttl <= 0 {
+			expired++
+			continue
+		}
+		for b, bucket := range ttlBuckets {
+			if bucket.limit == 0 || ttl < bucket.limit {
+				hist[b]++
+				break
+			}
+		}
+	}
+
+	saved := rdbSaveTime(d, st.ModTime())
+	fmt.Printf("%s%s%s: RDB version %d", highlightStart, file, highlightEnd, d.Version)
+	if v := d.Aux["redis-ver"]; v != "" {
+		fmt.Printf(", redis %s", v)
+	}
+	fmt.Printf(", saved %s, %s, %d keys\n", saved.Format("2006-01-02 15:04:05"), humanBytes(st.Size()), total)
+	if readErr != nil {
+		fmt.Printf("(error) %s, the counts stop there\n", readErr.Error())
+	}
+	if total == 0 {
+		return
+	}
+
+	list := make([]*rdbTypeStats, 0, len(stats))
+	for _, s := range stats {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].db != list[j].db {
+			return list[i].db < list[j].db
+		}
+		return list[i].bytes > list[j].bytes
+	})
+	var rows [][]string
+	for _, s := range list {
+		rows = append(rows, []string{strconv.Itoa(s.db), s.typ, strconv.FormatInt(s.keys, 10), humanBytes(s.bytes), percent(s.bytes, totalBytes)})
+	}
+	fmt.Println()
+	printTable([]string{"DB", "TYPE", "KEYS", "BYTES", "SHARE"}, rows)
+
+	if len(biggest) > 0 {
+		fmt.Printf("\n%sbiggest keys%s (bytes in the file, compressed)\n", highlightStart, highlightEnd)
+		rows = nil
+		for _, e := range biggest {
+			elements := "-"
+			if e.Elements >= 0 {
+				elements = strconv.FormatInt(e.Elements, 10)
+			}
+			rows = append(rows, []string{e.Key, strconv.Itoa(e.DB), e.Type, elements, humanBytes(e.Bytes)})
+		}
+		printTable([]string{"KEY", "DB", "TYPE", "ELEMENTS", "BYTES"}, rows)
+	}
+
+	fmt.Printf("\n%sTTL at save time%s\n", highlightStart, highlightEnd)
+	n := int(total)
+	rows = [][]string{
+		{"no TTL", strconv.Itoa(n - withTTL), ttlBar(n-withTTL, n)},
+		{"expired", strconv.Itoa(expired), ttlBar(expired, n)},
+	}
+	for b, bucket := range ttlBuckets {
+		rows = append(rows, []string{bucket.name, strconv.Itoa(hist[b]), ttlBar(hist[b], n)})
+	}
+	printTable([]string{"TTL", "KEYS", ""}, rows)
+}
+
+// rdbSaveTime returns when the file was saved, which the ctime field of
+// the file says, or else its modification time.
+func rdbSaveTime(d *rdb.Decoder, modTime time.Time) time.Time {
+	if ctime, err := strconv.ParseInt(d.Aux["ctime"], 10, 64); err == nil {
+		return time.Unix(ctime, 0)
+	}
+	return modTime
+}
@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+	"github.com/peterh/liner"
+)
+
+// Read-Eval-Print Loop
+func repl() {
+	line = liner.NewLiner()
+	line.SetCtrlCAborts(true)
+	// restore the terminal before a panic is printed
+	defer func() {
+		if r := recover(); r != nil {
+			closeTerminal()
+			panic(r)
+		}
+	}()
+
+	setCompletionHandler()
+	loadHistory()
+
+	prompt := ""
+
+	cliConnect()
+
+	if *showWelcome {
+		showWelcomeMsg()
+		showServerBanner()
+	}
+	runInit()
+
+	for {
+		addr := addr()
+		// show which ACL user the session runs as
+		if *user != "" {
+			addr = *user + "@" + addr
+		}
+		addr = sessionPrompt() + addr
+		if *dbn > 0 {
+			prompt = fmt.Sprintf("%s[%d]> ", addr, *dbn)
+		} else {
+			prompt = fmt.Sprintf("%s> ", addr)
+		}
+
+		cmd, err := line.Prompt(prompt)
+		if err != nil {
+			fmt.Printf("%s\n", err.Error())
+			exit(0)
+		}
+
+		cmds := lexer.Split(cmd)
+		if len(cmds) == 0 {
+			continue
+		} else {
+
+			appendHistory(cmds)
+			recordMacro(cmds)
+
+			execCommand(cmds)
+		}
+	}
+}
+
+// execCommand runs one command line, either handled by the CLI itself or
+// sent to the server.
+func execCommand(cmds []string) {
+	// ":on name cmd | copy" copies the reply of the other connection
+	if strings.ToLower(cmds[0]) == ":on" {
+		runOnSession(cmds[1:])
+		return
+	}
+	if strings.ToLower(cmds[0]) == ":all" {
+		runOnAll(cmds[1:])
+		return
+	}
+	// "@host:port cmd" runs one command on that node, "@replica cmd" on
+	// a replica
+	if strings.HasPrefix(cmds[0], "@") {
+		var err error
+		if len(cmds) == 1 {
+			err = fmt.Errorf("invalid args. Should be @host:port|@replica command [arg ...]")
+		} else if strings.ToLower(cmds[0]) == "@replica" {
+			targetReplica = true
+		} else {
+			targetNode, err = parseNodePrefix(cmds[0])
+		}
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		defer func() { targetNode, targetReplica = "", false }()
+		cmds = cmds[1:]
+	}
+	// $_1 refers to a past reply at the prompt only, SHOW $_1 names a
+	// reply rather than using it
+	if line != nil && strings.ToLower(cmds[0]) != "show" {
+		expanded, err := expandReplies(cmds)
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		cmds = expanded
+	}
+	if c, stages := splitPipes(cmds); stages != nil {
+		pipeReply(c, stages)
+		return
+	}
+
+	cmd := strings.ToLower(cmds[0])
+	if cmd == "help" || cmd == "?" {
+		printHelp(cmds)
+	} else if cmd == "quit" || cmd == "exit" {
+		exit(0)
+	} else if cmd == "clear" {
+		println("Please use Ctrl + L instead")
+	} else if cmd == "connect" {
+		reconnect(cmds[1:])
+	} else if cmd == "mode" {
+		switchMode(cmds[1:])
+	} else if cmd == "can" {
+		can(cmds[1:])
+	} else if cmd == "session" {
+		sessionCommand(cmds[1:])
+	} else if cmd == "show" {
+		showReply(cmds[1:])
+	} else if cmd == "explain" {
+		explain(cmds[1:])
+	} else if cmd == "trace" {
+		traceCommand(cmds[1:])
+	} else if cmd == "uri" {
+		printURI(cmds[1:])
+	} else if cmd == "dumpkey" {
+		dumpKey(cmds[1:])
+	} else if cmd == "restorekey" {
+		restoreKey(cmds[1:])
+	} else if cmd == "cluster-health" {
+		clusterHealth(cmds[1:])
+	} else if cmd == "slots" {
+		slotsView(cmds[1:])
+	} else if cmd == "slot-keys" {
+		slotKeys(cmds[1:])
+	} else if cmd == "hot-slots" {
+		hotSlots(cmds[1:])
+	} else if cmd == "hashtag" {
+		hashTagView(cmds[1:])
+	} else if cmd == "tag-check" {
+		tagCheck(cmds[1:])
+	} else if cmd == "cluster" && len(cmds) > 1 && strings.ToLower(cmds[1]) == "move-slots" {
+		moveSlots(cmds[2:])
+	} else if cmd == "flushdb-safe" {
+		flushSafe("FLUSHDB", cmds[1:])
+	} else if cmd == "flushall-safe" {
+		flushSafe("FLUSHALL", cmds[1:])
+	} else if cmd == "generate" {
+		generateData(cmds[1:])
+	} else if cmd == "watchkey" {
+		watchKey(cmds[1:])
+	} else if cmd == "diffcmd" {
+		diffCommands(cmds[1:])
+	} else if cmd == "macro" {
+		macroCommand(cmds[1:])
+	} else if cmd == "bits" {
+		bitsView(cmds[1:])
+	} else if cmd == "leaderboard" {
+		leaderboard(cmds[1:])
+	} else if cmd == "pfinfo" {
+		pfInfo(cmds[1:])
+	} else if cmd == "queue" {
+		queueView(cmds[1:])
+	} else if cmd == "encoding-stats" {
+		encodingStats(cmds[1:])
+	} else if cmd == "memory-by-pattern" {
+		memoryByPattern(cmds[1:])
+	} else if cmd == "rdb-info" {
+		rdbInfo(cmds[1:])
+	} else if cmd == "save-watch" {
+		saveWatch(cmds[1:])
+	} else if cmd == "persistence" {
+		persistence(cmds[1:])
+	} else if cmd == "frag-report" {
+		fragReport(cmds[1:])
+	} else if cmd == "expiry-report" {
+		expiryReport(cmds[1:])
+	} else if cmd == "maintenance" {
+		maintenance(cmds[1:])
+	} else if cmd == "debug" {
+		debugCommand(cmds)
+	} else if cmd == "find-in" {
+		findIn(cmds[1:])
+	} else if cmd == "grep-values" {
+		grepValues(cmds[1:])
+	} else if cmd == "sample" {
+		sampleKeys(cmds[1:])
+	} else if cmd == "latency-events" {
+		latencyEvents(cmds[1:])
+	} else if cmd == "publish-bench" {
+		publishBench(cmds[1:])
+	} else if cmd == "subscribe" || cmd == "psubscribe" {
+		subscribe(cmd, cmds[1:])
+	} else if cmd == "monitor" {
+		monitor(cmds[1:])
+	} else if cmd == "stream-info" {
+		streamInfo(cmds[1:])
+	} else if cmd == "xtail" {
+		xtail(cmds[1:])
+	} else if cmd == "notifications" {
+		notifications(cmds[1:])
+	} else if cmd == "slowlog-tail" {
+		slowlogTail(cmds[1:])
+	} else if cmd == "edit" {
+		editKey(cmds[1:])
+	} else if cmd == "select" {
+		selectDB(cmds[1:])
+	} else if cmd == "commands" {
+		commandsSheet(cmds[1:])
+	} else if cmd == "version" {
+		printVersion()
+	} else if cmd == "hello" {
+		hello(cmds[1:])
+	} else if cmd == "auth" {
+		authenticate(cmds[1:])
+	} else if cmd == "open" {
+		openSession(cmds[1:])
+	} else if cmd == "use" {
+		useSession(cmds[1:])
+	} else if cmd == "close" {
+		closeSession(cmds[1:])
+	} else if cmd == "sentinel" {
+		sentinelCommand(cmds)
+	} else if cmd == "script" && len(cmds) > 1 && strings.ToLower(cmds[1]) == "run" {
+		runScript(cmds[2:])
+	} else if cmd == "scripts" {
+		scriptsCommand(cmds[1:])
+	} else if cmd == "evalsha-by-name" {
+		evalshaByName(cmds[1:])
+	} else if cmd == "plugins" {
+		listPlugins()
+	} else if p, ok := lookupPlugin(cmd); ok {
+		runPlugin(p, cmds[1:])
+	} else {
+		cliSendCommand(cmds...)
+	}
+}
+
+func appendHistory(cmds []string) {
+	entry := strings.Join(maskSecrets(cmds), " ")
+	line.AppendHistory(entry)
+	appendHistoryFile(entry)
+}
+
+// maskSecrets returns a copy of cmds with the passwords it holds replaced
+// by ******.
+func maskSecrets(cmds []string) []string {
+	// make a copy of cmds
+	cloneCmds := make([]string, len(cmds))
+	for i, cmd := range cmds {
+		cloneCmds[i] = cmd
+	}
+
+	// for security reason, hide the password with ******
+	if (len(cloneCmds) == 2 || len(cloneCmds) == 3) && strings.ToLower(cloneCmds[0]) == "auth" {
+		cloneCmds[len(cloneCmds)-1] = "******"
+	}
+	// and whatever follows an AUTH option, as in HELLO 3 AUTH user secret
+	// or MIGRATE ... AUTH2 user secret
+	for i := 1; i < len(cloneCmds)-1; i++ {
+		if opt := strings.ToLower(cloneCmds[i]); opt == "auth" || opt == "auth2" {
+			cloneCmds = append(cloneCmds[:i+1], "******")
+			break
+		}
+	}
+	if len(cloneCmds) == 4 && strings.ToLower(cloneCmds[0]) == "connect" {
+		cloneCmds[3] = "******"
+	}
+	if len(cloneCmds) >= 3 && strings.ToLower(cloneCmds[0]) == "open" {
+		if u, err := url.Parse(lexer.TrimQuotes(cloneCmds[2])); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), "******")
+				cloneCmds[2] = u.String()
+			}
+		}
+	}
+	return cloneCmds
+}
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// replicaAddr returns the address of the first online replica listed in
+// INFO replication of the current connection.
+func replicaAddr() (string, error) {
+	srv, err := fetchInfo("replication")
+	if err != nil {
+		return "", err
+	}
+	for i := 0; ; i++ {
+		// slave0:ip=10.0.0.2,port=6379,state=online,offset=123,lag=0
+		v := srv.Get(fmt.Sprintf("slave%d", i))
+		if v == "" {
+			return "", fmt.Errorf("no online replica of %s", addr())
+		}
+		fields := map[string]string{}
+		for _, kv := range strings.Split(v, ",") {
+			if j := strings.Index(kv, "="); j > 0 {
+				fields[kv[:j]] = kv[j+1:]
+			}
+		}
+		if fields["state"] == "online" && fields["ip"] != "" && fields["port"] != "" {
+			return net.JoinHostPort(fields["ip"], fields["port"]), nil
+		}
+	}
+}
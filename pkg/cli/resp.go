@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// respPassthrough copies raw RESP from stdin to a single server connection
+// and the raw replies to stdout. Once stdin is exhausted an ECHO of a
+// random marker is sent, and the copy stops when its reply comes back.
+func respPassthrough() error {
+	if err := checkRawMode("-resp-stdin"); err != nil {
+		return err
+	}
+	c, err := dialRaw(addr())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	r := bufio.NewReader(c)
+
+	if err := rawHandshake(c, r); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 20)
+	rand.Read(buf)
+	marker := hex.EncodeToString(buf)
+	markerReply := []byte(fmt.Sprintf("$%d\r\n%s\r\n", len(marker), marker))
+
+	writeErr := make(chan error, 1)
+	go func() {
+		if _, err := io.Copy(c, os.Stdin); err != nil {
+			writeErr <- err
+			return
+		}
+		_, err := fmt.Fprintf(c, "*2\r\n$4\r\nECHO\r\n$%d\r\n%s\r\n", len(marker), marker)
+		writeErr <- err
+	}()
+
+	// copy replies, holding back enough bytes to spot the marker reply
+	var pending []byte
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		pending = append(pending, chunk[:n]...)
+		if i := bytes.Index(pending, markerReply); i >= 0 {
+			os.Stdout.Write(pending[:i])
+			return <-writeErr
+		}
+		if keep := len(markerReply) - 1; len(pending) > keep {
+			os.Stdout.Write(pending[:len(pending)-keep])
+			pending = pending[len(pending)-keep:]
+		}
+		if err != nil {
+			os.Stdout.Write(pending)
+			if err == io.EOF {
+				return fmt.Errorf("connection closed by server")
+			}
+			return err
+		}
+	}
+}
+
+// dialRaw opens a plain connection to addr, over TLS when -tls is set.
+func dialRaw(addr string) (net.Conn, error) {
+	network := "tcp"
+	if len(*socket) > 0 {
+		network = "unix"
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if *useTLS && network == "tcp" {
+		return tls.DialWithDialer(dialer, network, addr, &tls.Config{})
+	}
+	return dialer.Dial(network, addr)
+}
+
+// rawHandshake authenticates and selects the database on a raw
+// connection, consuming the replies.
+func rawHandshake(c net.Conn, r *bufio.Reader) error {
+	var cmds [][]string
+	if *auth != "" {
+		if *user != "" {
+			cmds = append(cmds, []string{"AUTH", *user, *auth})
+		} else {
+			cmds = append(cmds, []string{"AUTH", *auth})
+		}
+	}
+	if *dbn > 0 {
+		cmds = append(cmds, []string{"SELECT", fmt.Sprint(*dbn)})
+	}
+
+	for _, cmd := range cmds {
+		fmt.Fprintf(c, "*%d\r\n", len(cmd))
+		for _, arg := range cmd {
+			fmt.Fprintf(c, "$%d\r\n%s\r\n", len(arg), arg)
+		}
+		reply, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(reply, "-") {
+			return fmt.Errorf("%s: %s", cmd[0], strings.TrimSpace(reply[1:]))
+		}
+	}
+	return nil
+}
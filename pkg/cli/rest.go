@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// serveREST exposes the connection over HTTP: POST /command with a JSON
+// array of arguments returns the reply as JSON. -allow and -read-only are
+// enforced.
+func serveREST(listen string) error {
+	cliConnect()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/command", handleRESTCommand)
+
+	fmt.Printf("serving %s REST bridge on http://%s/command\n", addr(), listen)
+	return http.ListenAndServe(listen, mux)
+}
+
+type restResponse struct {
+	Reply interface{} `json:"reply"`
+	Text  string      `json:"text,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+func handleRESTCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeREST(w, http.StatusMethodNotAllowed, restResponse{Error: "use POST with a JSON array of arguments"})
+		return
+	}
+
+	var cmds []string
+	if err := json.NewDecoder(r.Body).Decode(&cmds); err != nil || len(cmds) == 0 {
+		writeREST(w, http.StatusBadRequest, restResponse{Error: "body must be a non-empty JSON array of strings"})
+		return
+	}
+
+	if err := checkAllowed(cmds); err != nil {
+		writeREST(w, http.StatusForbidden, restResponse{Error: err.Error()})
+		return
+	}
+
+	args := make([]interface{}, len(cmds))
+	for i, c := range cmds {
+		args[i] = c
+	}
+	reply, err := client.Do(args...).Result()
+	if err == redis.Nil {
+		reply, err = nil, nil
+	}
+	if err != nil {
+		writeREST(w, http.StatusOK, restResponse{Error: err.Error()})
+		return
+	}
+
+	writeREST(w, http.StatusOK, restResponse{
+		Reply: format.JSONValue(reply),
+		Text:  format.Sprint(reply, mode),
+	})
+}
+
+func writeREST(w http.ResponseWriter, status int, resp restResponse) {
+	writeJSON(w, status, resp)
+}
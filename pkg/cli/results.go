@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// resultHistorySize is how many replies stay addressable as $_1, $_2...
+const resultHistorySize = 20
+
+// pastReply is a reply kept for $_ and SHOW.
+type pastReply struct {
+	cmds  []string
+	reply interface{}
+	at    time.Time
+}
+
+// rememberReply records the reply of a command sent to the server in the
+// replies of the active connection, the most recent first.
+func rememberReply(cmds []string, reply interface{}) {
+	s := activeSession()
+	s.replies = append([]pastReply{{cmds: cmds, reply: reply, at: time.Now()}}, s.replies...)
+	if len(s.replies) > resultHistorySize {
+		s.replies = s.replies[:resultHistorySize]
+	}
+}
+
+// replyRef matches $_, $_1, $_2... optionally followed by the 1-based
+// index of an element: $_[3]. The underscore keeps them apart from the
+// $1, $2... arguments of macros.
+var replyRef = regexp.MustCompile(`^\$_([0-9]+)?(?:\[([0-9]+)\])?$`)
+
+// findReply returns the past reply a $ reference names.
+func findReply(ref string) (pastReply, interface{}, error) {
+	m := replyRef.FindStringSubmatch(ref)
+	if m == nil {
+		return pastReply{}, nil, fmt.Errorf("invalid reference %s, should be $_, $_1, $_2... or $_1[index]", ref)
+	}
+	n := 1
+	if m[1] != "" {
+		n, _ = strconv.Atoi(m[1])
+	}
+	replies := activeSession().replies
+	if n < 1 || n > len(replies) {
+		return pastReply{}, nil, fmt.Errorf("no reply %s, see SHOW", ref)
+	}
+	past := replies[n-1]
+	if m[2] == "" {
+		return past, past.reply, nil
+	}
+	i, _ := strconv.Atoi(m[2])
+	arr, ok := past.reply.([]interface{})
+	if !ok || i < 1 || i > len(arr) {
+		return past, nil, fmt.Errorf("%s has no element %d", strings.SplitN(ref, "[", 2)[0], i)
+	}
+	return past, arr[i-1], nil
+}
+
+// expandReplies replaces the $_, $_1... arguments of a command line with
+// the replies they name, an array reply becoming one argument per
+// element, so that DEL $_ deletes the keys the last command returned.
+// Quoted arguments are left alone and \$_1 stands for a literal $_1.
+func expandReplies(cmds []string) ([]string, error) {
+	var expanded []string
+	for i, c := range cmds {
+		if i > 0 && strings.HasPrefix(c, `\$`) && replyRef.MatchString(c[1:]) {
+			expanded = append(expanded, c[1:])
+			continue
+		}
+		if i == 0 || !replyRef.MatchString(c) {
+			expanded = append(expanded, c)
+			continue
+		}
+		_, v, err := findReply(c)
+		if err != nil {
+			return nil, err
+		}
+		switch v := v.(type) {
+		case nil:
+			return nil, fmt.Errorf("%s is nil", c)
+		case []interface{}:
+			for _, e := range v {
+				if _, nested := e.([]interface{}); nested {
+					return nil, fmt.Errorf("%s holds nested arrays, pick an element with %s[index]", c, c)
+				}
+				expanded = append(expanded, quoteArg(fmt.Sprint(e)))
+			}
+		default:
+			expanded = append(expanded, quoteArg(fmt.Sprint(v)))
+		}
+	}
+	return expanded, nil
+}
+
+// quoteArg wraps a value in quotes that lexer.TrimQuotes removes, so the
+// value reaches the server as it is, quotes of its own included.
+func quoteArg(v string) string {
+	return "'" + v + "'"
+}
+
+// showReply prints a past reply again, in another mode or as JSON, or
+// lists the replies kept.
+// Usage: SHOW [$_|$_n [std|raw|yaml|gron|json]]
+func showReply(args []string) {
+	const usage = "(error) invalid args. Should be SHOW [$_|$_n [std|raw|yaml|gron|json]]"
+	if len(args) == 0 {
+		listReplies()
+		return
+	}
+	if len(args) > 2 {
+		fmt.Println(usage)
+		return
+	}
+	past, v, err := findReply(args[0])
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	m := mode
+	if len(args) == 2 {
+		name := strings.ToLower(args[1])
+		if name == "json" {
+			b, err := json.MarshalIndent(format.JSONValue(structureReply(past.cmds, v)), "", "  ")
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			fmt.Println(string(b))
+			return
+		}
+		if !validMode(name) {
+			fmt.Println(usage)
+			return
+		}
+		m = modes[name]
+	}
+	if m != format.Raw && replyTmpl == nil {
+		v = structureReply(past.cmds, v)
+	}
+	printReply(0, v, m)
+	fmt.Println()
+}
+
+// listReplies prints the replies kept, with the commands that returned
+// them.
+func listReplies() {
+	replies := activeSession().replies
+	if len(replies) == 0 {
+		fmt.Println("(empty list), replies of the commands sent to the server are kept")
+		return
+	}
+	rows := make([][]string, len(replies))
+	for i, p := range replies {
+		rows[i] = []string{"$_" + strconv.Itoa(i+1), strings.Join(p.cmds, " "), replySummary(p.reply), p.at.Format("15:04:05")}
+	}
+	printTable([]string{"REF", "COMMAND", "REPLY", "AT"}, rows)
+}
+
+// replySummary describes a reply in a few words.
+func replySummary(reply interface{}) string {
+	switch r := reply.(type) {
+	case nil:
+		return "(nil)"
+	case []interface{}:
+		return fmt.Sprintf("array of %d", len(r))
+	case int64:
+		return fmt.Sprintf("(integer) %d", r)
+	case string:
+		if len(r) > 40 {
+			return strconv.Quote(r[:40]) + "..."
+		}
+		return strconv.Quote(r)
+	}
+	return fmt.Sprint(reply)
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+func TestExpandReplies(t *testing.T) {
+	defer func(saved map[string]*session) { sessions = saved }(sessions)
+	sessions = map[string]*session{}
+	rememberReply([]string{"lrange", "l", "0", "-1"}, []interface{}{"a", `"b"`})
+	rememberReply([]string{"get", "k"}, `"hi"`)
+
+	tests := []struct {
+		line string
+		want []string
+	}{
+		// a quoted $_5 is a value, not a reference
+		{`SET k '$_5'`, []string{"SET", "k", "$_5"}},
+		{`SET k "$_"`, []string{"SET", "k", "$_"}},
+		{`SET k \$_5`, []string{"SET", "k", "$_5"}},
+		{`SET k $_5x`, []string{"SET", "k", "$_5x"}},
+		// $1 is a macro argument, not a reply
+		{`SET k $1`, []string{"SET", "k", "$1"}},
+		// expanded values keep their own quotes
+		{`SET k $_`, []string{"SET", "k", `"hi"`}},
+		{`SET k $_1`, []string{"SET", "k", `"hi"`}},
+		{`RPUSH l $_2`, []string{"RPUSH", "l", "a", `"b"`}},
+		{`SET k $_2[2]`, []string{"SET", "k", `"b"`}},
+	}
+	for _, tt := range tests {
+		got, err := expandReplies(lexer.Split(tt.line))
+		if err != nil {
+			t.Errorf("%s: %v", tt.line, err)
+			continue
+		}
+		for i := range got {
+			got[i] = lexer.TrimQuotes(got[i])
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.line, got, tt.want)
+		}
+	}
+
+	if _, err := expandReplies([]string{"SET", "k", "$_5"}); err == nil {
+		t.Errorf("SET k $_5: want an error for a missing reply")
+	}
+}
+
+func TestRepliesPerSession(t *testing.T) {
+	defer func(saved map[string]*session, name string) {
+		sessions, sessionName = saved, name
+	}(sessions, sessionName)
+	sessions = map[string]*session{}
+
+	sessionName = "a"
+	rememberReply([]string{"get", "k"}, "from a")
+	sessionName = "b"
+	if _, err := expandReplies([]string{"SET", "k", "$_"}); err == nil {
+		t.Errorf("b sees the replies of a")
+	}
+	rememberReply([]string{"get", "k"}, "from b")
+
+	sessionName = "a"
+	got, err := expandReplies([]string{"SET", "k", "$_"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[2] != "'from a'" {
+		t.Errorf("a: $_ is %s, want 'from a'", got[2])
+	}
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// sampleKeys prints n random keys with their type, size and TTL. Without
+// filters it uses RANDOMKEY, otherwise reservoir sampling over a full SCAN.
+// Usage: SAMPLE [n] [--type t] [--pattern p]
+func sampleKeys(args []string) {
+	const usage = "(error) invalid args. Should be SAMPLE [n] [--type t] [--pattern p]"
+	n := 10
+	var onlyType, pattern string
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		if (opt == "--type" || opt == "--pattern") && i+1 < len(args) {
+			if opt == "--type" {
+				onlyType = strings.ToLower(lexer.TrimQuotes(args[i+1]))
+			} else {
+				pattern = lexer.TrimQuotes(args[i+1])
+			}
+			i++
+		} else if v, err := strconv.Atoi(args[i]); err == nil && v > 0 {
+			n = v
+		} else {
+			fmt.Println(usage)
+			return
+		}
+	}
+	cliConnect()
+
+	var keys []string
+	var err error
+	if onlyType == "" && pattern == "" {
+		keys, err = randomKeys(n)
+	} else {
+		keys, err = reservoirKeys(n, pattern, onlyType)
+	}
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if len(keys) == 0 {
+		fmt.Println("(no keys)")
+		return
+	}
+	sort.Strings(keys)
+
+	var rows [][]string
+	for _, k := range keys {
+		typ, err := client.Type(k).Result()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		ttl, _ := client.TTL(k).Result()
+		ttlText := "-"
+		if ttl >= 0 {
+			ttlText = ttl.String()
+		}
+		rows = append(rows, []string{k, typ, keySize(k, typ), ttlText})
+	}
+	printTable([]string{"KEY", "TYPE", "SIZE", "TTL"}, rows)
+}
+
+// randomKeys returns up to n distinct keys from RANDOMKEY, giving up after
+// a few tries without a new key on small databases.
+func randomKeys(n int) ([]string, error) {
+	seen := map[string]bool{}
+	var keys []string
+	for misses := 0; len(keys) < n && misses < 3*n; {
+		k, err := client.RandomKey().Result()
+		if err == redis.Nil {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if seen[k] {
+			misses++
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// reservoirKeys scans every key matching pattern and keeps a uniform
+// sample of n of those of type typ, when given.
+func reservoirKeys(n int, pattern, typ string) ([]string, error) {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var keys []string
+	seen := 0
+	err := scanKeys(pattern, 1000, func(batch []string) error {
+		if typ != "" && len(batch) > 0 {
+			pipe := client.Pipeline()
+			defer pipe.Close()
+			types := make([]*redis.StatusCmd, len(batch))
+			for i, k := range batch {
+				types[i] = pipe.Type(k)
+			}
+			pipe.Exec()
+
+			filtered := batch[:0]
+			for i, k := range batch {
+				if types[i].Val() == typ {
+					filtered = append(filtered, k)
+				}
+			}
+			batch = filtered
+		}
+
+		for _, k := range batch {
+			seen++
+			if len(keys) < n {
+				keys = append(keys, k)
+			} else if j := r.Intn(seen); j < n {
+				keys[j] = k
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// keySize returns the length of a string or the number of elements of a
+// collection.
+func keySize(key, typ string) string {
+	var n int64
+	var err error
+	switch typ {
+	case "string":
+		n, err = client.StrLen(key).Result()
+		if err == nil {
+			return fmt.Sprintf("%d bytes", n)
+		}
+	case "hash":
+		n, err = client.HLen(key).Result()
+	case "list":
+		n, err = client.LLen(key).Result()
+	case "set":
+		n, err = client.SCard(key).Result()
+	case "zset":
+		n, err = client.ZCard(key).Result()
+	case "stream":
+		n, err = client.XLen(key).Result()
+	default:
+		return "-"
+	}
+	if err != nil {
+		return "?"
+	}
+	return fmt.Sprintf("%d elements", n)
+}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/info"
+)
+
+// saveJob is a background job SAVE-WATCH follows: its INFO persistence
+// fields and the command starting it. The done fields change when a job
+// ends, however quickly.
+type saveJob struct {
+	name     string
+	command  string
+	running  string
+	status   string
+	duration string
+	elapsed  string
+	done     []string
+}
+
+var saveJobs = []saveJob{
+	{"bgsave", "BGSAVE", "rdb_bgsave_in_progress", "rdb_last_bgsave_status", "rdb_last_bgsave_time_sec", "rdb_current_bgsave_time_sec",
+		[]string{"rdb_last_save_time", "rdb_saves", "rdb_last_bgsave_status", "rdb_last_bgsave_time_sec"}},
+	{"aof rewrite", "BGREWRITEAOF", "aof_rewrite_in_progress", "aof_last_bgrewrite_status", "aof_last_rewrite_time_sec", "aof_current_rewrite_time_sec",
+		[]string{"aof_rewrites", "aof_last_bgrewrite_status", "aof_last_rewrite_time_sec"}},
+}
+
+// doneStamp returns the done fields of job, to tell when it has ended.
+func (job *saveJob) doneStamp(p info.Reply) string {
+	values := make([]string, len(job.done))
+	for i, f := range job.done {
+		values[i] = p.Get(f)
+	}
+	return strings.Join(values, " ")
+}
+
+// saveWatch starts BGSAVE or BGREWRITEAOF, or follows the one running,
+// printing a progress line from INFO until it completes.
+// Usage: SAVE-WATCH [BGSAVE|BGREWRITEAOF] [--interval s]
+func saveWatch(args []string) {
+	const usage = "(error) invalid args. Should be SAVE-WATCH [BGSAVE|BGREWRITEAOF] [--interval s]"
+	start := ""
+	interval := 500 * time.Millisecond
+	for i := 0; i < len(args); i++ {
+		opt := strings.ToLower(args[i])
+		switch {
+		case opt == "--interval" && i+1 < len(args):
+			secs, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || secs <= 0 {
+				fmt.Println(usage)
+				return
+			}
+			interval = time.Duration(secs * float64(time.Second))
+			i++
+		case (opt == "bgsave" || opt == "bgrewriteaof") && start == "":
+			start = strings.ToUpper(opt)
+		default:
+			fmt.Println(usage)
+			return
+		}
+	}
+	cliConnect()
+
+	var job *saveJob
+	before := ""
+	if start != "" {
+		for i := range saveJobs {
+			if saveJobs[i].command == start {
+				job = &saveJobs[i]
+			}
+		}
+		p, err := fetchInfo()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		before = job.doneStamp(p)
+
+		// the same job already running is followed rather than reported
+		// as an error, BGSAVE refused for an AOF rewrite in progress isn't
+		reply, err := client.Do(start).String()
+		if err != nil && (!strings.Contains(err.Error(), "in progress") || p.Get(job.running) != "1") {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		if err != nil {
+			fmt.Printf("%s already running\n", job.name)
+		} else {
+			fmt.Println(reply)
+		}
+	}
+
+	interrupt, stop := interrupted()
+	defer stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	started := false
+	for {
+		p, err := fetchInfo()
+		if err != nil {
+			fmt.Printf("\r\033[K(error) %s\n", err.Error())
+			return
+		}
+		if job == nil {
+			for i := range saveJobs {
+				if p.Get(saveJobs[i].running) == "1" {
+					job = &saveJobs[i]
+				}
+			}
+			if job == nil {
+				fmt.Println("no BGSAVE or AOF rewrite running, start one with SAVE-WATCH BGSAVE|BGREWRITEAOF")
+				return
+			}
+		}
+
+		running := p.Get(job.running) == "1"
+		switch {
+		case running:
+			started = true
+			fmt.Printf("\r\033[K%s", saveProgress(job, p))
+		case started || start == "" || job.doneStamp(p) != before:
+			fmt.Printf("\r\033[K%s\n", saveResult(job, p))
+			return
+		default:
+			// BGSAVE ... SCHEDULE and BGREWRITEAOF wait for the running fork
+			fmt.Printf("\r\033[K%s scheduled, waiting for the running fork to end", job.name)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-interrupt:
+			fmt.Printf("\r\033[K%s keeps running on the server, see PERSISTENCE\n", job.name)
+			return
+		}
+	}
+}
+
+// saveProgress formats the progress line of a running job.
+func saveProgress(job *saveJob, p info.Reply) string {
+	line := fmt.Sprintf("%s running for %ss", job.name, p.Get(job.elapsed))
+	if perc := p.Get("current_fork_perc"); perc != "" && strings.Trim(perc, "0.") != "" {
+		line += fmt.Sprintf(", %s%% (%d of %d keys)", perc, p.Int("current_save_keys_processed"), p.Int("current_save_keys_total"))
+	}
+	if job.command == "BGREWRITEAOF" && p.Get("aof_current_size") != "" {
+		line += fmt.Sprintf(", aof %s", humanBytes(p.Int("aof_current_size")))
+	}
+	if cow := p.Int("current_cow_size"); cow > 0 {
+		line += fmt.Sprintf(", copy-on-write %s", humanBytes(cow))
+	}
+	if fork := p.Int("latest_fork_usec"); fork > 0 {
+		line += fmt.Sprintf(", fork took %s", time.Duration(fork)*time.Microsecond)
+	}
+	return line
+}
+
+// saveResult formats how a job ended.
+func saveResult(job *saveJob, p info.Reply) string {
+	status := p.Get(job.status)
+	line := fmt.Sprintf("%s %s, took %ss", job.name, status, p.Get(job.duration))
+	if fork := p.Int("latest_fork_usec"); fork > 0 {
+		line += fmt.Sprintf(", fork took %s", time.Duration(fork)*time.Microsecond)
+	}
+	if status == "err" {
+		line = highlightStart + line + ", see the server log" + highlightEnd
+	}
+	return line
+}
@@ -0,0 +1,227 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// scanNode is what a SCAN-like command is sent to: the client, or a single
+// master of the cluster.
+type scanNode interface {
+	Do(args ...interface{}) *redis.Cmd
+}
+
+// scanCursorIndex returns the position of the cursor argument of the
+// SCAN family of commands, or -1 when cmd is not cursor based.
+func scanCursorIndex(cmd string) int {
+	switch cmd {
+	case "scan":
+		return 1
+	case "hscan", "sscan", "zscan":
+		return 2
+	}
+	return -1
+}
+
+// scanCursor extracts the next cursor from a SCAN-like reply.
+func scanCursor(reply interface{}) string {
+	arr, ok := reply.([]interface{})
+	if !ok || len(arr) != 2 {
+		return "0"
+	}
+	cursor, ok := arr[0].(string)
+	if !ok {
+		return "0"
+	}
+	return cursor
+}
+
+// scanNodes returns the nodes cmd iterates. In cluster mode SCAN runs on
+// every master in turn, as each holds its own keys and only understands
+// the cursors it returned; HSCAN and the like follow their key.
+func scanNodes(cmd string) ([]scanNode, error) {
+	if cmd != "scan" || !clusterEnabled {
+		return []scanNode{client}, nil
+	}
+	var mu sync.Mutex
+	var masters []*redis.Client
+	err := client.ForEachMaster(func(c *redis.Client) error {
+		mu.Lock()
+		masters = append(masters, c)
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(masters, func(i, j int) bool {
+		return masters[i].Options().Addr < masters[j].Options().Addr
+	})
+	nodes := make([]scanNode, len(masters))
+	for i, m := range masters {
+		nodes[i] = m
+	}
+	return nodes, nil
+}
+
+// commandScanNodes returns the nodes cmd iterates when it is routed to
+// node, the one it was forced to, or else those of scanNodes.
+func commandScanNodes(cmd string, node string) ([]scanNode, error) {
+	if node != "" {
+		return []scanNode{nodeClient(node)}, nil
+	}
+	return scanNodes(cmd)
+}
+
+// scanNodeAddr returns the address of a master returned by scanNodes, or
+// "" for the client.
+func scanNodeAddr(n scanNode) string {
+	if c, ok := n.(*redis.Client); ok {
+		return c.Options().Addr
+	}
+	return ""
+}
+
+// scanMore pages through a SCAN-like command by reissuing it with the
+// cursor from the previous reply, until the user stops or the iteration
+// is complete. reply came from nodes[0]; the remaining nodes are iterated
+// from cursor 0 after it.
+func scanMore(nodes []scanNode, args []interface{}, idx int, reply interface{}) {
+	all := false
+	for len(nodes) > 0 {
+		cursor := scanCursor(reply)
+		if cursor == "0" {
+			nodes = nodes[1:]
+			if len(nodes) == 0 {
+				return
+			}
+		}
+
+		if !all {
+			answer, err := line.Prompt("-- More (y/n/a) -- ")
+			if err != nil {
+				return
+			}
+			switch strings.ToLower(strings.TrimSpace(answer)) {
+			case "", "y", "yes":
+			case "a", "all":
+				all = true
+			default:
+				return
+			}
+		}
+
+		if cursor == "0" {
+			fmt.Printf("%s%s%s\n", highlightStart, scanNodeAddr(nodes[0]), highlightEnd)
+		}
+		args[idx] = cursor
+		r, err := nodes[0].Do(args...).Result()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		printReply(0, r, mode)
+		fmt.Printf("\n")
+		reply = r
+	}
+}
+
+// scanKeys iterates the keyspace with SCAN, calling fn with every batch of
+// keys matching pattern. Iteration stops early when fn returns an error.
+func scanKeys(pattern string, count int, fn func(keys []string) error) error {
+	nodes, err := scanNodes("scan")
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if err := scanNodeKeys(n, pattern, count, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanNodeKeys iterates the keys of a single node as scanKeys does.
+func scanNodeKeys(n scanNode, pattern string, count int, fn func(keys []string) error) error {
+	cursor := "0"
+	for {
+		args := []interface{}{"SCAN", cursor}
+		if pattern != "" {
+			args = append(args, "MATCH", pattern)
+		}
+		if count > 0 {
+			args = append(args, "COUNT", count)
+		}
+
+		r, err := n.Do(args...).Result()
+		if err != nil {
+			return err
+		}
+		arr, ok := r.([]interface{})
+		if !ok || len(arr) != 2 {
+			return fmt.Errorf("unexpected SCAN reply: %v", r)
+		}
+
+		items, _ := arr[1].([]interface{})
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			if k, ok := item.(string); ok {
+				keys = append(keys, k)
+			}
+		}
+		if err := fn(keys); err != nil {
+			return err
+		}
+
+		cursor = scanCursor(r)
+		if cursor == "0" {
+			return nil
+		}
+	}
+}
+
+// scanStream runs a SCAN-like command routed to node to completion,
+// emitting every element it returns as an event.
+func scanStream(cmd string, node string, args []interface{}, idx int) {
+	nodes, err := commandScanNodes(cmd, node)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	start := args[idx]
+	for _, n := range nodes {
+		from := scanNodeAddr(n)
+		if from == "" {
+			from = addr()
+		}
+		args[idx] = start
+		for {
+			r, err := n.Do(args...).Result()
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			arr, ok := r.([]interface{})
+			if !ok || len(arr) != 2 {
+				fmt.Printf("(error) unexpected %s reply: %v\n", strings.ToUpper(cmd), r)
+				return
+			}
+			items, _ := arr[1].([]interface{})
+			for _, item := range items {
+				emitEvent(streamEvent{Time: time.Now(), Node: from, Source: cmd, Payload: item}, fmt.Sprint(item))
+			}
+
+			cursor := scanCursor(r)
+			if cursor == "0" {
+				break
+			}
+			args[idx] = cursor
+		}
+		start = "0"
+	}
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/holys/redis-cli/pkg/lexer"
+	"go.starlark.net/starlark"
+)
+
+// runScript runs a client-side Starlark script.
+// Usage: SCRIPT RUN file [arg ...]
+//
+// Besides the Starlark builtins, scripts get:
+//
+//	ARGV                         the extra arguments, as strings
+//	call(cmd, *args)             run a command, errors abort the script
+//	scan(match="", count=1000)   all the keys matching a pattern
+//	fmt(reply)                   a reply rendered in the current mode
+func runScript(args []string) {
+	if len(args) < 1 {
+		fmt.Println("(error) invalid args. Should be SCRIPT RUN file [arg ...]")
+		return
+	}
+	cliConnect()
+
+	file := lexer.TrimQuotes(args[0])
+	argv := make([]starlark.Value, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		argv = append(argv, starlark.String(lexer.TrimQuotes(arg)))
+	}
+
+	predeclared := starlark.StringDict{
+		"ARGV": starlark.NewList(argv),
+		"call": starlark.NewBuiltin("call", scriptCall),
+		"scan": starlark.NewBuiltin("scan", scriptScan),
+		"fmt":  starlark.NewBuiltin("fmt", scriptFmt),
+	}
+	thread := &starlark.Thread{
+		Name: file,
+		Print: func(_ *starlark.Thread, msg string) {
+			fmt.Println(msg)
+		},
+	}
+
+	if _, err := starlark.ExecFile(thread, file, nil, predeclared); err != nil {
+		if evalErr, ok := err.(*starlark.EvalError); ok {
+			fmt.Printf("(error) %s\n", evalErr.Backtrace())
+		} else {
+			fmt.Printf("(error) %s\n", err.Error())
+		}
+	}
+}
+
+func scriptCall(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if len(kwargs) > 0 {
+		return nil, fmt.Errorf("%s: unexpected keyword arguments", b.Name())
+	}
+	if len(args) == 0 {
+		return nil, fmt.Errorf("%s: missing command", b.Name())
+	}
+
+	cmd := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case starlark.String:
+			cmd[i] = string(arg)
+		case starlark.Int:
+			n, _ := arg.Int64()
+			cmd[i] = n
+		default:
+			cmd[i] = arg.String()
+		}
+	}
+
+	r, err := client.Do(cmd...).Result()
+	if err != nil {
+		return nil, err
+	}
+	return toStarlark(r), nil
+}
+
+func scriptScan(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	match := ""
+	count := 1000
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "match?", &match, "count?", &count); err != nil {
+		return nil, err
+	}
+
+	var keys []starlark.Value
+	err := scanKeys(match, count, func(batch []string) error {
+		for _, k := range batch {
+			keys = append(keys, starlark.String(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return starlark.NewList(keys), nil
+}
+
+func scriptFmt(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var v starlark.Value
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &v); err != nil {
+		return nil, err
+	}
+	return starlark.String(format.Sprint(fromStarlark(v), mode)), nil
+}
+
+// toStarlark converts a reply to a Starlark value.
+func toStarlark(reply interface{}) starlark.Value {
+	switch reply := reply.(type) {
+	case int64:
+		return starlark.MakeInt64(reply)
+	case string:
+		return starlark.String(reply)
+	case []byte:
+		return starlark.String(reply)
+	case []interface{}:
+		list := make([]starlark.Value, len(reply))
+		for i, v := range reply {
+			list[i] = toStarlark(v)
+		}
+		return starlark.NewList(list)
+	case nil:
+		return starlark.None
+	default:
+		return starlark.String(fmt.Sprint(reply))
+	}
+}
+
+// fromStarlark converts a Starlark value back to a reply.
+func fromStarlark(v starlark.Value) interface{} {
+	switch v := v.(type) {
+	case starlark.String:
+		return string(v)
+	case starlark.Int:
+		n, _ := v.Int64()
+		return n
+	case starlark.NoneType:
+		return nil
+	case *starlark.List:
+		reply := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			reply[i] = fromStarlark(v.Index(i))
+		}
+		return reply
+	case starlark.Tuple:
+		reply := make([]interface{}, len(v))
+		for i, e := range v {
+			reply[i] = fromStarlark(e)
+		}
+		return reply
+	default:
+		return v.String()
+	}
+}
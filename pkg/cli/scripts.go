@@ -0,0 +1,232 @@
+package cli
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// scriptDir holds Lua scripts known by name: name.lua can be run with
+// EVALSHA-BY-NAME name.
+var scriptDir = path.Join(os.Getenv("HOME"), ".gorediscli_scripts") // $HOME/.gorediscli_scripts
+
+// knownScript is a Lua script whose source the CLI knows, so it can load
+// it again when the server lost it.
+type knownScript struct {
+	name   string
+	sha    string
+	origin string
+	body   string
+}
+
+// sessionScripts are the scripts sent with SCRIPT LOAD or EVAL during the
+// session, by SHA1.
+var sessionScripts = map[string]*knownScript{}
+
+// scriptSHA returns the SHA1 the server knows body by.
+func scriptSHA(body string) string {
+	sum := sha1.Sum([]byte(body))
+	return hex.EncodeToString(sum[:])
+}
+
+// rememberScript records the script of a successful SCRIPT LOAD or EVAL,
+// named after the file it was read from with --script.
+func rememberScript(cmd string, args []string, file string) {
+	var body string
+	switch {
+	case cmd == "script" && len(args) == 3 && strings.ToLower(args[1]) == "load":
+		body = args[2]
+	case (cmd == "eval" || cmd == "eval_ro") && len(args) > 1:
+		body = args[1]
+	default:
+		return
+	}
+	sha := scriptSHA(body)
+	if _, ok := sessionScripts[sha]; ok {
+		return
+	}
+	name := ""
+	if file != "" {
+		name = strings.TrimSuffix(path.Base(file), path.Ext(file))
+	}
+	sessionScripts[sha] = &knownScript{name: name, sha: sha, origin: "session", body: body}
+}
+
+// dirScripts reads the *.lua files of dir.
+func dirScripts(dir string) ([]*knownScript, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var scripts []*knownScript
+	for _, fi := range files {
+		if fi.IsDir() || path.Ext(fi.Name()) != ".lua" {
+			continue
+		}
+		file := path.Join(dir, fi.Name())
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		scripts = append(scripts, &knownScript{
+			name:   strings.TrimSuffix(fi.Name(), ".lua"),
+			sha:    scriptSHA(string(b)),
+			origin: file,
+			body:   string(b),
+		})
+	}
+	return scripts, nil
+}
+
+// knownScripts returns the scripts of scriptDir and of the session, by
+// name then SHA1.
+func knownScripts() []*knownScript {
+	scripts, _ := dirScripts(scriptDir)
+	seen := map[string]bool{}
+	for _, s := range scripts {
+		seen[s.sha] = true
+	}
+	for _, s := range sessionScripts {
+		if !seen[s.sha] {
+			scripts = append(scripts, s)
+		}
+	}
+	sort.Slice(scripts, func(i, j int) bool {
+		if scripts[i].name != scripts[j].name {
+			return scripts[i].name < scripts[j].name
+		}
+		return scripts[i].sha < scripts[j].sha
+	})
+	return scripts
+}
+
+// findScript returns the known script called name.
+func findScript(name string) *knownScript {
+	for _, s := range knownScripts() {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// scriptLoaded reports whether every master has sha in its script cache.
+func scriptLoaded(sha string) (bool, error) {
+	var mu sync.Mutex
+	loaded := true
+	err := client.ForEachMaster(func(c *redis.Client) error {
+		exists, err := c.ScriptExists(sha).Result()
+		if err != nil {
+			return err
+		}
+		if len(exists) == 0 || !exists[0] {
+			mu.Lock()
+			loaded = false
+			mu.Unlock()
+		}
+		return nil
+	})
+	return loaded, err
+}
+
+// loadScript loads body into the script cache of every master.
+func loadScript(body string) error {
+	return client.ForEachMaster(func(c *redis.Client) error {
+		return c.ScriptLoad(body).Err()
+	})
+}
+
+// ensureScript checks with SCRIPT EXISTS that sha is loaded before an
+// EVALSHA, loading it again when its source is known.
+func ensureScript(sha string) error {
+	loaded, err := scriptLoaded(sha)
+	if err != nil || loaded {
+		// leave errors to the EVALSHA itself
+		return nil
+	}
+	var known *knownScript
+	for _, s := range knownScripts() {
+		if s.sha == strings.ToLower(sha) {
+			known = s
+			break
+		}
+	}
+	if known == nil {
+		return fmt.Errorf("NOSCRIPT %s is not in the script cache and its source is unknown, load it with SCRIPT LOAD", sha)
+	}
+	if err := loadScript(known.body); err != nil {
+		return err
+	}
+	label := known.name
+	if label == "" {
+		label = known.sha
+	}
+	fmt.Printf("(loaded %s into the script cache)\n", label)
+	return nil
+}
+
+// scriptsCommand lists the known scripts, or syncs a directory of them.
+// Usage: SCRIPTS | SCRIPTS SYNC dir [--dry-run]
+func scriptsCommand(args []string) {
+	if len(args) > 0 && strings.ToLower(args[0]) == "sync" {
+		syncScripts(args[1:])
+		return
+	}
+	if len(args) != 0 {
+		fmt.Println("(error) invalid args. Should be SCRIPTS | SCRIPTS SYNC dir [--dry-run]")
+		return
+	}
+	listScripts()
+}
+
+// listScripts prints the known scripts and whether the server has them.
+func listScripts() {
+	cliConnect()
+
+	scripts := knownScripts()
+	if len(scripts) == 0 {
+		fmt.Printf("(empty list), load scripts with SCRIPT LOAD or add them to %s\n", scriptDir)
+		return
+	}
+	rows := make([][]string, len(scripts))
+	for i, s := range scripts {
+		name := s.name
+		if name == "" {
+			name = "-"
+		}
+		loaded := "no"
+		if ok, err := scriptLoaded(s.sha); err != nil {
+			loaded = "?"
+		} else if ok {
+			loaded = "yes"
+		}
+		rows[i] = []string{name, s.sha, loaded, s.origin}
+	}
+	printTable([]string{"NAME", "SHA1", "LOADED", "ORIGIN"}, rows)
+}
+
+// evalshaByName runs a known script by name, loading it first when the
+// server doesn't have it.
+// Usage: EVALSHA-BY-NAME name numkeys [key ...] [arg ...]
+func evalshaByName(args []string) {
+	if len(args) < 1 {
+		fmt.Println("(error) invalid args. Should be EVALSHA-BY-NAME name numkeys [key ...] [arg ...]")
+		return
+	}
+	name := lexer.TrimQuotes(args[0])
+	s := findScript(name)
+	if s == nil {
+		fmt.Printf("(error) no script named %s, see SCRIPTS\n", name)
+		return
+	}
+	cliSendCommand(append([]string{"evalsha", s.sha}, args[1:]...)...)
+}
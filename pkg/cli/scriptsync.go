@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// libraryHeader is the first line of a function library, which goes
+// through FUNCTION LOAD instead of SCRIPT LOAD.
+var libraryHeader = regexp.MustCompile(`^#!lua\s+name=(\S+)`)
+
+// syncScripts loads every *.lua file of a directory: function libraries,
+// starting with #!lua name=lib, with FUNCTION LOAD REPLACE and plain
+// scripts with SCRIPT LOAD. It reports what changed, and the libraries
+// the server has that the directory doesn't. --dry-run only reports.
+// Usage: SCRIPTS SYNC dir [--dry-run]
+func syncScripts(args []string) {
+	dryRun := false
+	if len(args) == 2 && strings.ToLower(args[1]) == "--dry-run" {
+		dryRun = true
+		args = args[:1]
+	}
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be SCRIPTS SYNC dir [--dry-run]")
+		return
+	}
+	dir := lexer.TrimQuotes(args[0])
+	scripts, err := dirScripts(dir)
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if len(scripts) == 0 {
+		fmt.Printf("(error) no .lua files in %s\n", dir)
+		return
+	}
+	cliConnect()
+
+	var (
+		libraries map[string]string
+		libErr    error
+		rows      [][]string
+	)
+	for _, s := range scripts {
+		file := path.Base(s.origin)
+		if m := libraryHeader.FindStringSubmatch(s.body); m != nil {
+			if libraries == nil && libErr == nil {
+				libraries, libErr = functionLibraries()
+			}
+			status := "error: " + fmt.Sprint(libErr)
+			if libErr == nil {
+				status = syncLibrary(m[1], s.body, libraries, dryRun)
+			}
+			rows = append(rows, []string{file, "function", m[1], status})
+			delete(libraries, m[1])
+			continue
+		}
+
+		status := syncScript(s, dryRun)
+		rows = append(rows, []string{file, "script", s.sha, status})
+	}
+	// libraries loaded on the server but not in the directory
+	var extra []string
+	for name := range libraries {
+		extra = append(extra, name)
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		rows = append(rows, []string{"-", "function", name, "only on the server"})
+	}
+	printTable([]string{"FILE", "TYPE", "SHA1/LIBRARY", "STATUS"}, rows)
+}
+
+// syncScript loads a plain script unless the server has it already.
+func syncScript(s *knownScript, dryRun bool) string {
+	// scripts are cached by SHA1, so a changed file has a new one
+	changed := false
+	for _, known := range sessionScripts {
+		if known.name == s.name && known.sha != s.sha {
+			changed = true
+		}
+	}
+	loaded, err := scriptLoaded(s.sha)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+
+	status := "unchanged"
+	switch {
+	case loaded:
+	case dryRun && changed:
+		status = "changed, not loaded"
+	case dryRun:
+		status = "not loaded"
+	default:
+		if err := loadScript(s.body); err != nil {
+			return "error: " + err.Error()
+		}
+		status = "loaded"
+		if changed {
+			status = "updated"
+		}
+	}
+	if !dryRun || loaded {
+		// EVALSHA-BY-NAME runs the new version from now on
+		for sha, known := range sessionScripts {
+			if known.name == s.name {
+				delete(sessionScripts, sha)
+			}
+		}
+		sessionScripts[s.sha] = s
+	}
+	return status
+}
+
+// syncLibrary loads a function library unless the server has the same
+// code.
+func syncLibrary(name, code string, libraries map[string]string, dryRun bool) string {
+	current, ok := libraries[name]
+	switch {
+	case ok && strings.TrimSpace(current) == strings.TrimSpace(code):
+		return "unchanged"
+	case dryRun && ok:
+		return "changed, not loaded"
+	case dryRun:
+		return "not loaded"
+	}
+
+	err := client.ForEachMaster(func(c *redis.Client) error {
+		return c.Do("FUNCTION", "LOAD", "REPLACE", code).Err()
+	})
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	if ok {
+		return "updated"
+	}
+	return "loaded"
+}
+
+// functionLibraries returns the code of the function libraries of the
+// server by name, nothing before Redis 7.0.
+func functionLibraries() (map[string]string, error) {
+	libraries := map[string]string{}
+	if serverVersion != "" && compareVersions(serverVersion, "7.0.0") < 0 {
+		return libraries, fmt.Errorf("functions need Redis 7.0, the server runs %s", serverVersion)
+	}
+	r, err := client.Do("FUNCTION", "LIST", "WITHCODE").Result()
+	if err != nil {
+		return nil, err
+	}
+	items, _ := r.([]interface{})
+	for _, item := range items {
+		fields := replyFields(item)
+		if name, ok := fields["library_name"].(string); ok {
+			code, _ := fields["library_code"].(string)
+			libraries[name] = code
+		}
+	}
+	return libraries, nil
+}
+
+// replyFields turns a flat field/value array reply into a map.
+func replyFields(reply interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	arr, _ := reply.([]interface{})
+	for i := 0; i+1 < len(arr); i += 2 {
+		if k, ok := arr[i].(string); ok {
+			fields[k] = arr[i+1]
+		}
+	}
+	return fields
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// selectDB handles SELECT on the client side. SELECT sent through the pool
+// would only switch the connection it went out on, so a client selecting
+// the database on every connection replaces the current one, and the
+// prompt changes once the server accepted the database.
+// Usage: SELECT index
+func selectDB(args []string) {
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be SELECT index")
+		return
+	}
+	n, err := strconv.Atoi(lexer.TrimQuotes(args[0]))
+	if err != nil || n < 0 {
+		fmt.Println("(error) ERR invalid DB index")
+		return
+	}
+	cliConnect()
+
+	if srv, err := fetchInfo("cluster"); err == nil && srv.Get("cluster_enabled") == "1" {
+		fmt.Println("(error) SELECT is not available in cluster mode, a cluster only has database 0")
+		return
+	}
+
+	opt := clientOptions(addr(), *auth)
+	opt.DB = n
+	c := conn.New(opt)
+	if err := c.Ping().Err(); err != nil {
+		c.Close()
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	client.Close()
+	client = c
+	*dbn = n
+	fmt.Println("OK")
+}
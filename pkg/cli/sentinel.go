@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// sentinelEvents are the Sentinel channels followed by SENTINEL WATCH.
+var sentinelEvents = []string{"+switch-master", "+failover-state-select-slave", "+promoted-slave", "+sdown", "-sdown", "+odown", "-odown"}
+
+// sentinelCommand runs a SENTINEL command. Sentinels are not cluster
+// nodes, so they are talked to with a single node client.
+//
+//	SENTINEL MASTERS               table of the monitored masters
+//	SENTINEL REPLICAS master       table of the replicas of a master
+//	SENTINEL FAILOVER master       force a failover, after confirmation
+//	SENTINEL WATCH                 follow failover events until Ctrl-C
+//
+// Other subcommands are sent as is.
+func sentinelCommand(cmds []string) {
+	c := conn.NewSingle(connOptions(addr(), *auth))
+	defer c.Close()
+
+	args := make([]string, len(cmds))
+	for i, arg := range cmds {
+		args[i] = lexer.TrimQuotes(arg)
+	}
+
+	sub := ""
+	if len(args) > 1 {
+		sub = strings.ToLower(args[1])
+	}
+	switch {
+	case sub == "masters" && len(args) == 2:
+		sentinelTable(c, []string{"name", "ip", "port", "flags", "num-slaves", "num-other-sentinels", "quorum"}, "SENTINEL", "MASTERS")
+	case (sub == "replicas" || sub == "slaves") && len(args) == 3:
+		sentinelTable(c, []string{"name", "ip", "port", "flags", "master-link-status", "slave-repl-offset"}, "SENTINEL", "REPLICAS", args[2])
+	case sub == "failover" && len(args) == 3:
+		if !confirm(fmt.Sprintf("Force a failover of %s?", args[2])) {
+			fmt.Println("aborted")
+			return
+		}
+		sentinelSend(c, args)
+	case sub == "watch" && len(args) == 2:
+		sentinelWatch(c)
+	default:
+		sentinelSend(c, args)
+	}
+}
+
+func sentinelSend(c *redis.Client, args []string) {
+	cmd := make([]interface{}, len(args))
+	for i, arg := range args {
+		cmd[i] = arg
+	}
+	r, err := c.Do(cmd...).Result()
+	if err != nil && err != redis.Nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	format.Fprint(os.Stdout, 0, r, mode)
+	fmt.Printf("\n")
+}
+
+// sentinelTable prints the given fields of every entry of a SENTINEL
+// reply made of field/value arrays.
+func sentinelTable(c *redis.Client, fields []string, args ...interface{}) {
+	r, err := c.Do(args...).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	entries, _ := r.([]interface{})
+	if len(entries) == 0 {
+		fmt.Println("(empty list or set)")
+		return
+	}
+	var rows [][]string
+	for _, e := range entries {
+		pairs := replyPairs(e)
+		row := make([]string, len(fields))
+		for i, f := range fields {
+			row[i] = pairs[f]
+		}
+		rows = append(rows, row)
+	}
+	printTable(fields, rows)
+}
+
+// sentinelWatch prints failover related events until interrupted.
+func sentinelWatch(c *redis.Client) {
+	pubsub := c.Subscribe(sentinelEvents...)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	fmt.Println("watching sentinel events, press Ctrl-C to stop")
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			fmt.Printf("%s %s\n", msg.Channel, msg.Payload)
+		case <-interrupt:
+			return
+		}
+	}
+}
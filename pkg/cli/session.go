@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// session is a named connection with its own settings: database, output
+// mode, string decoding and read-only. The settings of the active one live
+// in the connection flags, client and settings; USE saves them into its
+// session and loads another, so nothing leaks from one connection to the
+// next. The state of the connection itself, its node connections, past
+// replies and tracing, stays in the session.
+type session struct {
+	client        *redis.ClusterClient
+	hostname      string
+	port          string
+	socket        string
+	auth          string
+	user          string
+	dbn           int
+	useTLS        bool
+	serverVersion string
+	cluster       bool
+	commands      *commandTable
+	mode          format.Mode
+	strings       format.StringMode
+	readOnly      bool
+
+	nodeClients map[conn.Options]*redis.Client
+	replies     []pastReply
+	tracing     bool
+}
+
+// addr returns the address of the server of s, as addr() does for the
+// active connection.
+func (s *session) addr() string {
+	if s.socket != "" {
+		return s.socket
+	}
+	return s.hostname + ":" + s.port
+}
+
+// connReadOnly refuses writes on the active connection only, when it was
+// opened with --read-only. -read-only refuses them on all connections.
+var connReadOnly bool
+
+var (
+	// sessions holds the connections opened with OPEN, including the
+	// active one, whose entry is only up to date after a USE.
+	sessions = map[string]*session{}
+	// sessionName is the name of the active connection.
+	sessionName = "default"
+)
+
+// activeSession returns the session of the active connection, which
+// holds its state. Its settings are only up to date after currentSession.
+func activeSession() *session {
+	s, ok := sessions[sessionName]
+	if !ok {
+		s = &session{}
+		sessions[sessionName] = s
+	}
+	return s
+}
+
+// currentSession saves the settings of the active connection into its
+// session and returns it.
+func currentSession() *session {
+	s := activeSession()
+	s.client = client
+	s.hostname, s.port, s.socket = *hostname, *port, *socket
+	s.auth, s.user, s.dbn, s.useTLS = *auth, *user, *dbn, *useTLS
+	s.serverVersion, s.cluster, s.commands = serverVersion, clusterEnabled, serverCommands
+	s.mode, s.strings, s.readOnly = mode, format.Strings, connReadOnly
+	return s
+}
+
+// close closes the connection of s and those to its nodes.
+func (s *session) close() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	for _, c := range s.nodeClients {
+		c.Close()
+	}
+}
+
+// resetConnSettings gives the active connection the settings of the
+// command line.
+func resetConnSettings() {
+	mode = flagMode()
+	format.Strings, _ = format.ParseStringMode(*binaryMode)
+	connReadOnly = false
+}
+
+// switchSession makes the session name the active connection.
+func switchSession(name string) {
+	sessions[sessionName] = currentSession()
+	activateSession(name)
+}
+
+// activateSession loads the session name into the active connection,
+// without saving the one it replaces.
+func activateSession(name string) {
+	s := sessions[name]
+	client = s.client
+	*hostname, *port, *socket = s.hostname, s.port, s.socket
+	*auth, *user, *dbn, *useTLS = s.auth, s.user, s.dbn, s.useTLS
+	if s.commands == nil {
+		s.commands = &commandTable{}
+	}
+	serverVersion, clusterEnabled, serverCommands = s.serverVersion, s.cluster, s.commands
+	mode, format.Strings, connReadOnly = s.mode, s.strings, s.readOnly
+	sessionName = name
+	if serverVersion == "" {
+		detectServerVersion()
+	}
+}
+
+// openSession connects to a server under a name, without leaving the
+// active connection. The connection starts with the settings of the
+// command line, and refuses writes with --read-only.
+// Usage: OPEN name uri [--read-only]
+func openSession(args []string) {
+	readOnly := false
+	if len(args) == 3 && strings.ToLower(args[2]) == "--read-only" {
+		readOnly = true
+		args = args[:2]
+	}
+	if len(args) != 2 {
+		fmt.Println("(error) invalid args. Should be OPEN name redis://[user:password@]host:port[/db] [--read-only]")
+		return
+	}
+	name := lexer.TrimQuotes(args[0])
+	if _, ok := sessions[name]; ok || name == sessionName {
+		fmt.Printf("(error) %s is already open, switch to it with USE %s\n", name, name)
+		return
+	}
+	values, err := parseURI(lexer.TrimQuotes(args[1]))
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	s := &session{hostname: "127.0.0.1", port: "6379", useTLS: *useTLS, mode: flagMode(), readOnly: readOnly}
+	s.strings, _ = format.ParseStringMode(*binaryMode)
+	for flagName, v := range values {
+		switch flagName {
+		case "h":
+			s.hostname = v
+		case "p":
+			s.port = v
+		case "s":
+			s.socket = v
+		case "a":
+			s.auth = v
+		case "user":
+			s.user = v
+		case "n":
+			s.dbn, _ = strconv.Atoi(v)
+		case "tls":
+			s.useTLS, _ = strconv.ParseBool(v)
+		}
+	}
+
+	s.client = conn.New(conn.Options{
+		Addr:     s.addr(),
+		Username: s.user,
+		Password: s.auth,
+		TLS:      s.useTLS,
+		DB:       s.dbn,
+	})
+	if err := s.client.Ping().Err(); err != nil {
+		s.client.Close()
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	cliConnect()
+	sessions[sessionName] = currentSession()
+	sessions[name] = s
+	fmt.Println("OK")
+}
+
+// useSession switches to a connection opened with OPEN, or lists them.
+// Usage: USE [name]
+func useSession(args []string) {
+	if len(args) == 0 {
+		listSessions()
+		return
+	}
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be USE [name]")
+		return
+	}
+	name := lexer.TrimQuotes(args[0])
+	if name == sessionName {
+		fmt.Println("OK")
+		return
+	}
+	if _, ok := sessions[name]; !ok {
+		fmt.Printf("(error) no connection named %s, open it with OPEN %s uri\n", name, name)
+		return
+	}
+	switchSession(name)
+	fmt.Println("OK")
+}
+
+// closeSession closes a connection opened with OPEN other than the
+// active one.
+// Usage: CLOSE name
+func closeSession(args []string) {
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be CLOSE name")
+		return
+	}
+	name := lexer.TrimQuotes(args[0])
+	if name == sessionName {
+		fmt.Println("(error) can't close the active connection, USE another one first")
+		return
+	}
+	s, ok := sessions[name]
+	if !ok {
+		fmt.Printf("(error) no connection named %s\n", name)
+		return
+	}
+	s.close()
+	delete(sessions, name)
+	fmt.Println("OK")
+}
+
+func listSessions() {
+	sessions[sessionName] = currentSession()
+	names := make([]string, 0, len(sessions))
+	for name := range sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	rows := make([][]string, len(names))
+	for i, name := range names {
+		s := sessions[name]
+		active := ""
+		if name == sessionName {
+			active = "*"
+		}
+		access := "read-write"
+		if s.readOnly || *readOnly {
+			access = "read-only"
+		}
+		rows[i] = []string{active, name, s.addr(), strconv.Itoa(s.dbn), s.user, modeName(s.mode), access}
+	}
+	printTable([]string{"", "NAME", "ADDRESS", "DB", "USER", "MODE", "ACCESS"}, rows)
+}
+
+// runOnSession runs a command line on another connection, then switches
+// back.
+// Usage: :on name command [arg ...]
+func runOnSession(args []string) {
+	if len(args) < 2 {
+		fmt.Println("(error) invalid args. Should be :on name command [arg ...]")
+		return
+	}
+	name := lexer.TrimQuotes(args[0])
+	if name == sessionName {
+		execCommand(args[1:])
+		return
+	}
+	if _, ok := sessions[name]; !ok {
+		fmt.Printf("(error) no connection named %s, open it with OPEN %s uri\n", name, name)
+		return
+	}
+	previous := sessionName
+	switchSession(name)
+	defer switchSession(previous)
+	execCommand(args[1:])
+}
+
+// runOnAll runs a command line on every open connection in turn, under
+// the name of each, then switches back.
+// Usage: :all command [arg ...]
+func runOnAll(args []string) {
+	if len(args) == 0 {
+		fmt.Println("(error) invalid args. Should be :all command [arg ...]")
+		return
+	}
+	cliConnect()
+	sessions[sessionName] = currentSession()
+	names := make([]string, 0, len(sessions))
+	for name := range sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	previous := sessionName
+	defer switchSession(previous)
+	for i, name := range names {
+		if i > 0 {
+			fmt.Println()
+		}
+		switchSession(name)
+		fmt.Printf("%s%s%s (%s)\n", highlightStart, name, highlightEnd, addr())
+		execCommand(args)
+	}
+}
+
+// sessionPrompt returns the prefix of the prompt naming the active
+// connection, while several are open.
+func sessionPrompt() string {
+	if len(sessions) < 2 {
+		return ""
+	}
+	return "(" + sessionName + ") "
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// exit leaves the CLI with code after saving the history, restoring the
+// terminal and closing the connection. Every way out of the REPL goes
+// through it, as os.Exit skips deferred calls.
+func exit(code int) {
+	closeTerminal()
+	closeSinks()
+	if client != nil {
+		client.Close()
+	}
+	os.Exit(code)
+}
+
+// closeTerminal saves the history and gives the terminal back its mode.
+func closeTerminal() {
+	if line == nil {
+		return
+	}
+	saveHistory()
+	line.Close()
+	line = nil
+}
+
+// exitOnHangup exits cleanly when the terminal goes away or the process
+// is asked to stop, instead of leaving the terminal in raw mode or the
+// sinks unflushed.
+func exitOnHangup() {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP, syscall.SIGTERM)
+	go func() {
+		<-sigs
+		exit(1)
+	}()
+}
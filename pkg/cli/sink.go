@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// sink receives the events of the streaming modes (MONITOR, SUBSCRIBE,
+// NOTIFICATIONS, XTAIL, SLOWLOG-TAIL) besides stdout. line is the event as
+// a timestamped text line, or a JSON line with -jsonl.
+type sink interface {
+	Write(ev streamEvent, line string) error
+	Close() error
+}
+
+// listFlag collects the values of a repeated flag, such as -sink.
+type listFlag []string
+
+func (s *listFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *listFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+var (
+	sinkSpecs listFlag
+	sinks     []sink
+	// sinkFailed records the sinks whose error was reported, to report
+	// it once instead of on every event.
+	sinkFailed = map[sink]bool{}
+	// sinksMu serializes the writes to the sinks with closing them, which
+	// happens from the signal handler when the process is stopped.
+	sinksMu sync.Mutex
+)
+
+// openSinks opens the sinks given with -sink.
+func openSinks() error {
+	for _, spec := range sinkSpecs {
+		s, err := parseSink(spec)
+		if err != nil {
+			closeSinks()
+			return err
+		}
+		sinks = append(sinks, s)
+	}
+	return nil
+}
+
+// closeSinks closes the sinks, after which events only go to stdout.
+func closeSinks() {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	for _, s := range sinks {
+		s.Close()
+	}
+	sinks = nil
+}
+
+// writeSinks sends an event to every sink.
+func writeSinks(ev streamEvent, line string) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	for _, s := range sinks {
+		if err := s.Write(ev, line); err != nil && !sinkFailed[s] {
+			sinkFailed[s] = true
+			fmt.Fprintf(os.Stderr, "(error) sink: %s\n", err.Error())
+		}
+	}
+}
+
+// parseSink opens the sink described by spec.
+func parseSink(spec string) (sink, error) {
+	switch {
+	case strings.HasPrefix(spec, "http://") || strings.HasPrefix(spec, "https://"):
+		return newWebhookSink(spec), nil
+	case spec == "syslog" || strings.HasPrefix(spec, "syslog://") || strings.HasPrefix(spec, "syslog+tcp://"):
+		return newSyslogSink(spec)
+	case strings.HasPrefix(spec, "file:"):
+		return newFileSink(strings.TrimPrefix(spec, "file:"))
+	}
+	return nil, fmt.Errorf("invalid -sink %q, should be file:path, syslog[://host:port] or an http(s) URL", spec)
+}
+
+// fileSink appends lines to a file, rotating it once it reaches max
+// bytes and keeping keep old files: path.1 is the most recent.
+type fileSink struct {
+	path string
+	max  int64
+	keep int
+	f    *os.File
+	size int64
+}
+
+func newFileSink(spec string) (*fileSink, error) {
+	s := &fileSink{path: spec, keep: 5}
+	if i := strings.Index(spec, "?"); i >= 0 {
+		s.path = spec[:i]
+		q, err := url.ParseQuery(spec[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid file sink %q: %v", spec, err)
+		}
+		if v := q.Get("max"); v != "" {
+			if s.max, err = parseSize(v); err != nil {
+				return nil, fmt.Errorf("invalid file sink max %q", v)
+			}
+		}
+		if v := q.Get("keep"); v != "" {
+			if s.keep, err = strconv.Atoi(v); err != nil || s.keep < 0 {
+				return nil, fmt.Errorf("invalid file sink keep %q", v)
+			}
+		}
+	}
+	if s.path == "" {
+		return nil, fmt.Errorf("invalid file sink %q, the path is missing", spec)
+	}
+	return s, s.open()
+}
+
+func (s *fileSink) open() error {
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.f, s.size = f, fi.Size()
+	return nil
+}
+
+func (s *fileSink) Write(ev streamEvent, line string) error {
+	if s.max > 0 && s.size > 0 && s.size+int64(len(line))+1 > s.max {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.f.WriteString(line + "\n")
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileSink) rotate() error {
+	s.f.Close()
+	if s.keep == 0 {
+		os.Remove(s.path)
+	} else {
+		os.Remove(fmt.Sprintf("%s.%d", s.path, s.keep))
+		for i := s.keep - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		}
+		os.Rename(s.path, s.path+".1")
+	}
+	return s.open()
+}
+
+func (s *fileSink) Close() error {
+	return s.f.Close()
+}
+
+// parseSize parses a size in bytes with an optional KB, MB or GB suffix.
+func parseSize(s string) (int64, error) {
+	mult := int64(1)
+	upper := strings.ToUpper(s)
+	for _, u := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(upper, u.suffix) {
+			upper, mult = strings.TrimSuffix(upper, u.suffix), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(upper), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// webhookQueue is the number of events a webhook sink holds while the
+// endpoint is slow; more are dropped.
+const webhookQueue = 1000
+
+// webhookCloseTimeout bounds how long closing a webhook sink keeps sending
+// the queued events.
+const webhookCloseTimeout = 10 * time.Second
+
+// webhookSink POSTs every event as JSON to a URL, from a goroutine so a
+// slow endpoint doesn't hold the stream back.
+type webhookSink struct {
+	url    string
+	events chan streamEvent
+	// ctx is cancelled when Close gives up on the queued events.
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	err     error
+	dropped int
+}
+
+func newWebhookSink(u string) *webhookSink {
+	s := &webhookSink{url: u, events: make(chan streamEvent, webhookQueue)}
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+func (s *webhookSink) run() {
+	defer s.wg.Done()
+	hc := &http.Client{Timeout: 5 * time.Second}
+	for ev := range s.events {
+		if s.ctx.Err() != nil {
+			s.drop()
+			continue
+		}
+		ev.Payload = format.JSONValue(ev.Payload)
+		b, err := json.Marshal(ev)
+		if err == nil {
+			var req *http.Request
+			req, err = http.NewRequestWithContext(s.ctx, "POST", s.url, bytes.NewReader(b))
+			if err == nil {
+				req.Header.Set("Content-Type", "application/json")
+				var resp *http.Response
+				resp, err = hc.Do(req)
+				if err == nil {
+					resp.Body.Close()
+					if resp.StatusCode >= 300 {
+						err = fmt.Errorf("%s: %s", s.url, resp.Status)
+					}
+				}
+			}
+		}
+		if err != nil && s.ctx.Err() != nil {
+			s.drop()
+		} else if err != nil {
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+		}
+	}
+}
+
+// drop counts an event that wasn't sent, and returns the count.
+func (s *webhookSink) drop() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropped++
+	return s.dropped
+}
+
+func (s *webhookSink) Write(ev streamEvent, line string) error {
+	select {
+	case s.events <- ev:
+	default:
+		if s.drop() == 1 {
+			return fmt.Errorf("%s is too slow, dropping events", s.url)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close sends the queued events before returning, for webhookCloseTimeout
+// at most: those still queued then are dropped.
+func (s *webhookSink) Close() error {
+	close(s.events)
+	timer := time.AfterFunc(webhookCloseTimeout, s.cancel)
+	s.wg.Wait()
+	timer.Stop()
+	s.cancel()
+	if s.dropped > 0 {
+		fmt.Fprintf(os.Stderr, "(error) sink: dropped %d events for %s\n", s.dropped, s.url)
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// slotKeys lists keys of a slot, asking the master serving it.
+// Usage: SLOT-KEYS slot [count]
+func slotKeys(args []string) {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Println("(error) invalid args. Should be SLOT-KEYS slot [count]")
+		return
+	}
+	slot, err := strconv.Atoi(lexer.TrimQuotes(args[0]))
+	if err != nil || slot < 0 || slot >= clusterSlots {
+		fmt.Printf("(error) invalid slot %s, should be 0-%d\n", args[0], clusterSlots-1)
+		return
+	}
+	count := 10
+	if len(args) == 2 {
+		if count, err = strconv.Atoi(lexer.TrimQuotes(args[1])); err != nil || count <= 0 {
+			fmt.Println("(error) invalid args. Should be SLOT-KEYS slot [count]")
+			return
+		}
+	}
+	cliConnect()
+
+	nodes, err := fetchClusterNodes()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	owner := slotOwner(nodes, slot)
+	if owner == nil {
+		fmt.Printf("(error) slot %d is not served by any node\n", slot)
+		return
+	}
+
+	c := conn.NewSingle(connOptions(owner.Addr, *auth))
+	defer c.Close()
+	total, err := c.ClusterCountKeysInSlot(slot).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	keys, err := c.ClusterGetKeysInSlot(slot, count).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	fmt.Printf("slot %d on %s holds %d keys\n", slot, owner.Addr, total)
+	reply := make([]interface{}, len(keys))
+	for i, k := range keys {
+		reply[i] = k
+	}
+	printReply(0, reply, mode)
+	fmt.Println()
+	if int64(len(keys)) < total {
+		fmt.Printf("... and %d more, raise the count to see them\n", total-int64(len(keys)))
+	}
+}
+
+// slotOwner returns the master serving slot.
+func slotOwner(nodes []*clusterNode, slot int) *clusterNode {
+	for _, n := range nodes {
+		if !n.HasFlag("master") {
+			continue
+		}
+		for _, r := range n.Slots {
+			if slot >= r[0] && slot <= r[1] {
+				return n
+			}
+		}
+	}
+	return nil
+}
+
+// slotCount is the number of keys of a slot.
+type slotCount struct {
+	slot int
+	keys int64
+}
+
+// hotSlots shows the slots holding the most keys on every master, with
+// the keys of each master, to spot skew before resharding.
+// Usage: HOT-SLOTS [n]
+func hotSlots(args []string) {
+	top := 10
+	if len(args) == 1 {
+		var err error
+		if top, err = strconv.Atoi(lexer.TrimQuotes(args[0])); err != nil || top <= 0 {
+			fmt.Println("(error) invalid args. Should be HOT-SLOTS [n]")
+			return
+		}
+	} else if len(args) > 1 {
+		fmt.Println("(error) invalid args. Should be HOT-SLOTS [n]")
+		return
+	}
+	cliConnect()
+
+	nodes, err := fetchClusterNodes()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Addr < nodes[j].Addr
+	})
+
+	// count the keys of every slot, all masters at once
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		counts = map[string][]slotCount{}
+		errs   = map[string]error{}
+	)
+	for _, n := range nodes {
+		if !n.HasFlag("master") || n.SlotCount() == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(n *clusterNode) {
+			defer wg.Done()
+			c, err := countSlotKeys(n)
+			mu.Lock()
+			counts[n.Addr], errs[n.Addr] = c, err
+			mu.Unlock()
+		}(n)
+	}
+	wg.Wait()
+
+	var grand int64
+	for _, c := range counts {
+		for _, sc := range c {
+			grand += sc.keys
+		}
+	}
+
+	first := true
+	for _, n := range nodes {
+		c, ok := counts[n.Addr]
+		if !ok && errs[n.Addr] == nil {
+			continue
+		}
+		if !first {
+			fmt.Println()
+		}
+		first = false
+		if err := errs[n.Addr]; err != nil {
+			fmt.Printf("%s%s%s (error) %s\n", highlightStart, n.Addr, highlightEnd, err.Error())
+			continue
+		}
+
+		var total int64
+		for _, sc := range c {
+			total += sc.keys
+		}
+		avg := float64(total) / float64(len(c))
+		fmt.Printf("%s%s%s %d slots, %d keys (%s of the cluster), %.1f keys per slot\n",
+			highlightStart, n.Addr, highlightEnd, len(c), total, percent(total, grand), avg)
+
+		sort.SliceStable(c, func(i, j int) bool {
+			return c[i].keys > c[j].keys
+		})
+		if len(c) > top {
+			c = c[:top]
+		}
+		var rows [][]string
+		for _, sc := range c {
+			if sc.keys == 0 {
+				break
+			}
+			ratio := "-"
+			if avg > 0 {
+				ratio = fmt.Sprintf("%.1fx", float64(sc.keys)/avg)
+			}
+			rows = append(rows, []string{strconv.Itoa(sc.slot), strconv.FormatInt(sc.keys, 10), percent(sc.keys, total), ratio})
+		}
+		if len(rows) == 0 {
+			fmt.Println("  no keys")
+			continue
+		}
+		printTable([]string{"SLOT", "KEYS", "OF NODE", "VS AVERAGE"}, rows)
+	}
+}
+
+// countSlotKeys runs CLUSTER COUNTKEYSINSLOT for every slot of a master,
+// in one pipeline.
+func countSlotKeys(n *clusterNode) ([]slotCount, error) {
+	c := conn.NewSingle(connOptions(n.Addr, *auth))
+	defer c.Close()
+
+	var cmds []*redis.IntCmd
+	var slots []int
+	_, err := c.Pipelined(func(pipe redis.Pipeliner) error {
+		for _, r := range n.Slots {
+			for s := r[0]; s <= r[1] && s < clusterSlots; s++ {
+				cmds = append(cmds, pipe.ClusterCountKeysInSlot(s))
+				slots = append(slots, s)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	counts := make([]slotCount, len(cmds))
+	for i, cmd := range cmds {
+		counts[i] = slotCount{slots[i], cmd.Val()}
+	}
+	return counts, nil
+}
+
+// percent formats part as a percentage of total.
+func percent(part, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	clusterSlots = 16384
+	slotsBarSize = 64
+)
+
+// slotsView renders the slot space as one bar per master, followed by
+// the uncovered slots and the slots being migrated.
+// Usage: SLOTS
+func slotsView(args []string) {
+	if len(args) != 0 {
+		fmt.Println("(error) invalid args. Should be SLOTS")
+		return
+	}
+	cliConnect()
+
+	nodes, err := fetchClusterNodes()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Addr < nodes[j].Addr
+	})
+
+	var covered [clusterSlots]bool
+	byID := map[string]*clusterNode{}
+	for _, n := range nodes {
+		byID[n.ID] = n
+	}
+
+	fmt.Printf("each cell is %d slots: # all served, + partly served, . none\n\n", clusterSlots/slotsBarSize)
+	for _, n := range nodes {
+		if !n.HasFlag("master") {
+			continue
+		}
+		var owned [clusterSlots]bool
+		for _, r := range n.Slots {
+			for s := r[0]; s <= r[1] && s < clusterSlots; s++ {
+				owned[s] = true
+				covered[s] = true
+			}
+		}
+		fmt.Printf("%-21s %s %5d\n", n.Addr, slotsBar(owned[:], "#", "+", "."), n.SlotCount())
+	}
+
+	var uncovered [clusterSlots]bool
+	missing := 0
+	for s, ok := range covered {
+		if !ok {
+			uncovered[s] = true
+			missing++
+		}
+	}
+	if missing > 0 {
+		fmt.Printf("%-21s %s %5d\n", "UNCOVERED", slotsBar(uncovered[:], "!", "!", " "), missing)
+		fmt.Printf("\nuncovered slots: %s\n", slotRanges(uncovered[:]))
+	} else {
+		fmt.Printf("\nall %d slots are covered\n", clusterSlots)
+	}
+
+	var migrations []string
+	for _, n := range nodes {
+		for slot, peer := range n.Migrating {
+			migrations = append(migrations, fmt.Sprintf("slot %5d MIGRATING %s -> %s", slot, n.Addr, nodeName(byID, peer)))
+		}
+		for slot, peer := range n.Importing {
+			migrations = append(migrations, fmt.Sprintf("slot %5d IMPORTING %s <- %s", slot, n.Addr, nodeName(byID, peer)))
+		}
+	}
+	if len(migrations) > 0 {
+		sort.Strings(migrations)
+		fmt.Println("\nmigrations in progress:")
+		for _, m := range migrations {
+			fmt.Println("  " + m)
+		}
+	}
+}
+
+// slotsBar renders set slots as a bar of slotsBarSize cells.
+func slotsBar(set []bool, full, partial, none string) string {
+	per := len(set) / slotsBarSize
+	var b strings.Builder
+	for cell := 0; cell < slotsBarSize; cell++ {
+		count := 0
+		for s := cell * per; s < (cell+1)*per; s++ {
+			if set[s] {
+				count++
+			}
+		}
+		switch count {
+		case per:
+			b.WriteString(full)
+		case 0:
+			b.WriteString(none)
+		default:
+			b.WriteString(partial)
+		}
+	}
+	return b.String()
+}
+
+// slotRanges formats set slots as ranges, e.g. "0-99, 200".
+func slotRanges(set []bool) string {
+	var ranges []string
+	for s := 0; s < len(set); s++ {
+		if !set[s] {
+			continue
+		}
+		start := s
+		for s+1 < len(set) && set[s+1] {
+			s++
+		}
+		if start == s {
+			ranges = append(ranges, fmt.Sprint(start))
+		} else {
+			ranges = append(ranges, fmt.Sprintf("%d-%d", start, s))
+		}
+	}
+	return strings.Join(ranges, ", ")
+}
+
+// nodeName returns the address of a node ID, or the ID when unknown.
+func nodeName(byID map[string]*clusterNode, id string) string {
+	if n, ok := byID[id]; ok {
+		return n.Addr
+	}
+	return id
+}
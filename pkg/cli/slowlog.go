@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/conn"
+)
+
+// slowlogEntry is an entry of SLOWLOG GET.
+type slowlogEntry struct {
+	ID       int64    `json:"id"`
+	Time     int64    `json:"time"`
+	Duration int64    `json:"duration_us"`
+	Args     []string `json:"args"`
+	Client   string   `json:"client,omitempty"`
+	Name     string   `json:"client_name,omitempty"`
+}
+
+// parseSlowlog parses a SLOWLOG GET reply, newest entries first.
+func parseSlowlog(reply interface{}) []slowlogEntry {
+	items, _ := reply.([]interface{})
+	var entries []slowlogEntry
+	for _, it := range items {
+		fields, ok := it.([]interface{})
+		if !ok || len(fields) < 4 {
+			continue
+		}
+		var e slowlogEntry
+		e.ID, _ = fields[0].(int64)
+		e.Time, _ = fields[1].(int64)
+		e.Duration, _ = fields[2].(int64)
+		args, _ := fields[3].([]interface{})
+		for _, a := range args {
+			e.Args = append(e.Args, fmt.Sprint(a))
+		}
+		// Redis 4.0 added the client address and name
+		if len(fields) >= 6 {
+			e.Client, _ = fields[4].(string)
+			e.Name, _ = fields[5].(string)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// slowlogTail polls the slow log of the server every -interval seconds
+// and prints the entries added after it starts until Ctrl-C.
+// Usage: SLOWLOG-TAIL
+func slowlogTail(args []string) {
+	if len(args) != 0 {
+		fmt.Println("(error) invalid args. Should be SLOWLOG-TAIL")
+		return
+	}
+	if err := checkAllowed([]string{"slowlog", "get"}); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	node := addr()
+	c := conn.NewSingle(clientOptions(node, *auth))
+	defer c.Close()
+
+	interrupt, stop := interrupted()
+	defer stop()
+
+	reply, err := c.Do("slowlog", "get", 1).Result()
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	last := int64(-1)
+	if entries := parseSlowlog(reply); len(entries) > 0 {
+		last = entries[0].ID
+	}
+	if !*jsonl {
+		fmt.Printf("following the slow log of %s, press Ctrl-C to stop\n", node)
+	}
+
+	tick := time.NewTicker(time.Duration(*interval * float64(time.Second)))
+	defer tick.Stop()
+	for {
+		select {
+		case <-interrupt:
+			return
+		case <-tick.C:
+		}
+
+		reply, err := c.Do("slowlog", "get", 128).Result()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		entries := parseSlowlog(reply)
+		// the reply is newest first, print oldest first
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			if e.ID <= last {
+				continue
+			}
+			last = e.ID
+			emitEvent(streamEvent{
+				Time:    time.Unix(e.Time, 0),
+				Node:    node,
+				Source:  "slowlog",
+				Payload: e,
+			}, fmt.Sprintf("#%d %s %s", e.ID, time.Duration(e.Duration)*time.Microsecond, slowlogArgs(e.Args)))
+		}
+	}
+}
+
+func slowlogArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = strconv.Quote(a)
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,267 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// snapshotDir holds the saved sessions, one JSON file each.
+var snapshotDir = path.Join(os.Getenv("HOME"), ".gorediscli_sessions") // $HOME/.gorediscli_sessions
+
+// snapshot is a saved set of named connections and output settings.
+// Passwords are never saved: restoring asks for them again.
+type snapshot struct {
+	Active      string               `json:"active"`
+	Connections []snapshotConnection `json:"connections"`
+	Timestamps  string               `json:"timestamps,omitempty"`
+	Trace       bool                 `json:"trace,omitempty"`
+}
+
+// snapshotConnection is a named connection with its settings.
+type snapshotConnection struct {
+	Name     string `json:"name"`
+	Host     string `json:"host,omitempty"`
+	Port     string `json:"port,omitempty"`
+	Socket   string `json:"socket,omitempty"`
+	User     string `json:"user,omitempty"`
+	DB       int    `json:"db"`
+	TLS      bool   `json:"tls,omitempty"`
+	Password bool   `json:"password,omitempty"`
+	Mode     string `json:"mode"`
+	Strings  string `json:"strings"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+}
+
+// stringModes are the names of the -binary styles.
+var stringModes = []string{"auto", "hex", "escape", "as-is"}
+
+func stringModeName(m format.StringMode) string {
+	for _, name := range stringModes {
+		if sm, _ := format.ParseStringMode(name); sm == m {
+			return name
+		}
+	}
+	return "auto"
+}
+
+// sessionCommand saves the open connections and output settings under a
+// name, restores them, or lists the saved ones.
+// Usage: SESSION SAVE name | SESSION RESTORE name | SESSION LIST | SESSION DELETE name
+func sessionCommand(args []string) {
+	const usage = "(error) invalid args. Should be SESSION SAVE name | RESTORE name | LIST | DELETE name"
+	if len(args) == 0 {
+		fmt.Println(usage)
+		return
+	}
+	sub := strings.ToLower(args[0])
+	if sub == "list" && len(args) == 1 {
+		listSnapshots()
+		return
+	}
+	if len(args) != 2 {
+		fmt.Println(usage)
+		return
+	}
+	name := lexer.TrimQuotes(args[1])
+	if !validMacroName(name) {
+		fmt.Printf("(error) invalid session name %q\n", name)
+		return
+	}
+	file := path.Join(snapshotDir, name+".json")
+
+	var err error
+	switch sub {
+	case "save":
+		err = saveSnapshot(file)
+	case "restore":
+		err = restoreSnapshot(file)
+	case "delete":
+		if err = os.Remove(file); os.IsNotExist(err) {
+			err = fmt.Errorf("no session named %s", name)
+		}
+	default:
+		fmt.Println(usage)
+		return
+	}
+	if err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	fmt.Println("OK")
+}
+
+func saveSnapshot(file string) error {
+	cliConnect()
+	sessions[sessionName] = currentSession()
+	names := make([]string, 0, len(sessions))
+	for name := range sessions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	snap := snapshot{Active: sessionName, Timestamps: *timestamps, Trace: activeSession().tracing}
+	for _, name := range names {
+		s := sessions[name]
+		snap.Connections = append(snap.Connections, snapshotConnection{
+			Name:     name,
+			Host:     s.hostname,
+			Port:     s.port,
+			Socket:   s.socket,
+			User:     s.user,
+			DB:       s.dbn,
+			TLS:      s.useTLS,
+			Password: s.auth != "",
+			Mode:     modeName(s.mode),
+			Strings:  stringModeName(s.strings),
+			ReadOnly: s.readOnly,
+		})
+	}
+
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(snapshotDir, 0700); err != nil {
+		return err
+	}
+	return writeFileAtomic(file, append(data, '\n'), 0600)
+}
+
+// restoreSnapshot opens the connections of a saved session in place of
+// the open ones, asking for the passwords they need unless an open
+// connection to the same server and user has one.
+func restoreSnapshot(file string) error {
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no session named %s, see SESSION LIST", strings.TrimSuffix(path.Base(file), ".json"))
+	} else if err != nil {
+		return err
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("%s: %v", file, err)
+	}
+	if len(snap.Connections) == 0 {
+		return fmt.Errorf("%s has no connections", file)
+	}
+
+	if client != nil {
+		sessions[sessionName] = currentSession()
+	}
+	opened := map[string]*session{}
+	closeOpened := func() {
+		for _, s := range opened {
+			s.client.Close()
+		}
+	}
+	for _, c := range snap.Connections {
+		s := &session{
+			hostname: c.Host,
+			port:     c.Port,
+			socket:   c.Socket,
+			user:     c.User,
+			dbn:      c.DB,
+			useTLS:   c.TLS,
+			mode:     flagMode(),
+			readOnly: c.ReadOnly,
+		}
+		if m, ok := modes[c.Mode]; ok {
+			s.mode = m
+		}
+		s.strings, _ = format.ParseStringMode(c.Strings)
+		if c.Password {
+			if s.auth, err = snapshotPassword(c.Name, s); err != nil {
+				closeOpened()
+				return err
+			}
+		}
+		s.client = conn.New(conn.Options{
+			Addr:     s.addr(),
+			Username: s.user,
+			Password: s.auth,
+			TLS:      s.useTLS,
+			DB:       s.dbn,
+		})
+		if err := s.client.Ping().Err(); err != nil {
+			s.client.Close()
+			closeOpened()
+			return fmt.Errorf("%s (%s): %v", c.Name, s.addr(), err)
+		}
+		opened[c.Name] = s
+	}
+	if _, ok := opened[snap.Active]; !ok {
+		closeOpened()
+		return fmt.Errorf("%s has no connection named %s", file, snap.Active)
+	}
+
+	for _, s := range sessions {
+		s.close()
+	}
+	sessions = opened
+	activateSession(snap.Active)
+	if validTimestamps(snap.Timestamps) {
+		*timestamps = snap.Timestamps
+	}
+	activeSession().tracing = snap.Trace
+	return nil
+}
+
+// snapshotPassword returns the password of a connection being restored:
+// that of an open connection to the same server and user, that of -a for
+// the server and user of the command line, or one typed at a prompt.
+func snapshotPassword(name string, s *session) (string, error) {
+	for _, open := range sessions {
+		if open.auth != "" && open.addr() == s.addr() && open.user == s.user {
+			return open.auth, nil
+		}
+	}
+	if *auth != "" && addr() == s.addr() && *user == s.user {
+		return *auth, nil
+	}
+	if line == nil {
+		return "", fmt.Errorf("%s (%s) needs a password, open it with -h, -p and -a or restore the session at the prompt", name, s.addr())
+	}
+	return line.PasswordPrompt(fmt.Sprintf("password for %s (%s): ", name, s.addr()))
+}
+
+func listSnapshots() {
+	files, err := ioutil.ReadDir(snapshotDir)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	var rows [][]string
+	for _, f := range files {
+		if f.IsDir() || path.Ext(f.Name()) != ".json" {
+			continue
+		}
+		var snap snapshot
+		data, err := ioutil.ReadFile(path.Join(snapshotDir, f.Name()))
+		if err == nil {
+			err = json.Unmarshal(data, &snap)
+		}
+		if err != nil {
+			rows = append(rows, []string{strings.TrimSuffix(f.Name(), ".json"), "?", err.Error(), ""})
+			continue
+		}
+		names := make([]string, len(snap.Connections))
+		for i, c := range snap.Connections {
+			names[i] = c.Name
+		}
+		rows = append(rows, []string{strings.TrimSuffix(f.Name(), ".json"), snap.Active, strings.Join(names, " "), f.ModTime().Format("2006-01-02 15:04")})
+	}
+	if len(rows) == 0 {
+		fmt.Println("(empty list), save the open connections with SESSION SAVE name")
+		return
+	}
+	printTable([]string{"SESSION", "ACTIVE", "CONNECTIONS", "SAVED"}, rows)
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// streamEvent is one item printed by a streaming mode: SCAN with -jsonl,
+// SUBSCRIBE, MONITOR, XTAIL or NOTIFICATIONS.
+type streamEvent struct {
+	Time    time.Time   `json:"time"`
+	Node    string      `json:"node"`
+	Source  string      `json:"source"`
+	Channel string      `json:"channel,omitempty"`
+	Payload interface{} `json:"payload"`
+}
+
+// emitEvent prints ev as a JSON line with -jsonl, or text otherwise, and
+// sends it to the -sink outputs.
+func emitEvent(ev streamEvent, text string) {
+	line, err := eventLine(ev, text)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "(error) %s\n", err.Error())
+		return
+	}
+	if *jsonl {
+		fmt.Println(line)
+	} else {
+		fmt.Println(timestampPrefix(ev.Time) + text)
+	}
+	writeSinks(ev, line)
+}
+
+// eventLine returns ev as written to files: a JSON line with -jsonl, or
+// the text after the time of the event.
+func eventLine(ev streamEvent, text string) (string, error) {
+	if !*jsonl {
+		return ev.Time.Format(time.RFC3339) + " " + text, nil
+	}
+	ev.Payload = format.JSONValue(ev.Payload)
+	b, err := json.Marshal(ev)
+	return string(b), err
+}
+
+// interrupted returns a channel receiving Ctrl-C, and the function to
+// stop listening.
+func interrupted() (<-chan os.Signal, func()) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt)
+	return c, func() { signal.Stop(c) }
+}
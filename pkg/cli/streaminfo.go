@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// streamInfo renders XINFO STREAM FULL and XPENDING as tables: the
+// consumer groups with their pending entries lists, the consumers of
+// every group, and the oldest pending entries.
+// Usage: STREAM-INFO key [--pending n]
+func streamInfo(args []string) {
+	const usage = "(error) invalid args. Should be STREAM-INFO key [--pending n]"
+	var key string
+	pending := int64(5)
+	for i := 0; i < len(args); i++ {
+		if strings.ToLower(args[i]) == "--pending" && i+1 < len(args) {
+			n, err := strconv.ParseInt(args[i+1], 10, 64)
+			if err != nil || n < 0 {
+				fmt.Println(usage)
+				return
+			}
+			pending = n
+			i++
+		} else if key == "" {
+			key = lexer.TrimQuotes(args[i])
+		} else {
+			fmt.Println(usage)
+			return
+		}
+	}
+	if key == "" {
+		fmt.Println(usage)
+		return
+	}
+	cliConnect()
+
+	// COUNT 1 keeps the entries and PEL samples out, the counts are exact
+	r, err := client.Do("XINFO", "STREAM", key, "FULL", "COUNT", 1).Result()
+	if err != nil {
+		if strings.Contains(err.Error(), "syntax") || strings.Contains(err.Error(), "unknown") {
+			err = fmt.Errorf("%v (STREAM-INFO needs XINFO STREAM FULL, Redis 6.0 or later)", err)
+		}
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	stream := replyFields(r)
+
+	fmt.Printf("%s%s%s: %s entries, last id %s", highlightStart, key, highlightEnd, replyString(stream["length"]), replyString(stream["last-generated-id"]))
+	if first := replyString(stream["recorded-first-entry-id"]); first != "-" {
+		fmt.Printf(", first id %s", first)
+	}
+	if added := replyString(stream["entries-added"]); added != "-" {
+		fmt.Printf(", %s added in all", added)
+	}
+	fmt.Println()
+
+	groups, _ := stream["groups"].([]interface{})
+	if len(groups) == 0 {
+		fmt.Println("\nno consumer groups")
+		return
+	}
+
+	var rows [][]string
+	for _, g := range groups {
+		group := replyFields(g)
+		name := replyString(group["name"])
+		consumers, _ := group["consumers"].([]interface{})
+		minID, maxID := "-", "-"
+		if summary, err := client.Do("XPENDING", key, name).Result(); err == nil {
+			if arr, ok := summary.([]interface{}); ok && len(arr) >= 3 && arr[1] != nil {
+				minID, maxID = replyString(arr[1]), replyString(arr[2])
+			}
+		}
+		rows = append(rows, []string{
+			name,
+			strconv.Itoa(len(consumers)),
+			replyString(group["pel-count"]),
+			minID,
+			maxID,
+			replyString(group["last-delivered-id"]),
+			replyString(group["lag"]),
+		})
+	}
+	fmt.Println()
+	printTable([]string{"GROUP", "CONSUMERS", "PENDING", "MIN PENDING ID", "MAX PENDING ID", "LAST DELIVERED", "LAG"}, rows)
+
+	now := time.Now()
+	for _, g := range groups {
+		group := replyFields(g)
+		name := replyString(group["name"])
+
+		consumers, _ := group["consumers"].([]interface{})
+		if len(consumers) > 0 {
+			fmt.Printf("\n%s%s%s consumers:\n", highlightStart, name, highlightEnd)
+			var rows [][]string
+			for _, c := range consumers {
+				consumer := replyFields(c)
+				rows = append(rows, []string{
+					replyString(consumer["name"]),
+					replyString(consumer["pel-count"]),
+					sinceMillis(now, consumer["seen-time"]),
+					sinceMillis(now, consumer["active-time"]),
+				})
+			}
+			printTable([]string{"CONSUMER", "PENDING", "LAST SEEN", "LAST ACTIVE"}, rows)
+		}
+
+		if pending == 0 || replyString(group["pel-count"]) == "0" {
+			continue
+		}
+		r, err := client.Do("XPENDING", key, name, "-", "+", pending).Result()
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			continue
+		}
+		entries, _ := r.([]interface{})
+		fmt.Printf("\n%s%s%s oldest pending:\n", highlightStart, name, highlightEnd)
+		var rows [][]string
+		for _, e := range entries {
+			entry, ok := e.([]interface{})
+			if !ok || len(entry) < 4 {
+				continue
+			}
+			idle, _ := entry[2].(int64)
+			rows = append(rows, []string{
+				replyString(entry[0]),
+				replyString(entry[1]),
+				idleString(idle),
+				replyString(entry[3]),
+			})
+		}
+		printTable([]string{"ID", "CONSUMER", "IDLE", "DELIVERIES"}, rows)
+	}
+}
+
+// replyString formats a scalar reply, "-" for nil.
+func replyString(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(v)
+}
+
+// sinceMillis formats how long ago a unix time in milliseconds was, "-"
+// when the server doesn't report it.
+func sinceMillis(now time.Time, v interface{}) string {
+	ms, ok := v.(int64)
+	if !ok || ms <= 0 {
+		return "-"
+	}
+	return idleString(now.Sub(time.Unix(0, ms*int64(time.Millisecond))).Nanoseconds()/int64(time.Millisecond)) + " ago"
+}
+
+// idleString formats a duration in milliseconds, to the second once it
+// exceeds a minute.
+func idleString(ms int64) string {
+	d := time.Duration(ms) * time.Millisecond
+	if d >= time.Minute {
+		d = d.Round(time.Second)
+	}
+	return d.String()
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// subscribe listens to channels, or patterns for PSUBSCRIBE, and prints
+// the messages until Ctrl-C. --grep keeps the messages whose payload
+// matches, --stats prints the messages per second and channel, and --out
+// writes the messages to a file instead of the terminal, with the stats.
+// Usage: SUBSCRIBE channel [channel ...] [--grep regex] [--stats] [--out file]
+// PSUBSCRIBE pattern [pattern ...] [--grep regex] [--stats] [--out file]
+func subscribe(cmd string, args []string) {
+	usage := fmt.Sprintf("(error) invalid args. Should be %s channel [channel ...] [--grep regex] [--stats] [--out file]", strings.ToUpper(cmd))
+	var opts streamOptions
+	var channels []string
+	for i := 0; i < len(args); i++ {
+		switch opt := strings.ToLower(args[i]); {
+		case opt == "--grep" && i+1 < len(args):
+			re, err := regexp.Compile(lexer.TrimQuotes(args[i+1]))
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			opts.grep = re
+			i++
+		case opt == "--stats":
+			opts.stats = true
+		case opt == "--out" && i+1 < len(args):
+			out, err := newFileSink(lexer.TrimQuotes(args[i+1]))
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			defer out.Close()
+			opts.out = out
+			opts.stats = true
+			i++
+		case strings.HasPrefix(opt, "--"):
+			fmt.Println(usage)
+			return
+		default:
+			channels = append(channels, lexer.TrimQuotes(args[i]))
+		}
+	}
+	if len(channels) == 0 {
+		fmt.Println(usage)
+		return
+	}
+	cliConnect()
+	if err := checkAllowed(append([]string{cmd}, channels...)); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+
+	var pubsub *redis.PubSub
+	if cmd == "psubscribe" {
+		pubsub = client.PSubscribe(channels...)
+	} else {
+		pubsub = client.Subscribe(channels...)
+	}
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if !*jsonl || opts.out != nil {
+		fmt.Printf("listening to %s, press Ctrl-C to stop\n", strings.Join(channels, " "))
+	}
+	streamMessages(pubsub, "message", opts, func(msg *redis.Message) (interface{}, string) {
+		return msg.Payload, fmt.Sprintf("%s %s", msg.Channel, msg.Payload)
+	})
+}
+
+// notifications prints the keyspace notifications of the keys matching
+// pattern in the current database.
+// Usage: NOTIFICATIONS [pattern]
+func notifications(args []string) {
+	if len(args) > 1 {
+		fmt.Println("(error) invalid args. Should be NOTIFICATIONS [pattern]")
+		return
+	}
+	pattern := "*"
+	if len(args) == 1 {
+		pattern = lexer.TrimQuotes(args[0])
+	}
+	cliConnect()
+
+	if cfg, err := client.ConfigGet("notify-keyspace-events").Result(); err == nil && len(cfg) == 2 && fmt.Sprint(cfg[1]) == "" {
+		fmt.Println("warning: notify-keyspace-events is empty, enable it with CONFIG SET notify-keyspace-events KEA")
+	}
+
+	prefix := fmt.Sprintf("__keyspace@%d__:", *dbn)
+	pubsub := client.PSubscribe(prefix + pattern)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	}
+	if !*jsonl {
+		fmt.Printf("listening to notifications of %s, press Ctrl-C to stop\n", pattern)
+	}
+	streamMessages(pubsub, "notification", streamOptions{}, func(msg *redis.Message) (interface{}, string) {
+		key := strings.TrimPrefix(msg.Channel, prefix)
+		return map[string]string{"key": key, "event": msg.Payload}, fmt.Sprintf("%s %s", key, msg.Payload)
+	})
+}
+
+// streamOptions filter and count the messages of a subscription.
+type streamOptions struct {
+	grep  *regexp.Regexp
+	stats bool
+	// out receives the messages instead of the terminal.
+	out *fileSink
+}
+
+// channelStats counts the messages of a channel.
+type channelStats struct {
+	received, matched int64
+}
+
+// streamMessages emits the messages of pubsub until Ctrl-C. render
+// returns the payload and the text of a message.
+func streamMessages(pubsub *redis.PubSub, source string, opts streamOptions, render func(*redis.Message) (interface{}, string)) {
+	interrupt, stop := interrupted()
+	defer stop()
+
+	var tick <-chan time.Time
+	if opts.stats {
+		t := time.NewTicker(time.Second)
+		defer t.Stop()
+		tick = t.C
+	}
+	start := time.Now()
+	stats := map[string]*channelStats{}
+	var matched, lastMatched int64
+	defer func() {
+		if opts.stats || opts.grep != nil {
+			printChannelStats(stats, time.Since(start))
+		}
+	}()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			cs := stats[msg.Channel]
+			if cs == nil {
+				cs = &channelStats{}
+				stats[msg.Channel] = cs
+			}
+			cs.received++
+			if opts.grep != nil && !opts.grep.MatchString(msg.Payload) {
+				continue
+			}
+			cs.matched++
+			matched++
+
+			payload, text := render(msg)
+			ev := streamEvent{
+				Time:    time.Now(),
+				Node:    addr(),
+				Source:  source,
+				Channel: msg.Channel,
+				Payload: payload,
+			}
+			if opts.out == nil {
+				emitEvent(ev, text)
+				continue
+			}
+			line, err := eventLine(ev, text)
+			if err == nil {
+				err = opts.out.Write(ev, line)
+			}
+			if err != nil {
+				fmt.Printf("(error) %s\n", err.Error())
+				return
+			}
+			writeSinks(ev, line)
+		case now := <-tick:
+			fmt.Fprintf(os.Stderr, "%s %d msg/s, %d in all\n", now.Sub(start).Round(time.Second), matched-lastMatched, matched)
+			lastMatched = matched
+		case <-interrupt:
+			return
+		}
+	}
+}
+
+// printChannelStats prints the messages received and matched per channel.
+func printChannelStats(stats map[string]*channelStats, elapsed time.Duration) {
+	channels := make([]string, 0, len(stats))
+	for ch := range stats {
+		channels = append(channels, ch)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return stats[channels[i]].received > stats[channels[j]].received
+	})
+	rows := make([][]string, len(channels))
+	for i, ch := range channels {
+		cs := stats[ch]
+		rows[i] = []string{ch, strconv.FormatInt(cs.received, 10), strconv.FormatInt(cs.matched, 10),
+			fmt.Sprintf("%.1f", float64(cs.received)/elapsed.Seconds())}
+	}
+	fmt.Fprintln(os.Stderr)
+	if len(rows) == 0 {
+		fmt.Fprintln(os.Stderr, "no messages received")
+		return
+	}
+	fprintTable(os.Stderr, []string{"CHANNEL", "RECEIVED", "MATCHED", "MSG/S"}, rows)
+}
@@ -0,0 +1,46 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package cli
+
+import (
+	"log/syslog"
+	"net/url"
+	"strings"
+)
+
+// syslogSink sends every event as a syslog message, to the local daemon
+// or to syslog://host:port over UDP (syslog+tcp:// for TCP).
+type syslogSink struct {
+	w *syslog.Writer
+}
+
+func newSyslogSink(spec string) (sink, error) {
+	network, raddr := "", ""
+	if spec != "syslog" {
+		u, err := url.Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		network, raddr = "udp", u.Host
+		if u.Scheme == "syslog+tcp" {
+			network = "tcp"
+		}
+		if !strings.Contains(raddr, ":") {
+			raddr += ":514"
+		}
+	}
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_USER, "redis-cli")
+	if err != nil {
+		return nil, err
+	}
+	return &syslogSink{w}, nil
+}
+
+func (s *syslogSink) Write(ev streamEvent, line string) error {
+	return s.w.Info(line)
+}
+
+func (s *syslogSink) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,10 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package cli
+
+import "fmt"
+
+func newSyslogSink(spec string) (sink, error) {
+	return nil, fmt.Errorf("syslog sinks aren't supported on this system")
+}
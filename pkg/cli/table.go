@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// printTable prints rows as aligned columns under a header line.
+func printTable(headers []string, rows [][]string) {
+	fprintTable(os.Stdout, headers, rows)
+}
+
+// fprintTable writes rows as aligned columns under a header line to out.
+func fprintTable(out io.Writer, headers []string, rows [][]string) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+}
+
+// replyPairs turns a flat field/value array reply into a map.
+func replyPairs(reply interface{}) map[string]string {
+	pairs := map[string]string{}
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return pairs
+	}
+	for i := 0; i+1 < len(arr); i += 2 {
+		pairs[fmt.Sprint(arr[i])] = fmt.Sprint(arr[i+1])
+	}
+	return pairs
+}
+
+// replyStrings returns the elements of an array reply as strings.
+func replyStrings(reply interface{}) []string {
+	arr, _ := reply.([]interface{})
+	strs := make([]string, 0, len(arr))
+	for _, v := range arr {
+		strs = append(strs, fmt.Sprint(v))
+	}
+	return strs
+}
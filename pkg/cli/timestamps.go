@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var startTime = time.Now()
+
+// validTimestamps reports whether s is a -timestamps style.
+func validTimestamps(s string) bool {
+	return s == "" || s == "unix" || s == "iso" || s == "relative"
+}
+
+// timestampPrefix returns t formatted in the -timestamps style followed by
+// a space, or nothing when timestamps are off.
+func timestampPrefix(t time.Time) string {
+	switch *timestamps {
+	case "unix":
+		return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', 6, 64) + " "
+	case "iso":
+		return t.Format("2006-01-02T15:04:05.000Z07:00") + " "
+	case "relative":
+		return fmt.Sprintf("+%.3fs ", t.Sub(startTime).Seconds())
+	}
+	return ""
+}
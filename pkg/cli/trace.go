@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis"
+)
+
+// traceFields are the CLIENT INFO fields printed by TRACE: the connection,
+// its two ends, and the state that can differ between pooled connections.
+var traceFields = []string{"id", "addr", "laddr", "db", "name", "user", "flags", "multi", "resp"}
+
+// traceCommand turns tracing on or off for the active connection: it
+// prints, after every reply, the node and pooled connection that ran the
+// command.
+// Usage: TRACE [ON|OFF]
+func traceCommand(args []string) {
+	s := activeSession()
+	if len(args) == 0 {
+		if s.tracing {
+			fmt.Println("trace is on")
+		} else {
+			fmt.Println("trace is off")
+		}
+		return
+	}
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be TRACE [ON|OFF]")
+		return
+	}
+	switch strings.ToLower(args[0]) {
+	case "on":
+		s.tracing = true
+	case "off":
+		s.tracing = false
+	default:
+		fmt.Println("(error) invalid args. Should be TRACE [ON|OFF]")
+	}
+}
+
+// doTraced runs a command on the master of its first key, followed by
+// CLIENT ID and CLIENT INFO in the same pipeline so they are answered by
+// the connection that ran it, and returns the reply with a line describing
+// that connection.
+func doTraced(args []interface{}, plain []string) (interface{}, string, error) {
+	key := ""
+	if keys := commandKeys(plain); len(keys) > 0 {
+		key = keys[0]
+	}
+	node, err := masterForKey(key)
+	if err != nil {
+		r, err := client.Do(args...).Result()
+		return r, fmt.Sprintf("(trace) node unknown: %s", err.Error()), err
+	}
+	return traceOn(node, args, false)
+}
+
+// traceOn runs a command on node, after READONLY with readOnly, with the
+// CLIENT ID and CLIENT INFO of the connection it used.
+func traceOn(node *redis.Client, args []interface{}, readOnly bool) (interface{}, string, error) {
+	do := redis.NewCmd(args...)
+	id := redis.NewIntCmd("client", "id")
+	info := redis.NewStringCmd("client", "info")
+	// the errors are those of the commands, checked one by one
+	node.Pipelined(func(pipe redis.Pipeliner) error {
+		if readOnly {
+			pipe.Process(redis.NewStatusCmd("readonly"))
+		}
+		pipe.Process(do)
+		pipe.Process(id)
+		pipe.Process(info)
+		return nil
+	})
+	r, err := do.Result()
+	return r, traceLine(node.Options().Addr, id, info), err
+}
+
+// masterForKey returns the client of the master serving key, or of any
+// master when key is empty.
+func masterForKey(key string) (*redis.Client, error) {
+	want := ""
+	if clusterEnabled && key != "" {
+		nodes, err := fetchClusterNodes()
+		if err != nil {
+			return nil, err
+		}
+		owner := slotOwner(nodes, keySlot(key))
+		if owner == nil {
+			return nil, fmt.Errorf("slot %d is not served by any node", keySlot(key))
+		}
+		want = owner.Addr
+	}
+
+	var mu sync.Mutex
+	var found *redis.Client
+	err := client.ForEachMaster(func(c *redis.Client) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if found == nil && (want == "" || c.Options().Addr == want) {
+			found = c
+		}
+		return nil
+	})
+	if err == nil && found == nil {
+		err = fmt.Errorf("no client for %s", want)
+	}
+	return found, err
+}
+
+// traceLine describes the connection of a traced command, by its CLIENT
+// INFO, or its CLIENT ID before Redis 6.2.
+func traceLine(node string, id *redis.IntCmd, info *redis.StringCmd) string {
+	trace := "(trace) node " + node
+	if info.Err() == nil {
+		fields := map[string]string{}
+		for _, kv := range strings.Fields(info.Val()) {
+			if i := strings.Index(kv, "="); i > 0 {
+				fields[kv[:i]] = kv[i+1:]
+			}
+		}
+		for _, f := range traceFields {
+			if v, ok := fields[f]; ok {
+				trace += fmt.Sprintf(" %s=%s", f, v)
+			}
+		}
+		return trace
+	}
+	if id.Err() == nil {
+		return fmt.Sprintf("%s id=%d", trace, id.Val())
+	}
+	return fmt.Sprintf("%s, CLIENT ID failed: %s", trace, id.Err().Error())
+}
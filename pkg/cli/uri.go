@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// connURI returns the current connection as a redis URI. The password is
+// masked unless withPassword is set.
+func connURI(withPassword bool) string {
+	u := &url.URL{}
+	if len(*socket) > 0 {
+		u.Scheme = "unix"
+		u.Path = *socket
+		if *dbn > 0 {
+			u.RawQuery = "db=" + strconv.Itoa(*dbn)
+		}
+	} else {
+		u.Scheme = "redis"
+		if *useTLS {
+			u.Scheme = "rediss"
+		}
+		u.Host = addr()
+		u.Path = "/" + strconv.Itoa(*dbn)
+	}
+
+	if *auth != "" {
+		if withPassword {
+			u.User = url.UserPassword(*user, *auth)
+		} else {
+			u.User = url.UserPassword(*user, "******")
+		}
+	} else if *user != "" {
+		u.User = url.User(*user)
+	}
+	return u.String()
+}
+
+// printURI prints the current connection as a redis URI.
+// Usage: URI [--with-password]
+func printURI(args []string) {
+	withPassword := false
+	for _, arg := range args {
+		if strings.ToLower(arg) != "--with-password" {
+			fmt.Println("(error) invalid args. Should be URI [--with-password]")
+			return
+		}
+		withPassword = true
+	}
+	fmt.Println(connURI(withPassword))
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// serverVersion is the redis_version of the connected server, empty when
+// it could not be detected.
+var serverVersion string
+
+// clusterEnabled reports whether the connected server runs in cluster
+// mode.
+var clusterEnabled bool
+
+// detectServerVersion records the version of the connected server, and
+// whether it runs in cluster mode.
+func detectServerVersion() {
+	serverVersion, clusterEnabled = "", false
+	srv, err := fetchInfo()
+	if err != nil {
+		return
+	}
+	serverVersion = srv.Get("redis_version")
+	clusterEnabled = srv.Get("cluster_enabled") == "1"
+}
+
+// compareVersions compares two dotted version strings, returning -1, 0
+// or 1.
+func compareVersions(a, b string) int {
+	as := strings.Split(a, ".")
+	bs := strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+	}
+	return 0
+}
+
+// commandSinceVersion returns the name and the version that introduced the
+// command in cmds, looking at subcommands first.
+func commandSinceVersion(cmds []string) (string, string) {
+	if d, ok := lookupCommandDoc(cmds); ok {
+		return d.Name, d.Since
+	}
+	return strings.ToUpper(cmds[0]), ""
+}
+
+// warnIncompatible prints a warning when cmds is not available in the
+// version of the connected server. The command is still sent.
+func warnIncompatible(cmds []string) {
+	if serverVersion == "" || len(cmds) == 0 {
+		return
+	}
+	name, since := commandSinceVersion(cmds)
+	if since != "" && compareVersions(serverVersion, since) < 0 {
+		fmt.Printf("Warning: %s is available since Redis %s, the server runs %s\n", name, since, serverVersion)
+	}
+	if strings.ToLower(cmds[0]) == "info" && len(cmds) > 2 && compareVersions(serverVersion, "7.0") < 0 {
+		fmt.Printf("Warning: INFO with several sections is available since Redis 7.0, the server runs %s\n", serverVersion)
+	}
+}
+
+// deprecation describes a deprecated command and what to use instead.
+type deprecation struct {
+	since       string
+	replacement string
+}
+
+// deprecatedCommands lists the commands deprecated by newer Redis versions.
+var deprecatedCommands = map[string]deprecation{
+	"BRPOPLPUSH":        {"6.2.0", "BLMOVE source destination RIGHT LEFT timeout"},
+	"CLUSTER SLAVES":    {"5.0.0", "CLUSTER REPLICAS"},
+	"CLUSTER SLOTS":     {"7.0.0", "CLUSTER SHARDS"},
+	"GEORADIUS":         {"6.2.0", "GEOSEARCH with BYRADIUS, or GEOSEARCHSTORE"},
+	"GEORADIUSBYMEMBER": {"6.2.0", "GEOSEARCH with FROMMEMBER and BYRADIUS, or GEOSEARCHSTORE"},
+	"GETSET":            {"6.2.0", "SET key value GET"},
+	"HMSET":             {"4.0.0", "HSET, which takes several fields too"},
+	"PSETEX":            {"2.6.12", "SET key value PX milliseconds"},
+	"RPOPLPUSH":         {"6.2.0", "LMOVE source destination RIGHT LEFT"},
+	"SETEX":             {"2.6.12", "SET key value EX seconds"},
+	"SETNX":             {"2.6.12", "SET key value NX"},
+	"SLAVEOF":           {"5.0.0", "REPLICAOF"},
+	"SUBSTR":            {"2.0.0", "GETRANGE"},
+	"ZRANGEBYLEX":       {"6.2.0", "ZRANGE key min max BYLEX"},
+	"ZRANGEBYSCORE":     {"6.2.0", "ZRANGE key min max BYSCORE"},
+	"ZREVRANGE":         {"6.2.0", "ZRANGE key start stop REV"},
+	"ZREVRANGEBYLEX":    {"6.2.0", "ZRANGE key max min BYLEX REV"},
+	"ZREVRANGEBYSCORE":  {"6.2.0", "ZRANGE key max min BYSCORE REV"},
+}
+
+// deprecationShown records the commands already advised against, so the
+// advice is given once per session.
+var deprecationShown = map[string]bool{}
+
+// warnDeprecated advises the modern equivalent of a command deprecated in
+// the version of the connected server, unless -no-deprecation-warnings is
+// set. The command is still sent.
+func warnDeprecated(cmds []string) {
+	if *noDeprecate || serverVersion == "" || len(cmds) == 0 {
+		return
+	}
+	name := strings.ToUpper(cmds[0])
+	d, ok := deprecatedCommands[name]
+	if len(cmds) > 1 {
+		if sub, found := deprecatedCommands[name+" "+strings.ToUpper(cmds[1])]; found {
+			name, d, ok = name+" "+strings.ToUpper(cmds[1]), sub, true
+		}
+	}
+	if !ok || deprecationShown[name] || compareVersions(serverVersion, d.since) < 0 {
+		return
+	}
+	deprecationShown[name] = true
+	fmt.Printf("Advice: %s is deprecated since Redis %s, use %s instead\n", name, d.since, d.replacement)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// watchLoop clears the screen and calls fn every interval until
+// interrupted with Ctrl-C.
+func watchLoop(interval time.Duration, fn func()) {
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("Every %s, press Ctrl-C to stop\n\n", interval)
+		fn()
+
+		select {
+		case <-ticker.C:
+		case <-interrupt:
+			return
+		}
+	}
+}
+
+// parseWatch removes a "--watch [seconds]" option from args. It returns
+// the remaining arguments and the refresh interval, zero when not
+// watching.
+func parseWatch(args []string, def time.Duration) ([]string, time.Duration, error) {
+	var rest []string
+	var interval time.Duration
+	for i := 0; i < len(args); i++ {
+		if strings.ToLower(args[i]) != "--watch" {
+			rest = append(rest, args[i])
+			continue
+		}
+		interval = def
+		if i+1 < len(args) {
+			if secs, err := strconv.ParseFloat(args[i+1], 64); err == nil {
+				if secs <= 0 {
+					return nil, 0, fmt.Errorf("invalid watch interval %q", args[i+1])
+				}
+				interval = time.Duration(secs * float64(time.Second))
+				i++
+			}
+		}
+	}
+	return rest, interval, nil
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+const (
+	watchHead      = 20
+	highlightStart = "\033[1;33m"
+	highlightEnd   = "\033[0m"
+)
+
+// watchKey polls a key and reprints its value every interval, highlighting
+// the fields that changed since the previous poll.
+// Usage: WATCHKEY key [interval]
+func watchKey(args []string) {
+	if len(args) < 1 || len(args) > 2 {
+		fmt.Println("(error) invalid args. Should be WATCHKEY key [interval]")
+		return
+	}
+	key := lexer.TrimQuotes(args[0])
+	interval := time.Second
+	if len(args) == 2 {
+		secs, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || secs <= 0 {
+			fmt.Printf("(error) invalid interval %q\n", args[1])
+			return
+		}
+		interval = time.Duration(secs * float64(time.Second))
+	}
+	cliConnect()
+
+	var previous map[string]string
+	watchLoop(interval, func() {
+		current, order, err := keySnapshot(key)
+		if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+		for _, field := range order {
+			value := current[field]
+			if previous != nil && previous[field] != value {
+				fmt.Printf("%s%s: %s%s\n", highlightStart, field, value, highlightEnd)
+			} else {
+				fmt.Printf("%s: %s\n", field, value)
+			}
+		}
+		previous = current
+	})
+}
+
+// keySnapshot reads a key according to its type, returning its fields
+// (with type and ttl first) and their display order.
+func keySnapshot(key string) (map[string]string, []string, error) {
+	typ, err := client.Type(key).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	ttl, _ := client.TTL(key).Result()
+
+	snap := map[string]string{"type": typ, "ttl": ttl.String()}
+	order := []string{"type", "ttl"}
+	add := func(field, value string) {
+		snap[field] = value
+		order = append(order, field)
+	}
+
+	switch typ {
+	case "none":
+	case "string":
+		v, err := client.Get(key).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		add("value", strconv.Quote(v))
+	case "hash":
+		h, err := client.HGetAll(key).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		var fields []string
+		for f := range h {
+			fields = append(fields, f)
+		}
+		sort.Strings(fields)
+		for _, f := range fields {
+			add(f, strconv.Quote(h[f]))
+		}
+	case "list":
+		n, _ := client.LLen(key).Result()
+		add("length", strconv.FormatInt(n, 10))
+		items, err := client.LRange(key, 0, watchHead-1).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		for i, v := range items {
+			add(fmt.Sprintf("[%d]", i), strconv.Quote(v))
+		}
+	case "set":
+		n, _ := client.SCard(key).Result()
+		add("cardinality", strconv.FormatInt(n, 10))
+		members, _, err := client.SScan(key, 0, "", watchHead).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		sort.Strings(members)
+		for _, m := range members {
+			add(strconv.Quote(m), "member")
+		}
+	case "zset":
+		n, _ := client.ZCard(key).Result()
+		add("cardinality", strconv.FormatInt(n, 10))
+		members, err := client.ZRangeWithScores(key, 0, watchHead-1).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, z := range members {
+			add(strconv.Quote(fmt.Sprint(z.Member)), strconv.FormatFloat(z.Score, 'g', -1, 64))
+		}
+	case "stream":
+		n, _ := client.XLen(key).Result()
+		add("length", strconv.FormatInt(n, 10))
+		entries, err := client.XRevRangeN(key, "+", "-", watchHead).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, e := range entries {
+			add(e.ID, fmt.Sprint(e.Values))
+		}
+	default:
+		add("value", "(unsupported type)")
+	}
+	return snap, order, nil
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/format"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// serveWeb exposes a minimal web terminal on addr. Only Redis commands
+// and MODE are available, and -allow/-read-only are enforced.
+func serveWeb(listen string) error {
+	cliConnect()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprintf(w, webPage, html.EscapeString(addr()))
+	})
+	mux.HandleFunc("/exec", handleWebExec)
+
+	fmt.Printf("serving %s on http://%s\n", addr(), listen)
+	return http.ListenAndServe(listen, mux)
+}
+
+type webRequest struct {
+	Line string `json:"line"`
+	Mode string `json:"mode"`
+}
+
+type webResponse struct {
+	Output string `json:"output"`
+	Mode   string `json:"mode"`
+}
+
+func handleWebExec(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req webRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := webResponse{Mode: req.Mode}
+	resp.Output = webExec(req.Line, &resp.Mode)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// webExec runs one prompt line for the web terminal and returns what the
+// REPL would have printed. MODE switches the mode of the web session only.
+func webExec(l string, m *string) string {
+	cmds := lexer.Split(l)
+	if len(cmds) == 0 {
+		return ""
+	}
+
+	if strings.ToLower(cmds[0]) == "mode" {
+		if len(cmds) != 2 || !validMode(cmds[1]) {
+			return "invalid args. Should be MODE [raw|std|yaml|gron]"
+		}
+		*m = strings.ToLower(cmds[1])
+		return ""
+	}
+
+	if err := checkAllowed(cmds); err != nil {
+		return fmt.Sprintf("(error) %s", err.Error())
+	}
+
+	args := make([]interface{}, len(cmds))
+	for i, c := range cmds {
+		args[i] = lexer.TrimQuotes(c)
+	}
+	r, err := client.Do(args...).Result()
+	if err == redis.Nil {
+		r, err = nil, nil
+	}
+	if err != nil {
+		return fmt.Sprintf("(error) %s", err.Error())
+	}
+
+	outMode := modes[*m]
+	if outMode != format.Raw {
+		r = structureReply(cmds, r)
+	}
+	return format.Sprint(r, outMode)
+}
+
+const webPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>redis-cli</title>
+<style>
+body { background: #111; color: #ddd; font: 14px monospace; margin: 1em; }
+pre { margin: 0; white-space: pre-wrap; }
+#line { background: transparent; color: inherit; font: inherit; border: none; outline: none; width: 80%%; }
+</style>
+</head>
+<body>
+<pre id="out"></pre>
+<div><span id="prompt">%s&gt; </span><input id="line" autofocus autocomplete="off"></div>
+<script>
+var out = document.getElementById("out"), input = document.getElementById("line");
+var prompt = document.getElementById("prompt").textContent, mode = "std", history = [], pos = 0;
+input.addEventListener("keydown", function(e) {
+  if (e.key === "ArrowUp" && pos > 0) { input.value = history[--pos]; return; }
+  if (e.key === "ArrowDown" && pos < history.length) { input.value = history[++pos] || ""; return; }
+  if (e.key !== "Enter") return;
+  var line = input.value;
+  input.value = "";
+  if (line.trim() !== "") { history.push(line); }
+  pos = history.length;
+  out.textContent += prompt + line + "\n";
+  fetch("exec", {method: "POST", body: JSON.stringify({line: line, mode: mode})})
+    .then(function(r) { return r.json(); })
+    .then(function(r) {
+      mode = r.mode || mode;
+      if (r.output !== "") { out.textContent += r.output + "\n"; }
+      window.scrollTo(0, document.body.scrollHeight);
+    });
+});
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis"
+	"github.com/holys/redis-cli/pkg/lexer"
+)
+
+// xtail follows a stream like tail -f, printing the entries added after
+// it starts until Ctrl-C.
+// Usage: XTAIL key
+func xtail(args []string) {
+	if len(args) != 1 {
+		fmt.Println("(error) invalid args. Should be XTAIL key")
+		return
+	}
+	key := lexer.TrimQuotes(args[0])
+	cliConnect()
+
+	interrupt, stop := interrupted()
+	defer stop()
+	if !*jsonl {
+		fmt.Printf("following %s, press Ctrl-C to stop\n", key)
+	}
+
+	// start after the current last entry rather than at $, so that
+	// entries added between two XREAD calls aren't missed
+	last := "0-0"
+	if entries, err := client.XRevRangeN(key, "+", "-", 1).Result(); err != nil {
+		fmt.Printf("(error) %s\n", err.Error())
+		return
+	} else if len(entries) == 1 {
+		last = entries[0].ID
+	}
+	for {
+		select {
+		case <-interrupt:
+			return
+		default:
+		}
+
+		streams, err := client.XRead(&redis.XReadArgs{
+			Streams: []string{key, last},
+			Block:   time.Second,
+		}).Result()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			fmt.Printf("(error) %s\n", err.Error())
+			return
+		}
+
+		for _, s := range streams {
+			for _, m := range s.Messages {
+				last = m.ID
+				emitEvent(streamEvent{
+					Time:    time.Now(),
+					Node:    addr(),
+					Source:  "xtail",
+					Channel: key,
+					Payload: map[string]interface{}{"id": m.ID, "values": m.Values},
+				}, fmt.Sprintf("%s %s", m.ID, entryText(m.Values)))
+			}
+		}
+	}
+}
+
+// entryText renders stream entry values as sorted field=value pairs.
+func entryText(values map[string]interface{}) string {
+	fields := make([]string, 0, len(values))
+	for f := range values {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	for i, f := range fields {
+		fields[i] = fmt.Sprintf("%s=%q", f, fmt.Sprint(values[f]))
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/holys/redis-cli/pkg/format"
+)
+
+// structureReply turns the flat field/value arrays of well known commands
+// into format.Map, so that std mode can align them and YAML render them as
+// mappings.
+func structureReply(cmds []string, reply interface{}) interface{} {
+	cmd := strings.ToLower(cmds[0])
+	sub := ""
+	if len(cmds) > 1 {
+		sub = strings.ToLower(cmds[1])
+	}
+
+	switch {
+	case cmd == "hgetall" || cmd == "hello" || (cmd == "config" && sub == "get") || (cmd == "memory" && sub == "stats"):
+		return pairsReply(reply, false)
+	case cmd == "xinfo":
+		return pairsReply(reply, true)
+	case cmd == "sentinel" && (sub == "masters" || sub == "replicas" || sub == "slaves" || sub == "sentinels"):
+		arr, ok := reply.([]interface{})
+		if !ok {
+			return reply
+		}
+		structured := make([]interface{}, len(arr))
+		for i, v := range arr {
+			structured[i] = pairsReply(v, false)
+		}
+		return structured
+	case cmd == "sentinel" && sub == "master":
+		return pairsReply(reply, false)
+	case cmd == "xrange" || cmd == "xrevrange":
+		arr, ok := reply.([]interface{})
+		if !ok {
+			return reply
+		}
+		entries := make([]interface{}, len(arr))
+		for i, v := range arr {
+			entries[i] = v
+			if e, ok := v.([]interface{}); ok && len(e) == 2 {
+				entries[i] = format.Map{{Key: "id", Value: e[0]}, {Key: "values", Value: pairsReply(e[1], false)}}
+			}
+		}
+		return entries
+	case cmd == "xpending" && len(cmds) == 3:
+		// XPENDING key group: count, smallest and greatest ids, consumers
+		arr, ok := reply.([]interface{})
+		if !ok || len(arr) != 4 {
+			return reply
+		}
+		consumers := format.Map{}
+		list, _ := arr[3].([]interface{})
+		for _, c := range list {
+			if pair, ok := c.([]interface{}); ok && len(pair) == 2 {
+				consumers = append(consumers, format.Pair{Key: fmt.Sprint(pair[0]), Value: pair[1]})
+			}
+		}
+		return format.Map{
+			{Key: "pending", Value: arr[0]},
+			{Key: "min-id", Value: arr[1]},
+			{Key: "max-id", Value: arr[2]},
+			{Key: "consumers", Value: consumers},
+		}
+	case cmd == "client" && (sub == "info" || sub == "list"):
+		s, ok := reply.(string)
+		if !ok {
+			return reply
+		}
+		var clients []interface{}
+		for _, l := range strings.Split(strings.TrimSpace(s), "\n") {
+			clients = append(clients, clientFields(l))
+		}
+		if sub == "info" && len(clients) == 1 {
+			return clients[0]
+		}
+		return clients
+	}
+	return reply
+}
+
+// pairsReply returns reply as a format.Map when it is an array of
+// alternating string fields and values, recursing into the values when
+// asked to.
+func pairsReply(reply interface{}, recursive bool) interface{} {
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return reply
+	}
+
+	isPairs := len(arr)%2 == 0 && len(arr) > 0
+	for i := 0; isPairs && i < len(arr); i += 2 {
+		_, isPairs = arr[i].(string)
+	}
+	if !isPairs {
+		if !recursive {
+			return reply
+		}
+		structured := make([]interface{}, len(arr))
+		for i, v := range arr {
+			structured[i] = pairsReply(v, true)
+		}
+		return structured
+	}
+
+	m := make(format.Map, 0, len(arr)/2)
+	for i := 0; i < len(arr); i += 2 {
+		v := arr[i+1]
+		if recursive {
+			v = pairsReply(v, true)
+		}
+		m = append(m, format.Pair{Key: arr[i].(string), Value: v})
+	}
+	return m
+}
+
+// clientFields parses a CLIENT LIST line: id=3 addr=127.0.0.1:52110 ...
+func clientFields(l string) format.Map {
+	var m format.Map
+	for _, kv := range strings.Fields(l) {
+		if i := strings.Index(kv, "="); i > 0 {
+			m = append(m, format.Pair{Key: kv[:i], Value: kv[i+1:]})
+		} else {
+			m = append(m, format.Pair{Key: kv, Value: nil})
+		}
+	}
+	return m
+}
@@ -0,0 +1,162 @@
+// Package conn builds the clients redis-cli connects with.
+package conn
+
+import (
+	"crypto/tls"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// Options describes how to reach and authenticate to a server.
+type Options struct {
+	Addr     string
+	Username string
+	Password string
+	TLS      bool
+	// DB is the database every connection selects.
+	DB int
+}
+
+// Check, when set, is asked about every command before it is sent to a
+// server, on any client built here, and refuses it with an error. This
+// is where -read-only and -allow hold for all commands, including those
+// redis-cli sends on its own.
+var Check func(args []interface{}) error
+
+// New returns a client for the server described by opt.
+func New(opt Options) *redis.ClusterClient {
+	clusterOpt := &redis.ClusterOptions{
+		Addrs:        []string{opt.Addr},
+		Password:     opt.Password,
+		PoolSize:     3,
+		DialTimeout:  time.Second * 10,
+		ReadTimeout:  time.Second * 10,
+		WriteTimeout: time.Second * 10,
+	}
+	if opt.TLS {
+		clusterOpt.TLSConfig = &tls.Config{}
+	}
+
+	// go-redis only knows about the password and has no database for
+	// clusters, so ACL users authenticate themselves and the database is
+	// selected on every new connection.
+	if opt.Username != "" || opt.DB != 0 {
+		if opt.Username != "" {
+			clusterOpt.Password = ""
+		}
+		clusterOpt.OnConnect = func(cn *redis.Conn) error {
+			if opt.Username != "" {
+				if err := cn.Process(redis.NewStatusCmd("AUTH", opt.Username, opt.Password)); err != nil {
+					return err
+				}
+			}
+			if opt.DB != 0 {
+				return cn.Process(redis.NewStatusCmd("SELECT", opt.DB))
+			}
+			return nil
+		}
+	}
+	// commands go through the clients of the nodes, but pipelines are
+	// written to their connections directly
+	clusterOpt.OnNewNode = guard
+	c := redis.NewClusterClient(clusterOpt)
+	c.WrapProcessPipeline(guardPipeline)
+	return c
+}
+
+// NewSingle returns a client talking to the single server described by
+// opt, for servers that are not part of a cluster such as Sentinels.
+func NewSingle(opt Options) *redis.Client {
+	singleOpt := &redis.Options{
+		Addr:         opt.Addr,
+		Password:     opt.Password,
+		DB:           opt.DB,
+		PoolSize:     3,
+		DialTimeout:  time.Second * 10,
+		ReadTimeout:  time.Second * 10,
+		WriteTimeout: time.Second * 10,
+	}
+	if opt.TLS {
+		singleOpt.TLSConfig = &tls.Config{}
+	}
+	if opt.Username != "" {
+		singleOpt.Password = ""
+		singleOpt.OnConnect = func(cn *redis.Conn) error {
+			return cn.Process(redis.NewStatusCmd("AUTH", opt.Username, opt.Password))
+		}
+	}
+	c := redis.NewClient(singleOpt)
+	guard(c)
+	return c
+}
+
+// guard makes c ask Check about its commands.
+func guard(c *redis.Client) {
+	c.WrapProcess(func(process func(redis.Cmder) error) func(redis.Cmder) error {
+		return func(cmd redis.Cmder) error {
+			if Check != nil {
+				if err := Check(cmd.Args()); err != nil {
+					refuse(cmd, err)
+					return err
+				}
+			}
+			return process(cmd)
+		}
+	})
+	c.WrapProcessPipeline(guardPipeline)
+}
+
+// guardPipeline asks Check about the commands of a pipeline, and refuses
+// all of them when one is refused, so a transaction is never half sent.
+func guardPipeline(process func([]redis.Cmder) error) func([]redis.Cmder) error {
+	return func(cmds []redis.Cmder) error {
+		if Check != nil {
+			for _, cmd := range cmds {
+				if err := Check(cmd.Args()); err != nil {
+					for _, cmd := range cmds {
+						refuse(cmd, err)
+					}
+					return err
+				}
+			}
+		}
+		return process(cmds)
+	}
+}
+
+// refuse makes err the result of cmd, as if the server had replied with
+// it. go-redis keeps the error of a command to itself, so it is replaced
+// by a command holding err.
+func refuse(cmd redis.Cmder, err error) {
+	switch c := cmd.(type) {
+	case *redis.Cmd:
+		*c = *redis.NewCmdResult(nil, err)
+	case *redis.SliceCmd:
+		*c = *redis.NewSliceResult(nil, err)
+	case *redis.StatusCmd:
+		*c = *redis.NewStatusResult("", err)
+	case *redis.IntCmd:
+		*c = *redis.NewIntResult(0, err)
+	case *redis.DurationCmd:
+		*c = *redis.NewDurationResult(0, err)
+	case *redis.BoolCmd:
+		*c = *redis.NewBoolResult(false, err)
+	case *redis.StringCmd:
+		*c = *redis.NewStringResult("", err)
+	case *redis.FloatCmd:
+		*c = *redis.NewFloatResult(0, err)
+	case *redis.StringSliceCmd:
+		*c = *redis.NewStringSliceResult(nil, err)
+	case *redis.BoolSliceCmd:
+		*c = *redis.NewBoolSliceResult(nil, err)
+	case *redis.StringStringMapCmd:
+		*c = *redis.NewStringStringMapResult(nil, err)
+	case *redis.StringIntMapCmd:
+		*c = *redis.NewStringIntMapCmdResult(nil, err)
+	case *redis.ZSliceCmd:
+		*c = *redis.NewZSliceCmdResult(nil, err)
+	case *redis.ScanCmd:
+		*c = *redis.NewScanCmdResult(nil, 0, err)
+	}
+}
@@ -0,0 +1,189 @@
+// Package format renders Redis replies the way redis-cli prints them.
+package format
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Mode selects how replies are rendered.
+type Mode int
+
+const (
+	// Std renders replies like the interactive redis-cli.
+	Std Mode = iota
+	// Raw renders replies without type hints or quoting.
+	Raw
+	// YAML renders replies as YAML, field/value replies as mappings.
+	YAML
+	// Gron renders replies as one "path = value" line per leaf.
+	Gron
+)
+
+// Markers printed for replies without content of their own: std mode
+// uses NilStd and EmptyArrayStd, raw mode NilRaw and EmptyStringRaw.
+var (
+	NilStd         = "(nil)"
+	EmptyArrayStd  = "(empty array)"
+	NilRaw         = ""
+	EmptyStringRaw = ""
+)
+
+// Array display settings.
+var (
+	// Counters numbers the elements of arrays in std mode: 1), 2) ...
+	Counters = true
+	// Indent is the width of a nesting level, and of the counters column.
+	Indent = 4
+	// IndexPaths labels elements with their full index path in std
+	// mode, e.g. 2.1) for the first element of the second one.
+	IndexPaths = false
+)
+
+// Fprint writes reply to w, indented for the given nesting level.
+func Fprint(w io.Writer, level int, reply interface{}, mode Mode) {
+	switch mode {
+	case Std:
+		fprintStd(w, level, reply)
+	case Raw:
+		fprintRaw(w, level, reply)
+	case YAML:
+		fprintYAML(w, reply)
+	case Gron:
+		fprintGron(w, reply)
+	default:
+		fprintStd(w, level, reply)
+	}
+}
+
+// Sprint returns reply rendered as Fprint would write it.
+func Sprint(reply interface{}, mode Mode) string {
+	var b strings.Builder
+	Fprint(&b, 0, reply, mode)
+	return b.String()
+}
+
+func fprintStd(w io.Writer, level int, reply interface{}) {
+	fprintStdAt(w, level*Indent, nil, reply)
+}
+
+// fprintStdAt writes reply with continuation lines indented to col. path
+// holds the 1-based indexes of the arrays reply is nested in.
+func fprintStdAt(w io.Writer, col int, path []int, reply interface{}) {
+	switch reply := reply.(type) {
+	case int64:
+		fmt.Fprintf(w, "(integer) %d", reply)
+	case string:
+		fmt.Fprintf(w, "%s", safeString(reply))
+	case []byte:
+		fmt.Fprintf(w, "%q", reply)
+	case nil:
+		fmt.Fprintf(w, "%s", NilStd)
+	case Map:
+		if len(reply) == 0 {
+			fmt.Fprintf(w, "%s", EmptyArrayStd)
+		}
+		width := 0
+		for _, p := range reply {
+			if len(p.Key) > width {
+				width = len(p.Key)
+			}
+		}
+		for i, p := range reply {
+			if i != 0 {
+				fmt.Fprintf(w, "%s", strings.Repeat(" ", col))
+			}
+			fmt.Fprintf(w, "%-*s", width+2, p.Key+":")
+
+			fprintStdAt(w, col+width+2, path, p.Value)
+			if i != len(reply)-1 {
+				fmt.Fprintf(w, "\n")
+			}
+		}
+	case error:
+		fmt.Fprintf(w, "%s\n", reply.Error())
+	case []interface{}:
+		if len(reply) == 0 {
+			fmt.Fprintf(w, "%s", EmptyArrayStd)
+		}
+
+		// labels are padded to the widest one, and at least to Indent
+		// unless they are hidden at the top level
+		labels := make([]string, len(reply))
+		width := 0
+		if Counters || IndexPaths || len(path) > 0 {
+			width = Indent
+		}
+		for i := range reply {
+			if IndexPaths {
+				labels[i] = indexPath(append(path, i+1)) + ") "
+			} else if Counters {
+				labels[i] = fmt.Sprintf("%d) ", i+1)
+			}
+			if len(labels[i]) > width {
+				width = len(labels[i])
+			}
+		}
+
+		for i, v := range reply {
+			if i != 0 {
+				fmt.Fprintf(w, "%s", strings.Repeat(" ", col))
+			}
+			fmt.Fprintf(w, "%-*s", width, labels[i])
+
+			fprintStdAt(w, col+width, append(path, i+1), v)
+			if i != len(reply)-1 {
+				fmt.Fprintf(w, "\n")
+			}
+		}
+	default:
+		fmt.Fprintf(w, "Unknown reply type: %+v", reply)
+	}
+}
+
+// indexPath joins indexes with dots, e.g. 2.1.
+func indexPath(path []int) string {
+	parts := make([]string, len(path))
+	for i, n := range path {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+func fprintRaw(w io.Writer, level int, reply interface{}) {
+	switch reply := reply.(type) {
+	case int64:
+		fmt.Fprintf(w, "%d", reply)
+	case string:
+		if reply == "" {
+			reply = EmptyStringRaw
+		}
+		fmt.Fprintf(w, "%s", reply)
+	case []byte:
+		if len(reply) == 0 {
+			reply = []byte(EmptyStringRaw)
+		}
+		fmt.Fprintf(w, "%s", reply)
+	case nil:
+		fmt.Fprintf(w, "%s", NilRaw)
+	case Map:
+		fprintRaw(w, level, reply.Flat())
+	case error:
+		fmt.Fprintf(w, "%s\n", reply.Error())
+	case []interface{}:
+		for i, v := range reply {
+			if i != 0 {
+				fmt.Fprintf(w, "%s", strings.Repeat(" ", level*Indent))
+			}
+
+			fprintRaw(w, level+1, v)
+			if i != len(reply)-1 {
+				fmt.Fprintf(w, "\n")
+			}
+		}
+	default:
+		fmt.Fprintf(w, "Unknown reply type: %+v", reply)
+	}
+}
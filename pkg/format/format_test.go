@@ -0,0 +1,117 @@
+package format
+
+import "testing"
+
+func TestSprint(t *testing.T) {
+	nested := []interface{}{"a", int64(1), []interface{}{"b", nil}}
+	tests := []struct {
+		reply interface{}
+		mode  Mode
+		want  string
+	}{
+		{nil, Std, "(nil)"},
+		{nil, Raw, ""},
+		{"hi", Std, "hi"},
+		{"hi", Raw, "hi"},
+		{int64(3), Std, "(integer) 3"},
+		{int64(3), Raw, "3"},
+		{[]interface{}{}, Std, "(empty array)"},
+		{[]interface{}{}, Raw, ""},
+		{nested, Std, "1)  a\n2)  (integer) 1\n3)  1)  b\n    2)  (nil)"},
+		{[]interface{}{"a", int64(1), "b"}, Raw, "a\n1\nb"},
+	}
+	for _, tt := range tests {
+		if got := Sprint(tt.reply, tt.mode); got != tt.want {
+			t.Errorf("Sprint(%#v, %d) = %q, want %q", tt.reply, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	defer func(nilStd, emptyStd, nilRaw, emptyRaw string) {
+		NilStd, EmptyArrayStd, NilRaw, EmptyStringRaw = nilStd, emptyStd, nilRaw, emptyRaw
+	}(NilStd, EmptyArrayStd, NilRaw, EmptyStringRaw)
+	NilStd, EmptyArrayStd, NilRaw, EmptyStringRaw = "-", "[]", "NULL", `""`
+
+	tests := []struct {
+		reply interface{}
+		mode  Mode
+		want  string
+	}{
+		{nil, Std, "-"},
+		{[]interface{}{}, Std, "[]"},
+		{[]interface{}{"a", nil}, Std, "1)  a\n2)  -"},
+		{nil, Raw, "NULL"},
+		{"", Raw, `""`},
+	}
+	for _, tt := range tests {
+		if got := Sprint(tt.reply, tt.mode); got != tt.want {
+			t.Errorf("Sprint(%#v, %d) = %q, want %q", tt.reply, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestSprintSettings(t *testing.T) {
+	defer func(counters, paths bool, nilStd string) {
+		Counters, IndexPaths, NilStd = counters, paths, nilStd
+	}(Counters, IndexPaths, NilStd)
+	nested := []interface{}{"a", []interface{}{"b", nil}}
+
+	NilStd = "-"
+	IndexPaths = true
+	if got, want := Sprint(nested, Std), "1)  a\n2)  2.1) b\n    2.2) -"; got != want {
+		t.Errorf("index paths: got %q, want %q", got, want)
+	}
+	IndexPaths = false
+	Counters = false
+	if got, want := Sprint(nested, Std), "a\n    b\n    -"; got != want {
+		t.Errorf("no counters: got %q, want %q", got, want)
+	}
+}
+
+func TestSprintYAML(t *testing.T) {
+	tests := []struct {
+		reply interface{}
+		want  string
+	}{
+		{"plain", "plain"},
+		{"user:1", "user:1"},
+		{"a b", "a b"},
+		{"123", `"123"`},
+		{"yes", `"yes"`},
+		{"", `""`},
+		{"trailing ", `"trailing "`},
+		{"key: value", `"key: value"`},
+		{"key:", `"key:"`},
+		{"a #comment", `"a #comment"`},
+		{nil, "null"},
+		{int64(7), "7"},
+		{[]interface{}{}, "[]"},
+		{[]interface{}{"a", []interface{}{"b", nil}}, "- a\n- - b\n  - null"},
+		{Map{{"name", "k: v"}, {"list", []interface{}{"x"}}, {"empty", Map{}}}, "name: \"k: v\"\nlist:\n  - x\nempty: {}"},
+	}
+	for _, tt := range tests {
+		if got := Sprint(tt.reply, YAML); got != tt.want {
+			t.Errorf("Sprint(%#v, YAML) = %q, want %q", tt.reply, got, tt.want)
+		}
+	}
+}
+
+func TestSprintGron(t *testing.T) {
+	tests := []struct {
+		reply interface{}
+		want  string
+	}{
+		{"a", `reply = "a"`},
+		{nil, "reply = null"},
+		{int64(1), "reply = 1"},
+		{[]interface{}{}, "reply = []"},
+		{[]interface{}{"a", []interface{}{int64(1), nil}}, "reply[0] = \"a\"\nreply[1][0] = 1\nreply[1][1] = null"},
+		{Map{{"name", "x"}, {"last-id", "0-1"}, {"groups", Map{}}}, "reply.name = \"x\"\nreply[\"last-id\"] = \"0-1\"\nreply.groups = {}"},
+	}
+	for _, tt := range tests {
+		if got := Sprint(tt.reply, Gron); got != tt.want {
+			t.Errorf("Sprint(%#v, Gron) = %q, want %q", tt.reply, got, tt.want)
+		}
+	}
+}
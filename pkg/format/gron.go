@@ -0,0 +1,58 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+var gronIdent = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// fprintGron writes one "path = value" line per leaf of reply, e.g.
+// reply[2][1] = "foo", so that nested replies can be grepped and diffed.
+func fprintGron(w io.Writer, reply interface{}) {
+	first := true
+	gronLines("reply", reply, func(path, value string) {
+		if !first {
+			io.WriteString(w, "\n")
+		}
+		first = false
+		fmt.Fprintf(w, "%s = %s", path, value)
+	})
+}
+
+func gronLines(path string, reply interface{}, emit func(path, value string)) {
+	switch reply := reply.(type) {
+	case []interface{}:
+		if len(reply) == 0 {
+			emit(path, "[]")
+		}
+		for i, v := range reply {
+			gronLines(fmt.Sprintf("%s[%d]", path, i), v, emit)
+		}
+	case Map:
+		if len(reply) == 0 {
+			emit(path, "{}")
+		}
+		for _, p := range reply {
+			if gronIdent.MatchString(p.Key) {
+				gronLines(path+"."+p.Key, p.Value, emit)
+			} else {
+				gronLines(fmt.Sprintf("%s[%s]", path, strconv.Quote(p.Key)), p.Value, emit)
+			}
+		}
+	case nil:
+		emit(path, "null")
+	case int64:
+		emit(path, strconv.FormatInt(reply, 10))
+	case string:
+		emit(path, strconv.Quote(reply))
+	case []byte:
+		emit(path, strconv.Quote(string(reply)))
+	case error:
+		emit(path, "error "+strconv.Quote(reply.Error()))
+	default:
+		emit(path, strconv.Quote(fmt.Sprint(reply)))
+	}
+}
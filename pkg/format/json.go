@@ -0,0 +1,27 @@
+package format
+
+// JSONValue converts a reply to a value encoding/json can marshal:
+// bulk strings become strings, integers numbers, nil replies null, arrays
+// arrays, maps objects and errors {"error": "..."} objects.
+func JSONValue(reply interface{}) interface{} {
+	switch reply := reply.(type) {
+	case []byte:
+		return string(reply)
+	case error:
+		return map[string]string{"error": reply.Error()}
+	case []interface{}:
+		values := make([]interface{}, len(reply))
+		for i, v := range reply {
+			values[i] = JSONValue(v)
+		}
+		return values
+	case Map:
+		values := make(map[string]interface{}, len(reply))
+		for _, p := range reply {
+			values[p.Key] = JSONValue(p.Value)
+		}
+		return values
+	default:
+		return reply
+	}
+}
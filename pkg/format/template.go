@@ -0,0 +1,39 @@
+package format
+
+import (
+	"encoding/json"
+	"io"
+	"text/template"
+)
+
+// Funcs are the functions available to templates besides the text/template
+// builtins.
+var Funcs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// ParseTemplate parses a template for FprintTemplate.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("format").Funcs(Funcs).Parse(text)
+}
+
+// FprintTemplate executes t with the reply, converted by JSONValue, as
+// data. Array replies execute it once per element, one line each.
+func FprintTemplate(w io.Writer, t *template.Template, reply interface{}) error {
+	arr, ok := reply.([]interface{})
+	if !ok {
+		return t.Execute(w, JSONValue(reply))
+	}
+	for i, v := range arr {
+		if i != 0 {
+			io.WriteString(w, "\n")
+		}
+		if err := t.Execute(w, JSONValue(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
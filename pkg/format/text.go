@@ -0,0 +1,70 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StringMode selects how std mode prints strings that may not be text.
+type StringMode int
+
+const (
+	// StringsAuto prints text as-is, and escapes strings holding control
+	// characters or invalid UTF-8 so they can't corrupt the terminal.
+	StringsAuto StringMode = iota
+	// StringsHex prints text as-is and other strings as hex bytes.
+	StringsHex
+	// StringsEscape always prints quoted, escaped strings.
+	StringsEscape
+	// StringsAsIs always prints strings as they are.
+	StringsAsIs
+)
+
+// Strings is the StringMode of std mode.
+var Strings = StringsAuto
+
+// ParseStringMode parses auto, hex, escape or as-is.
+func ParseStringMode(s string) (StringMode, error) {
+	switch s {
+	case "auto":
+		return StringsAuto, nil
+	case "hex":
+		return StringsHex, nil
+	case "escape":
+		return StringsEscape, nil
+	case "as-is":
+		return StringsAsIs, nil
+	}
+	return StringsAuto, fmt.Errorf("invalid string mode %q, should be auto, hex, escape or as-is", s)
+}
+
+// isText reports whether s is valid UTF-8 without control characters
+// other than newlines and tabs.
+func isText(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if unicode.IsControl(r) && r != '\n' && r != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// safeString renders s according to Strings.
+func safeString(s string) string {
+	switch {
+	case Strings == StringsAsIs:
+		return s
+	case Strings == StringsEscape:
+		return strconv.Quote(s)
+	case isText(s):
+		return s
+	case Strings == StringsHex:
+		return fmt.Sprintf("(hex) % x", s)
+	}
+	return strconv.Quote(s)
+}
@@ -0,0 +1,124 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Pair is a field and its value.
+type Pair struct {
+	Key   string
+	Value interface{}
+}
+
+// Map is a reply made of field/value pairs, such as HGETALL or XINFO
+// STREAM. Std mode renders it as aligned "field: value" lines and YAML as a
+// mapping, raw mode as the flat array it came from.
+type Map []Pair
+
+// Flat returns m as the flat field/value array Redis replied with.
+func (m Map) Flat() []interface{} {
+	flat := make([]interface{}, 0, 2*len(m))
+	for _, p := range m {
+		flat = append(flat, p.Key, p.Value)
+	}
+	return flat
+}
+
+var (
+	yamlPlain   = regexp.MustCompile(`^[A-Za-z_./@][A-Za-z0-9_ ./:@=,()+-]*$`)
+	yamlSpecial = map[string]bool{
+		"true": true, "false": true, "null": true, "yes": true, "no": true,
+		"on": true, "off": true, "y": true, "n": true, "~": true,
+	}
+)
+
+func fprintYAML(w io.Writer, reply interface{}) {
+	io.WriteString(w, strings.Join(yamlLines(reply), "\n"))
+}
+
+// yamlLines renders reply as unindented YAML lines.
+func yamlLines(reply interface{}) []string {
+	switch reply := reply.(type) {
+	case []interface{}:
+		if len(reply) == 0 {
+			return []string{"[]"}
+		}
+		var lines []string
+		for _, v := range reply {
+			for i, l := range yamlLines(v) {
+				if i == 0 {
+					lines = append(lines, "- "+l)
+				} else {
+					lines = append(lines, "  "+l)
+				}
+			}
+		}
+		return lines
+	case Map:
+		if len(reply) == 0 {
+			return []string{"{}"}
+		}
+		var lines []string
+		for _, p := range reply {
+			sub := yamlLines(p.Value)
+			if !yamlNested(p.Value) {
+				lines = append(lines, yamlScalar(p.Key)+": "+sub[0])
+				continue
+			}
+			lines = append(lines, yamlScalar(p.Key)+":")
+			for _, l := range sub {
+				lines = append(lines, "  "+l)
+			}
+		}
+		return lines
+	case error:
+		return []string{"error: " + yamlScalar(reply.Error())}
+	default:
+		return []string{yamlScalar(reply)}
+	}
+}
+
+// yamlNested reports whether v renders on lines of its own.
+func yamlNested(v interface{}) bool {
+	switch v := v.(type) {
+	case []interface{}:
+		return len(v) > 0
+	case Map:
+		return len(v) > 0
+	case error:
+		return true
+	}
+	return false
+}
+
+func yamlScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case []byte:
+		return yamlString(string(v))
+	case string:
+		return yamlString(v)
+	default:
+		return yamlString(fmt.Sprint(v))
+	}
+}
+
+// yamlString leaves simple strings unquoted and double quotes the ones
+// YAML would read as something else, such as numbers, booleans, mappings
+// ("a: b", "a:") or comments ("a #b").
+func yamlString(s string) string {
+	if yamlPlain.MatchString(s) && !strings.HasSuffix(s, " ") && !yamlSpecial[strings.ToLower(s)] &&
+		!strings.Contains(s, ": ") && !strings.Contains(s, " #") && !strings.HasSuffix(s, ":") {
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return s
+		}
+	}
+	return strconv.Quote(s)
+}
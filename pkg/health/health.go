@@ -0,0 +1,120 @@
+// Package health implements the one-shot probing modes of redis-cli:
+// waiting for a server to come up and Docker healthchecks.
+package health
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/holys/redis-cli/pkg/conn"
+	"github.com/holys/redis-cli/pkg/info"
+)
+
+// Wait retries PING with backoff until the server answers or timeout
+// expires. With ready set, it also waits until the dataset is loaded and,
+// on replicas, the link to the master is up. It returns the process exit
+// code.
+func Wait(w io.Writer, opt conn.Options, timeout time.Duration, ready bool) int {
+	deadline := time.Now().Add(timeout)
+	backoff := 100 * time.Millisecond
+
+	for {
+		err := checkReady(opt, ready)
+		if err == nil {
+			fmt.Fprintf(w, "%s is ready\n", opt.Addr)
+			return 0
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			fmt.Fprintf(w, "(error) %s not ready after %s: %s\n", opt.Addr, timeout, err.Error())
+			return 1
+		}
+		if backoff < remaining {
+			time.Sleep(backoff)
+		} else {
+			time.Sleep(remaining)
+		}
+		if backoff *= 2; backoff > 2*time.Second {
+			backoff = 2 * time.Second
+		}
+	}
+}
+
+// checkReady runs one readiness probe on a fresh connection.
+func checkReady(opt conn.Options, ready bool) error {
+	c := conn.New(opt)
+	defer c.Close()
+
+	if err := c.Do("PING").Err(); err != nil {
+		return err
+	}
+	if !ready {
+		return nil
+	}
+
+	s, err := c.Do("INFO").String()
+	if err != nil {
+		return err
+	}
+	reply := info.Parse(s)
+	if reply.Get("loading") != "0" {
+		return fmt.Errorf("dataset is loading")
+	}
+	if reply.Get("role") == "slave" && reply.Get("master_link_status") != "up" {
+		return fmt.Errorf("master link is %s", reply.Get("master_link_status"))
+	}
+	return nil
+}
+
+// Check probes the server once and writes a one-line summary. It returns
+// 0 when healthy and 1 otherwise, as Docker HEALTHCHECK expects. A
+// non-empty role must match the server role (master or replica), and with
+// replication set a replica must have its master link up.
+func Check(w io.Writer, opt conn.Options, role string, replication bool) int {
+	c := conn.New(opt)
+	defer c.Close()
+
+	start := time.Now()
+	if err := c.Do("PING").Err(); err != nil {
+		fmt.Fprintf(w, "UNHEALTHY %s: %s\n", opt.Addr, err.Error())
+		return 1
+	}
+	latency := time.Since(start)
+
+	if role == "" && !replication {
+		fmt.Fprintf(w, "HEALTHY %s latency=%s\n", opt.Addr, latency)
+		return 0
+	}
+
+	s, err := c.Do("INFO", "replication").String()
+	if err != nil {
+		fmt.Fprintf(w, "UNHEALTHY %s: %s\n", opt.Addr, err.Error())
+		return 1
+	}
+	reply := info.Parse(s)
+
+	actual := reply.Get("role")
+	if role != "" && NormalizeRole(role) != NormalizeRole(actual) {
+		fmt.Fprintf(w, "UNHEALTHY %s: role is %s, expected %s\n", opt.Addr, actual, role)
+		return 1
+	}
+	if replication && actual == "slave" && reply.Get("master_link_status") != "up" {
+		fmt.Fprintf(w, "UNHEALTHY %s: master link is %s\n", opt.Addr, reply.Get("master_link_status"))
+		return 1
+	}
+
+	fmt.Fprintf(w, "HEALTHY %s role=%s latency=%s\n", opt.Addr, actual, latency)
+	return 0
+}
+
+// NormalizeRole maps the replica role names used by Redis to one name.
+func NormalizeRole(role string) string {
+	role = strings.ToLower(role)
+	if role == "slave" {
+		return "replica"
+	}
+	return role
+}
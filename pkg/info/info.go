@@ -0,0 +1,107 @@
+// Package info parses the reply of the INFO command.
+package info
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Reply holds a parsed INFO reply: section name (lower case) to the
+// fields of that section.
+type Reply map[string]map[string]string
+
+// Field is a field of an INFO section.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Section is an INFO section with its fields, in the order the server
+// sent them.
+type Section struct {
+	Name   string
+	Fields []Field
+}
+
+// ParseSections parses the text returned by INFO into its sections, keeping
+// the order and the case of the section names.
+func ParseSections(s string) []Section {
+	var sections []Section
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if strings.HasPrefix(l, "#") {
+			sections = append(sections, Section{Name: strings.TrimSpace(strings.TrimPrefix(l, "#"))})
+			continue
+		}
+
+		i := strings.Index(l, ":")
+		if i < 0 {
+			continue
+		}
+		if len(sections) == 0 {
+			sections = append(sections, Section{})
+		}
+		last := &sections[len(sections)-1]
+		last.Fields = append(last.Fields, Field{l[:i], l[i+1:]})
+	}
+	return sections
+}
+
+// Parse parses the text returned by INFO into sections.
+func Parse(s string) Reply {
+	info := Reply{}
+	for _, sec := range ParseSections(s) {
+		if len(sec.Fields) == 0 {
+			continue
+		}
+		name := strings.ToLower(sec.Name)
+		if info[name] == nil {
+			info[name] = map[string]string{}
+		}
+		for _, f := range sec.Fields {
+			info[name][f.Name] = f.Value
+		}
+	}
+	return info
+}
+
+// Get returns the value of field, whatever section it belongs to.
+func (info Reply) Get(field string) string {
+	for _, fields := range info {
+		if v, ok := fields[field]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// Int returns the value of field as an integer, or 0 when it is missing.
+func (info Reply) Int(field string) int64 {
+	n, _ := strconv.ParseInt(info.Get(field), 10, 64)
+	return n
+}
+
+// Float returns the value of field as a float, or 0 when it is missing.
+func (info Reply) Float(field string) float64 {
+	f, _ := strconv.ParseFloat(info.Get(field), 64)
+	return f
+}
+
+// TotalKeys sums the number of keys of every database in the keyspace
+// section.
+func (info Reply) TotalKeys() int64 {
+	var total int64
+	for _, v := range info["keyspace"] {
+		// db0:keys=1,expires=0,avg_ttl=0
+		for _, kv := range strings.Split(v, ",") {
+			if strings.HasPrefix(kv, "keys=") {
+				n, _ := strconv.ParseInt(strings.TrimPrefix(kv, "keys="), 10, 64)
+				total += n
+			}
+		}
+	}
+	return total
+}
@@ -0,0 +1,55 @@
+package info
+
+import (
+	"reflect"
+	"testing"
+)
+
+const reply = "# Server\r\nredis_version:7.2.4\r\nuptime_in_seconds:42\r\n\r\n" +
+	"# Memory\r\nused_memory:1024\r\nmem_fragmentation_ratio:1.25\r\n\r\n" +
+	"# Keyspace\r\ndb0:keys=3,expires=1,avg_ttl=0\r\ndb2:keys=4,expires=0,avg_ttl=0\r\n"
+
+func TestParseSections(t *testing.T) {
+	got := ParseSections(reply)
+	want := []Section{
+		{"Server", []Field{{"redis_version", "7.2.4"}, {"uptime_in_seconds", "42"}}},
+		{"Memory", []Field{{"used_memory", "1024"}, {"mem_fragmentation_ratio", "1.25"}}},
+		{"Keyspace", []Field{{"db0", "keys=3,expires=1,avg_ttl=0"}, {"db2", "keys=4,expires=0,avg_ttl=0"}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// the fields of a reply without section headers, such as that of a
+	// single field, go to an unnamed section
+	got = ParseSections("a:1\nb:x:y\nnot a field\n")
+	want = []Section{{"", []Field{{"a", "1"}, {"b", "x:y"}}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReply(t *testing.T) {
+	r := Parse(reply)
+	if v := r["server"]["redis_version"]; v != "7.2.4" {
+		t.Errorf("server redis_version %q, want 7.2.4", v)
+	}
+	if v := r.Get("used_memory"); v != "1024" {
+		t.Errorf("Get used_memory %q, want 1024", v)
+	}
+	if v := r.Get("missing"); v != "" {
+		t.Errorf("Get missing %q, want nothing", v)
+	}
+	if n := r.Int("uptime_in_seconds"); n != 42 {
+		t.Errorf("Int uptime_in_seconds %d, want 42", n)
+	}
+	if n := r.Int("redis_version"); n != 0 {
+		t.Errorf("Int redis_version %d, want 0", n)
+	}
+	if f := r.Float("mem_fragmentation_ratio"); f != 1.25 {
+		t.Errorf("Float mem_fragmentation_ratio %v, want 1.25", f)
+	}
+	if n := r.TotalKeys(); n != 7 {
+		t.Errorf("TotalKeys %d, want 7", n)
+	}
+}
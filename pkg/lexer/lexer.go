@@ -0,0 +1,25 @@
+// Package lexer splits a line typed at the redis-cli prompt into
+// command arguments.
+package lexer
+
+import (
+	"regexp"
+)
+
+var argRegexp = regexp.MustCompile(`'.*?'|".*?"|\S+`)
+
+// Split splits line into arguments. Quoted arguments are kept with their
+// quotes, use TrimQuotes to remove them.
+func Split(line string) []string {
+	return argRegexp.FindAllString(line, -1)
+}
+
+// TrimQuotes removes the quoting around an argument, that is the outer
+// pair of matching single or double quotes Split kept. Quotes inside the
+// argument, or not matched at the other end, are part of the value.
+func TrimQuotes(arg string) string {
+	if len(arg) >= 2 && (arg[0] == '"' || arg[0] == '\'') && arg[len(arg)-1] == arg[0] {
+		return arg[1 : len(arg)-1]
+	}
+	return arg
+}
@@ -0,0 +1,48 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"GET k", []string{"GET", "k"}},
+		{"  SET   k  v ", []string{"SET", "k", "v"}},
+		{`SET k "hello world"`, []string{"SET", "k", `"hello world"`}},
+		{`SET k 'it is'`, []string{"SET", "k", `'it is'`}},
+		{`SET "a b" "c d"`, []string{"SET", `"a b"`, `"c d"`}},
+		{`SET k "" x`, []string{"SET", "k", `""`, "x"}},
+		{"SET\tk\tv", []string{"SET", "k", "v"}},
+	}
+	for _, tt := range tests {
+		if got := Split(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestTrimQuotes(t *testing.T) {
+	tests := []struct {
+		arg, want string
+	}{
+		{"plain", "plain"},
+		{`"quoted"`, "quoted"},
+		{"'quoted'", "quoted"},
+		{`""`, ""},
+		{`"`, `"`},
+		{`"mixed'`, `"mixed'`},
+		{`"a"b"`, `a"b`},
+		{`'"inner"'`, `"inner"`},
+	}
+	for _, tt := range tests {
+		if got := TrimQuotes(tt.arg); got != tt.want {
+			t.Errorf("TrimQuotes(%q) = %q, want %q", tt.arg, got, tt.want)
+		}
+	}
+}
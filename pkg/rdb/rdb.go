@@ -0,0 +1,650 @@
+// Package rdb reads the keys of an RDB file, as written by SAVE and
+// BGSAVE, without loading the values: for every key it reports the
+// database, the type, the number of elements, the size of the value in
+// the file and the expiry. It knows the formats up to RDB version 12
+// (Redis 7.4), modules included as long as they use the self-describing
+// module encoding of Redis 5 and later.
+package rdb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+)
+
+// Entry is a key of the file.
+type Entry struct {
+	DB   int
+	Key  string
+	Type string
+	// Elements is the number of elements of the value, 1 for strings and
+	// -1 when the encoding doesn't say.
+	Elements int64
+	// Bytes is the size of the value in the file, compressed or not.
+	Bytes int64
+	// Expiry is zero for keys without one.
+	Expiry time.Time
+}
+
+// Decoder reads the keys of an RDB file one by one.
+type Decoder struct {
+	r       *bufio.Reader
+	n       int64
+	db      int
+	Version int
+	// Aux holds the auxiliary fields read so far, such as redis-ver and
+	// ctime, which come before the keys.
+	Aux map[string]string
+}
+
+// NewDecoder reads the header of an RDB file.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	d := &Decoder{r: bufio.NewReaderSize(r, 64<<10), Aux: map[string]string{}}
+	magic := make([]byte, 9)
+	if err := d.read(magic); err != nil {
+		return nil, fmt.Errorf("not an RDB file: %v", err)
+	}
+	if string(magic[:5]) != "REDIS" {
+		return nil, errors.New("not an RDB file: no REDIS signature")
+	}
+	v, err := strconv.Atoi(string(magic[5:]))
+	if err != nil {
+		return nil, fmt.Errorf("not an RDB file: invalid version %q", magic[5:])
+	}
+	d.Version = v
+	return d, nil
+}
+
+// Offset returns the number of bytes read so far.
+func (d *Decoder) Offset() int64 {
+	return d.n
+}
+
+// opcodes of the file, besides the value types
+const (
+	opSlotInfo     = 244
+	opFunction2    = 245
+	opFunctionPre  = 246
+	opModuleAux    = 247
+	opIdle         = 248
+	opFreq         = 249
+	opAux          = 250
+	opResizeDB     = 251
+	opExpireTimeMS = 252
+	opExpireTime   = 253
+	opSelectDB     = 254
+	opEOF          = 255
+)
+
+// Next returns the next key of the file, or io.EOF after the last one.
+func (d *Decoder) Next() (*Entry, error) {
+	var expiry time.Time
+	for {
+		op, err := d.readByte()
+		if err != nil {
+			return nil, d.unexpected(err)
+		}
+		switch op {
+		case opEOF:
+			// the checksum that follows isn't verified
+			return nil, io.EOF
+		case opSelectDB:
+			db, err := d.readLength()
+			if err != nil {
+				return nil, d.unexpected(err)
+			}
+			d.db = int(db)
+		case opExpireTime:
+			b := make([]byte, 4)
+			if err := d.read(b); err != nil {
+				return nil, d.unexpected(err)
+			}
+			expiry = time.Unix(int64(binary.LittleEndian.Uint32(b)), 0)
+		case opExpireTimeMS:
+			b := make([]byte, 8)
+			if err := d.read(b); err != nil {
+				return nil, d.unexpected(err)
+			}
+			ms := int64(binary.LittleEndian.Uint64(b))
+			expiry = time.Unix(0, ms*int64(time.Millisecond))
+		case opResizeDB:
+			err = d.skipLengths(2)
+		case opSlotInfo:
+			err = d.skipLengths(3)
+		case opAux:
+			var k, v []byte
+			if k, err = d.readString(); err == nil {
+				if v, err = d.readString(); err == nil {
+					d.Aux[string(k)] = string(v)
+				}
+			}
+		case opFreq:
+			_, err = d.readByte()
+		case opIdle:
+			_, err = d.readLength()
+		case opModuleAux:
+			// module id, when opcode, when
+			if err = d.skipLengths(3); err == nil {
+				err = d.skipModule()
+			}
+		case opFunction2:
+			err = d.skipString()
+		case opFunctionPre:
+			return nil, errors.New("functions saved by a Redis 7.0 release candidate are not supported")
+		default:
+			key, err := d.readString()
+			if err != nil {
+				return nil, d.unexpected(err)
+			}
+			start := d.n
+			typ, elements, err := d.skipValue(op)
+			if err != nil {
+				return nil, d.unexpected(err)
+			}
+			return &Entry{DB: d.db, Key: string(key), Type: typ, Elements: elements, Bytes: d.n - start, Expiry: expiry}, nil
+		}
+		if err != nil {
+			return nil, d.unexpected(err)
+		}
+	}
+}
+
+// unexpected adds the offset to an error, EOF being one in the middle of
+// the file.
+func (d *Decoder) unexpected(err error) error {
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("offset %d: %v", d.n, err)
+}
+
+// skipValue reads a value of type t, returning the type name and the
+// number of elements.
+func (d *Decoder) skipValue(t byte) (string, int64, error) {
+	switch t {
+	case 0:
+		return "string", 1, d.skipString()
+	case 1, 2:
+		n, err := d.readLength()
+		if err == nil {
+			err = d.skipStrings(n)
+		}
+		return [...]string{"", "list", "set"}[t], int64(n), err
+	case 3:
+		n, err := d.readLength()
+		for i := uint64(0); i < n && err == nil; i++ {
+			if err = d.skipString(); err == nil {
+				// the score is a string of up to 252 bytes, 253 to 255
+				// are nan and the infinities
+				var l byte
+				if l, err = d.readByte(); err == nil && l < 253 {
+					err = d.skip(int64(l))
+				}
+			}
+		}
+		return "zset", int64(n), err
+	case 4:
+		n, err := d.readLength()
+		if err == nil {
+			err = d.skipStrings(2 * n)
+		}
+		return "hash", int64(n), err
+	case 5:
+		n, err := d.readLength()
+		for i := uint64(0); i < n && err == nil; i++ {
+			if err = d.skipString(); err == nil {
+				err = d.skip(8)
+			}
+		}
+		return "zset", int64(n), err
+	case 6:
+		return "", 0, errors.New("modules saved before Redis 5 are not supported")
+	case 7:
+		id, err := d.readLength()
+		if err == nil {
+			err = d.skipModule()
+		}
+		return "module:" + moduleName(id), -1, err
+	case 9:
+		b, err := d.readString()
+		n := int64(-1)
+		// the first byte of a zipmap counts its pairs, up to 253
+		if len(b) > 0 && b[0] < 254 {
+			n = int64(b[0])
+		}
+		return "hash", n, err
+	case 10, 12, 13:
+		b, err := d.readString()
+		n := ziplistLen(b)
+		if t != 10 && n > 0 {
+			n /= 2
+		}
+		return [...]string{10: "list", 12: "zset", 13: "hash"}[t], n, err
+	case 11:
+		b, err := d.readString()
+		n := int64(-1)
+		if len(b) >= 8 {
+			n = int64(binary.LittleEndian.Uint32(b[4:8]))
+		}
+		return "set", n, err
+	case 14, 18:
+		nodes, err := d.readLength()
+		total := int64(0)
+		for i := uint64(0); i < nodes && err == nil; i++ {
+			// quicklist 2 nodes are plain (1) values or listpacks (2)
+			container := uint64(2)
+			if t == 18 {
+				if container, err = d.readLength(); err != nil {
+					break
+				}
+			}
+			var b []byte
+			if b, err = d.readString(); err != nil {
+				break
+			}
+			n := int64(1)
+			if t == 14 {
+				n = ziplistLen(b)
+			} else if container != 1 {
+				n = listpackLen(b)
+			}
+			if n < 0 || total < 0 {
+				total = -1
+			} else {
+				total += n
+			}
+		}
+		return "list", total, err
+	case 15, 19, 21:
+		n, err := d.skipStream(t)
+		return "stream", n, err
+	case 16, 17, 20:
+		b, err := d.readString()
+		n := listpackLen(b)
+		if t != 20 && n > 0 {
+			n /= 2
+		}
+		return [...]string{16: "hash", 17: "zset", 20: "set"}[t], n, err
+	case 22, 24:
+		// hashes with field expiration, the GA format adds the minimum
+		// expire time
+		var err error
+		if t == 24 {
+			err = d.skip(8)
+		}
+		var n uint64
+		if err == nil {
+			n, err = d.readLength()
+		}
+		for i := uint64(0); i < n && err == nil; i++ {
+			if _, err = d.readLength(); err == nil {
+				err = d.skipStrings(2)
+			}
+		}
+		return "hash", int64(n), err
+	case 23, 25:
+		var err error
+		if t == 25 {
+			err = d.skip(8)
+		}
+		var b []byte
+		if err == nil {
+			b, err = d.readString()
+		}
+		// field, value and TTL triples
+		n := listpackLen(b)
+		if n > 0 {
+			n /= 3
+		}
+		return "hash", n, err
+	}
+	return "", 0, fmt.Errorf("unknown value type %d", t)
+}
+
+// skipStream reads a stream of type t: 15 up to Redis 6.2, 19 for 7.0
+// and 21 for 7.2 consumers with an active time.
+func (d *Decoder) skipStream(t byte) (int64, error) {
+	nodes, err := d.readLength()
+	if err != nil {
+		return 0, err
+	}
+	// master ID and listpack of every node
+	if err := d.skipStrings(2 * nodes); err != nil {
+		return 0, err
+	}
+	length, err := d.readLength()
+	if err != nil {
+		return 0, err
+	}
+	// last ID, and for 7.0 the first ID, max deleted ID and entries added
+	ids := 2
+	if t >= 19 {
+		ids += 5
+	}
+	if err := d.skipLengths(ids); err != nil {
+		return 0, err
+	}
+
+	groups, err := d.readLength()
+	for g := uint64(0); g < groups && err == nil; g++ {
+		if err = d.skipString(); err != nil {
+			break
+		}
+		// last ID, and for 7.0 entries read
+		ids := 2
+		if t >= 19 {
+			ids++
+		}
+		if err = d.skipLengths(ids); err != nil {
+			break
+		}
+		var pel uint64
+		if pel, err = d.readLength(); err != nil {
+			break
+		}
+		// ID, delivery time, delivery count
+		for i := uint64(0); i < pel && err == nil; i++ {
+			if err = d.skip(16 + 8); err == nil {
+				_, err = d.readLength()
+			}
+		}
+		var consumers uint64
+		if err == nil {
+			consumers, err = d.readLength()
+		}
+		for c := uint64(0); c < consumers && err == nil; c++ {
+			if err = d.skipString(); err != nil {
+				break
+			}
+			times := int64(8)
+			if t >= 21 {
+				times += 8
+			}
+			if err = d.skip(times); err != nil {
+				break
+			}
+			var cpel uint64
+			if cpel, err = d.readLength(); err == nil {
+				err = d.skip(16 * int64(cpel))
+			}
+		}
+	}
+	return int64(length), err
+}
+
+// module value opcodes
+const (
+	moduleEOF = iota
+	moduleSInt
+	moduleUInt
+	moduleFloat
+	moduleDouble
+	moduleString
+)
+
+// skipModule reads a module value up to its EOF opcode.
+func (d *Decoder) skipModule() error {
+	for {
+		op, err := d.readLength()
+		if err != nil {
+			return err
+		}
+		switch op {
+		case moduleEOF:
+			return nil
+		case moduleSInt, moduleUInt:
+			_, err = d.readLength()
+		case moduleFloat:
+			err = d.skip(4)
+		case moduleDouble:
+			err = d.skip(8)
+		case moduleString:
+			err = d.skipString()
+		default:
+			return fmt.Errorf("unknown module opcode %d", op)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// moduleCharset are the characters of module type names.
+const moduleCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+// moduleName decodes the 9 characters name of a module type ID, which
+// holds 6 bits per character followed by a 10 bits encoding version.
+func moduleName(id uint64) string {
+	name := make([]byte, 9)
+	id >>= 10
+	for i := len(name) - 1; i >= 0; i-- {
+		name[i] = moduleCharset[id&63]
+		id >>= 6
+	}
+	return string(name)
+}
+
+// ziplistLen returns the entry count in the header of a ziplist, -1 when
+// it overflowed.
+func ziplistLen(b []byte) int64 {
+	if len(b) < 10 {
+		return -1
+	}
+	n := binary.LittleEndian.Uint16(b[8:10])
+	if n == 0xffff {
+		return -1
+	}
+	return int64(n)
+}
+
+// listpackLen returns the element count in the header of a listpack, -1
+// when it overflowed.
+func listpackLen(b []byte) int64 {
+	if len(b) < 6 {
+		return -1
+	}
+	n := binary.LittleEndian.Uint16(b[4:6])
+	if n == 0xffff {
+		return -1
+	}
+	return int64(n)
+}
+
+func (d *Decoder) read(b []byte) error {
+	n, err := io.ReadFull(d.r, b)
+	d.n += int64(n)
+	return err
+}
+
+func (d *Decoder) readByte() (byte, error) {
+	b, err := d.r.ReadByte()
+	if err == nil {
+		d.n++
+	}
+	return b, err
+}
+
+func (d *Decoder) skip(n int64) error {
+	m, err := io.CopyN(ioutil.Discard, d.r, n)
+	d.n += m
+	return err
+}
+
+// encoded string formats, in the low bits of a length byte 0b11xxxxxx
+const (
+	encInt8 = iota
+	encInt16
+	encInt32
+	encLZF
+)
+
+// readLengthOrEncoding reads a length, or the format of an encoded
+// string, which is -1 for a plain length.
+func (d *Decoder) readLengthOrEncoding() (uint64, int, error) {
+	b, err := d.readByte()
+	if err != nil {
+		return 0, -1, err
+	}
+	switch b >> 6 {
+	case 0:
+		return uint64(b & 0x3f), -1, nil
+	case 1:
+		b2, err := d.readByte()
+		return uint64(b&0x3f)<<8 | uint64(b2), -1, err
+	case 2:
+		switch b {
+		case 0x80:
+			buf := make([]byte, 4)
+			err := d.read(buf)
+			return uint64(binary.BigEndian.Uint32(buf)), -1, err
+		case 0x81:
+			buf := make([]byte, 8)
+			err := d.read(buf)
+			return binary.BigEndian.Uint64(buf), -1, err
+		}
+		return 0, -1, fmt.Errorf("invalid length byte %#x", b)
+	}
+	return 0, int(b & 0x3f), nil
+}
+
+func (d *Decoder) readLength() (uint64, error) {
+	n, enc, err := d.readLengthOrEncoding()
+	if err == nil && enc >= 0 {
+		err = fmt.Errorf("string encoding %d where a length was expected", enc)
+	}
+	return n, err
+}
+
+func (d *Decoder) skipLengths(n int) error {
+	for i := 0; i < n; i++ {
+		if _, err := d.readLength(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readString reads a string, decoding integers and LZF compression.
+func (d *Decoder) readString() ([]byte, error) {
+	n, enc, err := d.readLengthOrEncoding()
+	if err != nil {
+		return nil, err
+	}
+	switch enc {
+	case -1:
+		b := make([]byte, n)
+		return b, d.read(b)
+	case encInt8, encInt16, encInt32:
+		b := make([]byte, 1<<uint(enc))
+		if err := d.read(b); err != nil {
+			return nil, err
+		}
+		var v int64
+		switch enc {
+		case encInt8:
+			v = int64(int8(b[0]))
+		case encInt16:
+			v = int64(int16(binary.LittleEndian.Uint16(b)))
+		default:
+			v = int64(int32(binary.LittleEndian.Uint32(b)))
+		}
+		return []byte(strconv.FormatInt(v, 10)), nil
+	case encLZF:
+		clen, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		ulen, err := d.readLength()
+		if err != nil {
+			return nil, err
+		}
+		in := make([]byte, clen)
+		if err := d.read(in); err != nil {
+			return nil, err
+		}
+		return lzfDecompress(in, int(ulen))
+	}
+	return nil, fmt.Errorf("unknown string encoding %d", enc)
+}
+
+// skipString reads past a string without decoding it.
+func (d *Decoder) skipString() error {
+	n, enc, err := d.readLengthOrEncoding()
+	if err != nil {
+		return err
+	}
+	switch enc {
+	case -1:
+		return d.skip(int64(n))
+	case encInt8, encInt16, encInt32:
+		return d.skip(1 << uint(enc))
+	case encLZF:
+		clen, err := d.readLength()
+		if err == nil {
+			_, err = d.readLength()
+		}
+		if err == nil {
+			err = d.skip(int64(clen))
+		}
+		return err
+	}
+	return fmt.Errorf("unknown string encoding %d", enc)
+}
+
+func (d *Decoder) skipStrings(n uint64) error {
+	for i := uint64(0); i < n; i++ {
+		if err := d.skipString(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var errLZF = errors.New("invalid LZF compressed string")
+
+// lzfDecompress expands in, compressed with the LZF of Redis, to n bytes.
+func lzfDecompress(in []byte, n int) ([]byte, error) {
+	out := make([]byte, 0, n)
+	for i := 0; i < len(in); {
+		ctrl := int(in[i])
+		i++
+		if ctrl < 32 {
+			// a run of ctrl+1 literal bytes
+			if i+ctrl+1 > len(in) {
+				return nil, errLZF
+			}
+			out = append(out, in[i:i+ctrl+1]...)
+			i += ctrl + 1
+			continue
+		}
+		// a back reference of length+2 bytes
+		length := ctrl >> 5
+		if length == 7 {
+			if i >= len(in) {
+				return nil, errLZF
+			}
+			length += int(in[i])
+			i++
+		}
+		if i >= len(in) {
+			return nil, errLZF
+		}
+		ref := len(out) - (ctrl&0x1f)<<8 - int(in[i]) - 1
+		i++
+		if ref < 0 {
+			return nil, errLZF
+		}
+		for j := 0; j < length+2; j++ {
+			out = append(out, out[ref+j])
+		}
+	}
+	if len(out) != n {
+		return nil, errLZF
+	}
+	return out, nil
+}
@@ -0,0 +1,351 @@
+package rdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixture builds an RDB file by hand.
+type fixture struct {
+	bytes.Buffer
+	want []Entry
+	db   int
+}
+
+func newFixture(version string) *fixture {
+	f := &fixture{}
+	f.WriteString("REDIS" + version)
+	return f
+}
+
+func (f *fixture) length(n uint64) {
+	switch {
+	case n < 1<<6:
+		f.WriteByte(byte(n))
+	case n < 1<<14:
+		f.WriteByte(byte(n>>8) | 0x40)
+		f.WriteByte(byte(n))
+	case n <= 0xffffffff:
+		f.WriteByte(0x80)
+		binary.Write(f, binary.BigEndian, uint32(n))
+	default:
+		f.WriteByte(0x81)
+		binary.Write(f, binary.BigEndian, n)
+	}
+}
+
+func (f *fixture) str(s string) {
+	f.length(uint64(len(s)))
+	f.WriteString(s)
+}
+
+func (f *fixture) aux(k, v string) {
+	f.WriteByte(opAux)
+	f.str(k)
+	f.str(v)
+}
+
+func (f *fixture) selectDB(db int) {
+	f.WriteByte(opSelectDB)
+	f.length(uint64(db))
+	f.WriteByte(opResizeDB)
+	f.length(10)
+	f.length(1)
+	f.db = db
+}
+
+// key writes a key of type t whose value value writes, and records the
+// entry Next should return for it.
+func (f *fixture) key(t byte, key string, typ string, elements int64, expiry time.Time, value func()) {
+	f.WriteByte(t)
+	f.str(key)
+	start := f.Len()
+	value()
+	f.want = append(f.want, Entry{DB: f.db, Key: key, Type: typ, Elements: elements, Bytes: int64(f.Len() - start), Expiry: expiry})
+}
+
+func (f *fixture) eof() {
+	f.WriteByte(opEOF)
+	f.Write(make([]byte, 8))
+}
+
+// moduleID encodes a module type name and encoding version.
+func moduleID(name string, version uint64) uint64 {
+	var id uint64
+	for i := 0; i < len(name); i++ {
+		id = id<<6 | uint64(strings.IndexByte(moduleCharset, name[i]))
+	}
+	return id<<10 | version
+}
+
+// streamValue writes a stream of type t with one entry node, length
+// entries and a group with a pending entry owned by its consumer.
+func (f *fixture) streamValue(t byte, length uint64) {
+	f.length(1)
+	f.str(strings.Repeat("\x00", 16))
+	f.str("listpack")
+	f.length(length)
+	f.length(5)
+	f.length(0)
+	if t >= 19 {
+		f.length(1)
+		f.length(0)
+		f.length(0)
+		f.length(0)
+		f.length(length)
+	}
+	f.length(1)
+	f.str("group")
+	f.length(5)
+	f.length(0)
+	if t >= 19 {
+		f.length(length)
+	}
+	f.length(1)
+	f.Write(make([]byte, 16+8))
+	f.length(1)
+	f.length(1)
+	f.str("consumer")
+	f.Write(make([]byte, 8))
+	if t >= 21 {
+		f.Write(make([]byte, 8))
+	}
+	f.length(1)
+	f.Write(make([]byte, 16))
+}
+
+func decodeAll(t *testing.T, b []byte) (*Decoder, []Entry, error) {
+	t.Helper()
+	d, err := NewDecoder(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Entry
+	for {
+		e, err := d.Next()
+		if err == io.EOF {
+			return d, got, nil
+		} else if err != nil {
+			return d, got, err
+		}
+		got = append(got, *e)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	f := newFixture("0011")
+	f.aux("redis-ver", "7.2.4")
+	f.WriteByte(opAux)
+	f.str("ctime")
+	f.WriteByte(0xc0 | encInt32)
+	binary.Write(f, binary.LittleEndian, int32(1700000000))
+
+	// a module auxiliary field comes before the keys
+	f.WriteByte(opModuleAux)
+	f.length(moduleID("graphdata", 1))
+	f.length(moduleUInt)
+	f.length(2) // when: after the keys
+	f.length(moduleUInt)
+	f.length(7)
+	f.length(moduleString)
+	f.str("meta")
+	f.length(moduleDouble)
+	f.Write(make([]byte, 8))
+	f.length(moduleEOF)
+
+	f.selectDB(0)
+	var none time.Time
+	f.key(0, "plain", "string", 1, none, func() { f.str("hello") })
+	f.key(0, "int", "string", 1, none, func() {
+		f.WriteByte(0xc0 | encInt16)
+		binary.Write(f, binary.LittleEndian, int16(-300))
+	})
+	f.key(0, "lzf", "string", 1, none, func() {
+		// "ab" then a back reference copying 6 bytes: abababab
+		f.WriteByte(0xc0 | encLZF)
+		f.length(5)
+		f.length(8)
+		f.Write([]byte{1, 'a', 'b', 4 << 5, 1})
+	})
+	f.key(1, "list", "list", 3, none, func() {
+		f.length(3)
+		f.str("a")
+		f.str("b")
+		f.str("c")
+	})
+	f.key(2, "set", "set", 2, none, func() {
+		f.length(2)
+		f.str("x")
+		f.str("y")
+	})
+	f.key(3, "zset", "zset", 3, none, func() {
+		f.length(3)
+		f.str("m1")
+		f.str("1.5")
+		f.str("m2")
+		f.WriteByte(254) // +inf
+		f.str("m3")
+		f.WriteByte(253) // nan
+	})
+	f.key(5, "zset2", "zset", 1, none, func() {
+		f.length(1)
+		f.str("m")
+		binary.Write(f, binary.LittleEndian, 2.5)
+	})
+	f.key(4, "hash", "hash", 2, none, func() {
+		f.length(2)
+		f.str("f1")
+		f.str("v1")
+		f.str("f2")
+		f.str("v2")
+	})
+
+	// expiry opcodes apply to the next key only
+	inSecs := time.Unix(1800000000, 0)
+	f.WriteByte(opExpireTime)
+	binary.Write(f, binary.LittleEndian, uint32(1800000000))
+	f.key(0, "expires", "string", 1, inSecs, func() { f.str("v") })
+	inMS := time.Unix(0, 1800000000123*int64(time.Millisecond))
+	f.WriteByte(opExpireTimeMS)
+	binary.Write(f, binary.LittleEndian, uint64(1800000000123))
+	f.WriteByte(opIdle)
+	f.length(100)
+	f.key(0, "expires-ms", "string", 1, inMS, func() { f.str("v") })
+	f.WriteByte(opFreq)
+	f.WriteByte(5)
+	f.key(0, "after", "string", 1, none, func() { f.str("v") })
+
+	f.selectDB(3)
+	f.key(15, "stream", "stream", 4, none, func() { f.streamValue(15, 4) })
+	f.key(19, "stream7", "stream", 2, none, func() { f.streamValue(19, 2) })
+	f.key(21, "stream72", "stream", 6, none, func() { f.streamValue(21, 6) })
+	f.key(7, "graph", "module:graphdata", -1, none, func() {
+		f.length(moduleID("graphdata", 2))
+		f.length(moduleSInt)
+		f.length(42)
+		f.length(moduleFloat)
+		f.Write(make([]byte, 4))
+		f.length(moduleString)
+		f.str("node")
+		f.length(moduleEOF)
+	})
+	f.eof()
+
+	d, got, err := decodeAll(t, f.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Version != 11 {
+		t.Errorf("version %d, want 11", d.Version)
+	}
+	if d.Aux["redis-ver"] != "7.2.4" || d.Aux["ctime"] != "1700000000" {
+		t.Errorf("aux fields %v", d.Aux)
+	}
+	if len(got) != len(f.want) {
+		t.Fatalf("%d keys, want %d: %+v", len(got), len(f.want), got)
+	}
+	for i, e := range got {
+		w := f.want[i]
+		if e.DB != w.DB || e.Key != w.Key || e.Type != w.Type || e.Elements != w.Elements || e.Bytes != w.Bytes || !e.Expiry.Equal(w.Expiry) {
+			t.Errorf("key %d: got %+v, want %+v", i, e, w)
+		}
+	}
+}
+
+func TestReadString(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want string
+	}{
+		{[]byte{3, 'a', 'b', 'c'}, "abc"},
+		{[]byte{0xc0, 0xfe}, "-2"},
+		{[]byte{0xc1, 0x39, 0x30}, "12345"},
+		{[]byte{0xc2, 0x00, 0x00, 0x00, 0x80}, "-2147483648"},
+		{[]byte{0xc3, 5, 8, 1, 'a', 'b', 4 << 5, 1}, "abababab"},
+		// a back reference of 7+2+2 bytes
+		{[]byte{0xc3, 5, 12, 0, 'x', 7 << 5, 2, 0}, "xxxxxxxxxxxx"},
+	}
+	for _, tt := range tests {
+		d := &Decoder{r: bufio.NewReader(bytes.NewReader(tt.in))}
+		got, err := d.readString()
+		if err != nil {
+			t.Errorf("%v: %v", tt.in, err)
+		} else if string(got) != tt.want {
+			t.Errorf("%v: got %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLZFInvalid(t *testing.T) {
+	for _, in := range [][]byte{
+		{5, 'a'},         // literal run past the end
+		{1 << 5, 0},      // back reference before the start
+		{0, 'a', 7 << 5}, // long back reference cut short
+	} {
+		if _, err := lzfDecompress(in, 4); err != errLZF {
+			t.Errorf("%v: got %v, want errLZF", in, err)
+		}
+	}
+	if _, err := lzfDecompress([]byte{0, 'a'}, 2); err != errLZF {
+		t.Errorf("wrong length: got %v, want errLZF", err)
+	}
+}
+
+func TestTruncated(t *testing.T) {
+	f := newFixture("0011")
+	f.selectDB(0)
+	f.key(0, "a", "string", 1, time.Time{}, func() { f.str("first") })
+	f.key(1, "b", "list", 2, time.Time{}, func() {
+		f.length(2)
+		f.str("x")
+		f.str("y")
+	})
+	f.eof()
+	full := f.Bytes()
+
+	// cut in the middle of the list, then right after it before the EOF
+	for _, cut := range []int{len(full) - 12, len(full) - 9} {
+		_, got, err := decodeAll(t, full[:cut])
+		if err == nil {
+			t.Errorf("cut at %d: no error", cut)
+			continue
+		}
+		if !strings.Contains(err.Error(), io.ErrUnexpectedEOF.Error()) {
+			t.Errorf("cut at %d: got error %v, want an unexpected EOF", cut, err)
+		}
+		if len(got) == 0 || got[0].Key != "a" {
+			t.Errorf("cut at %d: got keys %+v, want the first key", cut, got)
+		}
+	}
+}
+
+func TestHeader(t *testing.T) {
+	for _, in := range []string{"", "REDIS", "RDB000011", "REDISabcd"} {
+		if _, err := NewDecoder(strings.NewReader(in)); err == nil {
+			t.Errorf("%q: no error", in)
+		}
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	f := newFixture("0010")
+	f.WriteByte(6)
+	f.str("old-module")
+	_, _, err := decodeAll(t, f.Bytes())
+	if err == nil || !strings.Contains(err.Error(), "before Redis 5") {
+		t.Errorf("got %v, want the modules before Redis 5 error", err)
+	}
+}
+
+func TestModuleName(t *testing.T) {
+	for _, name := range []string{"graphdata", "ReJSON-RL", "MBbloom--", "tdis-type"} {
+		if got := moduleName(moduleID(name, 3)); got != name {
+			t.Errorf("got %q, want %q", got, name)
+		}
+	}
+}
@@ -7,7 +7,7 @@ then
 fi
 echo "use latest tag $latest"
 
-export GOOS=linux; export GOARCH=amd64; go build -o bin/redis-cli-"$latest"-"$GOOS"-"$GOARCH" github.com/holys/redis-cli
-export GOOS=darwin; export GOARCH=amd64; go build -o bin/redis-cli-"$latest"-"$GOOS"-"$GOARCH" github.com/holys/redis-cli
-export GOOS=windows; export GOARCH=amd64; go build -o bin/redis-cli-"$latest"-"$GOOS"-"$GOARCH" github.com/holys/redis-cli
+export GOOS=linux; export GOARCH=amd64; go build -o bin/redis-cli-"$latest"-"$GOOS"-"$GOARCH" github.com/holys/redis-cli/cmd/redis-cli
+export GOOS=darwin; export GOARCH=amd64; go build -o bin/redis-cli-"$latest"-"$GOOS"-"$GOARCH" github.com/holys/redis-cli/cmd/redis-cli
+export GOOS=windows; export GOARCH=amd64; go build -o bin/redis-cli-"$latest"-"$GOOS"-"$GOARCH" github.com/holys/redis-cli/cmd/redis-cli
 echo "build release done"
\ No newline at end of file